@@ -2,7 +2,8 @@ package main
 
 import (
 	"github.com/GuessWhoSamFoo/gang-gang-bot/internal"
-	"log"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/logging"
+	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
@@ -11,24 +12,31 @@ import (
 func main() {
 	config, err := internal.NewConfig()
 	if err != nil {
-		log.Fatalln("Failed to get config file")
+		slog.Error("Failed to get config file", logging.ErrorKey, err)
+		os.Exit(1)
+	}
+	if err = logging.Setup(config.Log.Level); err != nil {
+		slog.Error("Failed to setup logging", logging.ErrorKey, err)
+		os.Exit(1)
 	}
 	bot, err := internal.NewBot(config)
 	if err != nil {
-		log.Fatalln("Failed initialize bot")
+		slog.Error("Failed initialize bot", logging.ErrorKey, err)
+		os.Exit(1)
 	}
 	defer func() {
 		if err := bot.Close(); err != nil {
-			log.Fatalf("cannot cleanup session: %v", err)
+			slog.Error("cannot cleanup session", logging.ErrorKey, err)
 		}
 	}()
 
 	if err := bot.Start(); err != nil {
-		log.Fatalf("cannot start bot: %v", err)
+		slog.Error("cannot start bot", logging.ErrorKey, err)
+		return
 	}
 
-	stop := make(chan os.Signal)
-	signal.Notify(stop, syscall.SIGINT, syscall.SIGKILL, os.Interrupt, os.Kill)
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM, os.Interrupt)
 	<-stop
-	log.Println("Goodbye")
+	slog.Info("Goodbye")
 }
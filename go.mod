@@ -1,7 +1,7 @@
 module github.com/GuessWhoSamFoo/gang-gang-bot
 
-// +heroku goVersion go1.19
-go 1.19
+// +heroku goVersion go1.21
+go 1.21
 
 require (
 	github.com/GuessWhoSamFoo/fsm v0.4.0
@@ -9,6 +9,7 @@ require (
 	github.com/bwmarrin/discordgo v0.26.1
 	github.com/ewohltman/discordgo-mock v0.0.7
 	github.com/lithammer/fuzzysearch v1.1.5
+	github.com/prometheus/client_golang v1.14.0
 	github.com/stretchr/testify v1.7.0
 	github.com/tj/go-naturaldate v1.3.0
 	golang.org/x/exp v0.0.0-20220613132600-b0d781184e0d
@@ -18,7 +19,9 @@ require (
 
 require (
 	cloud.google.com/go/compute v1.10.0 // indirect
-	github.com/davecgh/go-spew v1.1.0 // indirect
+	github.com/beorn7/perks v1.0.1 // indirect
+	github.com/cespare/xxhash/v2 v2.1.2 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/golang/groupcache v0.0.0-20200121045136-8c9f03a8e57e // indirect
 	github.com/golang/protobuf v1.5.2 // indirect
 	github.com/google/uuid v1.3.0 // indirect
@@ -26,7 +29,11 @@ require (
 	github.com/googleapis/gax-go/v2 v2.6.0 // indirect
 	github.com/gorilla/mux v1.8.0 // indirect
 	github.com/gorilla/websocket v1.4.2 // indirect
+	github.com/matttproud/golang_protobuf_extensions v1.0.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/prometheus/client_model v0.3.0 // indirect
+	github.com/prometheus/common v0.37.0 // indirect
+	github.com/prometheus/procfs v0.8.0 // indirect
 	go.opencensus.io v0.23.0 // indirect
 	golang.org/x/crypto v0.0.0-20210421170649-83a5a9bb288b // indirect
 	golang.org/x/net v0.0.0-20221014081412-f15817d10f9b // indirect
@@ -0,0 +1,65 @@
+package internal
+
+import (
+	"fmt"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/discord"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/errs"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/i18n"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/logging"
+	"github.com/bwmarrin/discordgo"
+)
+
+// printer renders messages in the language and format preferred by the user of an interaction
+func (sm *StateManager) printer(i *discordgo.InteractionCreate) *discord.Printer {
+	p := &discord.Printer{Localizer: i18n.FromInteraction(i, sm.Config.Discord.Locale)}
+	if user := logging.InteractionUser(i); user != nil {
+		p.Accessible = sm.Store.Preferences(user.ID).Accessible
+	}
+	return p
+}
+
+// userPrinter renders messages sent to a user outside an interaction, such as by scheduled jobs. The locale comes
+// first, then the one the user last used the bot in.
+func (sm *StateManager) userPrinter(userID, locale string) *discord.Printer {
+	prefs := sm.Store.Preferences(userID)
+	return &discord.Printer{
+		Localizer:  i18n.NewLocalizer(discordgo.Locale(locale), discordgo.Locale(prefs.Locale), discordgo.Locale(sm.Config.Discord.Locale)),
+		Accessible: prefs.Accessible,
+	}
+}
+
+// memberPrinter renders messages sent to a member about something another user did
+func (sm *StateManager) memberPrinter(userID string) *discord.Printer {
+	return sm.userPrinter(userID, "")
+}
+
+// AccessibilityHandler turns accessibility mode on or off for the user
+func (sm *StateManager) AccessibilityHandler(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	if sm.Store == nil {
+		return fmt.Errorf("preference store is not configured")
+	}
+	user := logging.InteractionUser(i)
+	if user == nil {
+		return fmt.Errorf("cannot find user")
+	}
+	prefs := sm.Store.Preferences(user.ID)
+	for _, opt := range i.ApplicationCommandData().Options {
+		if opt.Name == "enabled" {
+			prefs.Accessible = opt.BoolValue()
+		}
+	}
+	if err := sm.Store.SetPreferences(user.ID, prefs); err != nil {
+		return fmt.Errorf("cannot save preferences: %w", err)
+	}
+
+	embed := discord.AccessibilityOffMessage
+	if prefs.Accessible {
+		embed = discord.AccessibilityOnMessage
+	}
+	if _, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Embeds: &[]*discordgo.MessageEmbed{sm.printer(i).Embed(&embed)},
+	}); err != nil {
+		return errs.NewUpstream("failed to edit response", err)
+	}
+	return nil
+}
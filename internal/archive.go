@@ -0,0 +1,134 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/discord"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/logging"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/metrics"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/pkg/util"
+	"github.com/bwmarrin/discordgo"
+	"log/slog"
+	"time"
+)
+
+const (
+	archiveInterval = 5 * time.Minute
+	// archiveLookback is how long after ending an event is still archived, such as when the bot was offline
+	archiveLookback = 7 * 24 * time.Hour
+)
+
+// RunArchiver archives events as they end until the context is canceled
+func (sm *StateManager) RunArchiver(ctx context.Context, s *discordgo.Session) {
+	ticker := time.NewTicker(archiveInterval)
+	defer ticker.Stop()
+	for {
+		sm.archiveEndedEvents(s, time.Now())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// archiveEndedEvents archives every recently ended event linked from the calendar
+func (sm *StateManager) archiveEndedEvents(s *discordgo.Session, now time.Time) {
+	logger := slog.With("job", "archive")
+	events, err := sm.CalendarClient.ListEventsBetween(now.Add(-archiveLookback), now)
+	if err != nil {
+		logger.Error("cannot list events from Google calendar", logging.ErrorKey, err)
+		return
+	}
+	for _, e := range events {
+		link, err := util.GetDiscordLinkFromCalendarDescription(e.Description)
+		if err != nil {
+			continue
+		}
+		guildID, channelID, messageID, err := util.GetIDsFromDiscordLink(link)
+		if err != nil {
+			logger.Warn("skipping calendar event with invalid discord link", "calendarEvent", e.Id, logging.ErrorKey, err)
+			continue
+		}
+		if err = sm.archiveEvent(s, guildID, channelID, messageID, now); err != nil {
+			if !errors.Is(err, errUnchanged) {
+				logger.Error("cannot archive event", logging.EventKey, logging.EventPath(channelID, messageID), logging.ErrorKey, err)
+			}
+			continue
+		}
+		logger.Info("Archived event", logging.EventKey, logging.EventPath(channelID, messageID))
+	}
+}
+
+// archiveEvent records the final attendance of an ended event, marks it as ended and disables its buttons in the same
+// edit. Its thread is then archived and a copy is posted to the archive channel when one is configured. Those steps are
+// retried on later runs until they succeed, since the event itself is already marked as ended.
+func (sm *StateManager) archiveEvent(s *discordgo.Session, guildID, channelID, messageID string, now time.Time) error {
+	components := discord.DisabledEventComponents(sm.Config.Discord.TextButtons)
+	event, err := sm.updateEventComponents(s, guildID, channelID, messageID, components, func(e *discord.Event) error {
+		if e.Archived || !e.Ended(now) {
+			return errUnchanged
+		}
+		if sm.Store != nil {
+			a := discord.ToAttendance(e, channelID, messageID, now)
+			a.ArchivePending = true
+			if err := sm.Store.RecordAttendance(a); err != nil {
+				return fmt.Errorf("cannot record attendance: %w", err)
+			}
+		}
+		e.Archived = true
+		return nil
+	})
+	if errors.Is(err, errUnchanged) && sm.archivePending(messageID) {
+		if event, err = getEvent(s, guildID, channelID, messageID); err != nil {
+			return err
+		}
+		if !event.Archived {
+			return errUnchanged
+		}
+		return sm.finishArchive(s, event, channelID, messageID)
+	}
+	if err != nil {
+		return err
+	}
+	metrics.EventsArchived.Inc()
+	return sm.finishArchive(s, event, channelID, messageID)
+}
+
+// archivePending reports whether an archived event still needs its thread archived or its copy posted
+func (sm *StateManager) archivePending(messageID string) bool {
+	if sm.Store == nil {
+		return false
+	}
+	a, ok := sm.Store.Attendance(messageID)
+	return ok && a.ArchivePending
+}
+
+// finishArchive archives the thread of an ended event and posts a copy to the archive channel
+func (sm *StateManager) finishArchive(s *discordgo.Session, event *discord.Event, channelID, messageID string) error {
+	if err := event.ArchiveThread(s); err != nil {
+		return fmt.Errorf("failed to archive thread: %w", err)
+	}
+
+	archiveChannelID := sm.Config.Discord.ArchiveChannelID
+	if archiveChannelID != "" && archiveChannelID != channelID {
+		embed, err := discord.ConvertEventToMessageEmbed(event)
+		if err != nil {
+			return err
+		}
+		if _, err = s.ChannelMessageSendComplex(archiveChannelID, &discordgo.MessageSend{
+			Content: fmt.Sprintf("[Original post](%s)", event.DiscordLink),
+			Embeds:  []*discordgo.MessageEmbed{embed},
+		}); err != nil {
+			return fmt.Errorf("failed to post to archive channel: %w", err)
+		}
+	}
+	if sm.Store == nil {
+		return nil
+	}
+	if err := sm.Store.FinishArchive(messageID); err != nil {
+		return fmt.Errorf("cannot record finished archive: %w", err)
+	}
+	return nil
+}
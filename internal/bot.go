@@ -2,12 +2,19 @@ package internal
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/discord"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/i18n"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/logging"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/metrics"
 	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/services"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/store"
 	"github.com/GuessWhoSamFoo/gang-gang-bot/pkg/util"
 	"github.com/bwmarrin/discordgo"
-	"log"
+	"log/slog"
+	"net/http"
+	"os"
 	"time"
 )
 
@@ -15,6 +22,9 @@ type Bot struct {
 	Session    *discordgo.Session
 	CommandMap map[string]string // id:name
 	Config     *Config
+	Server     *http.Server
+
+	stopJobs context.CancelFunc
 }
 
 func NewBot(c *Config) (*Bot, error) {
@@ -38,6 +48,20 @@ func (b *Bot) Start() error {
 		return err
 	}
 	sm.CalendarClient = c
+	sm.Categories, err = b.Config.EventCategories()
+	if err != nil {
+		return err
+	}
+	c.Categories = sm.Categories
+
+	sm.Store, err = store.Open(b.Config.Store.Path)
+	if err != nil {
+		return err
+	}
+	// Heroku sets DYNO on its dynos, whose filesystem is reset on every restart
+	if os.Getenv("DYNO") != "" {
+		slog.Warn("store is on an ephemeral filesystem and will be lost when the dyno restarts", "path", b.Config.Store.Path)
+	}
 
 	b.Session, err = services.NewDiscordSession(b.Config.Secret.Token)
 	if err != nil {
@@ -51,27 +75,62 @@ func (b *Bot) Start() error {
 	time.Local = l
 	b.Session.AddHandler(services.ReadyEvent)
 	b.Session.AddHandler(func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		logger := logging.FromInteraction(i)
 		switch i.Type {
 		case discordgo.InteractionApplicationCommand:
-			if h, ok := sm.CommandHandlers[i.ApplicationCommandData().Name]; ok {
-				h(s, i)
+			name := i.ApplicationCommandData().Name
+			metrics.Interactions.WithLabelValues("command", name).Inc()
+			if h, ok := sm.CommandHandlers[name]; ok {
+				sm.Build(h)(s, i)
 			} else {
-				log.Fatalln("cannot add command handler")
+				sm.Build(Handler{Handle: unknownHandler("command", name)})(s, i)
 			}
 		case discordgo.InteractionMessageComponent:
-			if h, ok := sm.ComponentHandlers[i.MessageComponentData().CustomID]; ok {
-				h(s, i)
+			// Custom IDs may carry a payload after the prefix used for routing
+			prefix := discord.ComponentPrefix(i.MessageComponentData().CustomID)
+			metrics.Interactions.WithLabelValues("component", prefix).Inc()
+			if h, ok := sm.ComponentHandlers[prefix]; ok {
+				sm.Build(h)(s, i)
 			} else {
-				log.Fatalln("cannot add component handler")
+				sm.Build(Handler{Handle: unknownHandler("component", prefix)})(s, i)
 			}
 		default:
-			log.Println("unknown handler type")
+			logger.Warn("unknown handler type", "type", i.Type.String())
 		}
 	})
+	if b.Config.Discord.SyncInterested {
+		b.Session.AddHandler(sm.InterestedAddHandler)
+		b.Session.AddHandler(sm.InterestedRemoveHandler)
+	}
+	if b.Config.Discord.ImportEvents {
+		b.Session.AddHandler(sm.ScheduledEventCreateHandler)
+		b.Session.AddHandler(sm.ScheduledEventUpdateHandler)
+		b.Session.AddHandler(sm.ScheduledEventDeleteHandler)
+	}
+	b.Server = services.NewServer(b.Config.Server.Address, services.NewHealth(b.Session))
+	go func() {
+		if err := b.Server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("metrics server stopped", logging.ErrorKey, err)
+		}
+	}()
+
 	if err := b.Session.Open(); err != nil {
 		return fmt.Errorf("cannot open session: %v", err)
 	}
 
+	var jobs context.Context
+	jobs, b.stopJobs = context.WithCancel(ctx)
+	go sm.RunArchiver(jobs, b.Session)
+	go sm.RunPublisher(jobs, b.Session)
+	if b.Config.Store.RetentionDays > 0 {
+		go sm.RunPruner(jobs)
+	}
+	if b.Config.Rules.LateCancelLimit > 0 {
+		go sm.RunWaitlistRelease(jobs, b.Session)
+	}
+
+	addCategoryChoices(Commands, sm.Categories)
+	i18n.LocalizeCommands(Commands)
 	for _, v := range Commands {
 		c, err := b.Session.ApplicationCommandCreate(b.Session.State.User.ID, b.Config.Discord.GuildID, v)
 		if err != nil {
@@ -86,10 +145,18 @@ func (b *Bot) Close() error {
 	if b.Session == nil {
 		return fmt.Errorf("nil session")
 	}
+	if b.stopJobs != nil {
+		b.stopJobs()
+	}
+	if b.Server != nil {
+		if err := b.Server.Shutdown(context.Background()); err != nil {
+			slog.Error("cannot stop metrics server", logging.ErrorKey, err)
+		}
+	}
 	for id, name := range b.CommandMap {
-		log.Println("removing command /" + name)
+		slog.Info("removing command", "command", name)
 		if err := b.Session.ApplicationCommandDelete(b.Session.State.User.ID, b.Config.Discord.GuildID, id); err != nil {
-			log.Fatalf("Cannot delete slash command %q: %v", name, err)
+			slog.Error("cannot delete slash command", "command", name, logging.ErrorKey, err)
 		}
 	}
 	return b.Session.Close()
@@ -2,14 +2,19 @@ package internal
 
 import (
 	"context"
+	"fmt"
 	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands"
 	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states"
 	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/discord"
 	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/role"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/errs"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/logging"
 	"github.com/GuessWhoSamFoo/gang-gang-bot/pkg"
 	"github.com/GuessWhoSamFoo/gang-gang-bot/pkg/util"
 	"github.com/bwmarrin/discordgo"
-	"log"
+	"google.golang.org/api/calendar/v3"
+	"log/slog"
+	"strings"
 )
 
 var (
@@ -19,14 +24,103 @@ var (
 			Description:              "Create a new event",
 			DefaultMemberPermissions: &pkg.EventPermission,
 			DMPermission:             &pkg.DMPermission,
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:         discordgo.ApplicationCommandOptionChannel,
+					Name:         "channel",
+					Description:  "Channel to post the event in. Defaults to the events channel",
+					ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildText, discordgo.ChannelTypeGuildNews},
+				},
+			},
 		},
 		{
 			Name:        "my_events",
 			Description: "View a list of upcoming events you've organized or signed up for",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "category",
+					Description: "Only list events in this category",
+				},
+			},
 		},
 		{
 			Name:        "upcoming_events",
 			Description: "View a list of upcoming events",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "category",
+					Description: "Only list events in this category",
+				},
+			},
+		},
+		{
+			Name:                     "import_events",
+			Description:              "Publish signup posts for Discord events created outside the bot",
+			DefaultMemberPermissions: &pkg.EventPermission,
+		},
+		{
+			Name:        "stats",
+			Description: "View attendance history for yourself or another member",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionUser,
+					Name:        "member",
+					Description: "Member to view. Defaults to you",
+				},
+			},
+		},
+		{
+			Name:        "announce",
+			Description: "Message the members who signed up for one of your events",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "event",
+					Description: "Link to the event post",
+					Required:    true,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "message",
+					Description: "What to tell them",
+					Required:    true,
+					MaxLength:   discord.MaxAnnouncementLength,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionBoolean,
+					Name:        "accepted",
+					Description: "Message accepted members. Defaults to true",
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionBoolean,
+					Name:        "tentative",
+					Description: "Message tentative members",
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionBoolean,
+					Name:        "waitlist",
+					Description: "Message waitlisted members",
+				},
+			},
+		},
+		{
+			Name:                     "drafts",
+			Description:              "View your event drafts and scheduled posts",
+			DefaultMemberPermissions: &pkg.EventPermission,
+		},
+		{
+			Name:        "accessibility",
+			Description: "Write menus, lists and times out in plain text for screen readers",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionBoolean,
+					Name:        "enabled",
+					Description: "Turn accessibility mode on or off",
+					Required:    true,
+				},
+			},
 		},
 		//{
 		//	Name:        "edit",
@@ -35,200 +129,337 @@ var (
 	}
 )
 
-func (sm *StateManager) CreateEventHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	if sm.HasUser(i.Member.User.ID) {
-		discord.NotifyCommandInProgress(s, i)
-		return
+func (sm *StateManager) CreateEventHandler(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	logger := logging.FromInteraction(i)
+	opts := sm.createOptions(s, i)
+	for _, opt := range i.ApplicationCommandData().Options {
+		if opt.Name == "channel" {
+			opts.ChannelID = opt.ChannelValue(nil).ID
+		}
+	}
+	// A channel chosen with the command is checked before the DM sequence rather than after the organizer filled
+	// everything in. Otherwise the channel depends on the category and is checked before posting.
+	if opts.ChannelID != "" {
+		if err := discord.CheckCanPost(s, opts.ChannelID); err != nil {
+			return err
+		}
 	}
-	sm.AddUser(i.Member.User.ID)
-	defer sm.RemoveUser(i.Member.User.ID)
 
 	c, err := s.UserChannelCreate(i.Member.User.ID)
 	if err != nil {
-		log.Printf("cannot create channel: %v", err)
-		return
+		return errs.NewUpstream("cannot create channel", err)
 	}
+	opts.Channel = c
 	ctx := context.Background()
-	opts := discord.Options{
+
+	f, err := NewDefaultStateFactory(opts).Factory(commands.CreateType)
+	if err != nil {
+		return err
+	}
+
+	// Every step of a resumed draft is already answered, so it goes straight to the preview, which publishes the event
+	// or saves it as a draft
+	if err := runSequence(ctx, f, states.StartCreate.String(), commands.CreateNext); err != nil {
+		return sequenceError(logger, f, err)
+	}
+	return nil
+}
+
+// createOptions are the options of the DM sequence creating an event for the user of an interaction
+func (sm *StateManager) createOptions(s *discordgo.Session, i *discordgo.InteractionCreate) discord.Options {
+	return discord.Options{
 		Session:           s,
 		InteractionCreate: i,
-		Channel:           c,
+		Store:             sm.Store,
+		EventThreads:      sm.Config.Discord.EventThreads,
+		TextButtons:       sm.Config.Discord.TextButtons,
+		EventsChannelID:   sm.Config.Discord.EventsChannelID,
+		CategoryChannels:  sm.Config.Discord.CategoryChannels,
+		Categories:        sm.Categories,
+		Printer:           sm.printer(i),
+		Timeouts:          sm.Config.PromptTimeouts(),
 		CalendarClient:    sm.CalendarClient,
 	}
+}
 
-	f, err := NewDefaultStateFactory(opts).Factory(commands.CreateType)
+func (sm *StateManager) ListMyEventsHandler(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	events, err := sm.listLinkedEvents(s, i)
 	if err != nil {
-		return
+		return err
+	}
+
+	var desc string
+	category := categoryOption(i)
+	for _, event := range events {
+		if !inCategory(event, category) {
+			continue
+		}
+		if event.Owner == i.Member.User.Username || event.RoleGroup.HasUser(i.Member.User.Username, role.AcceptedField) {
+			desc += util.PrintEventListItem(event.Start, event.Title, event.DiscordLink)
+		}
+	}
+
+	if desc == "" {
+		desc = "No events found!"
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "My Events",
+		Color:       discord.Purple,
+		Description: desc,
+	}
+	if sm.Store != nil {
+		if stats := sm.Store.MemberStats(i.Member.User.Username); len(stats.Events) > 0 {
+			embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+				Name:  "Attendance",
+				Value: discord.PrintAttendanceSummary(stats) + "\nUse `/stats` to see past events",
+			})
+		}
 	}
 
-	if err := f.Event(ctx, states.StartCreate.String()); err != nil {
-		log.Println(err)
-		return
+	if _, err = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Embeds: &[]*discordgo.MessageEmbed{sm.printer(i).Embed(embed)},
+	}); err != nil {
+		return errs.NewUpstream("failed to edit response", err)
 	}
-	if err := f.Event(ctx, states.AddTitle.String()); err != nil {
-		log.Println(err)
-		return
+	return nil
+}
+
+// StatsHandler shows the attendance history of the user or the member passed as an option
+func (sm *StateManager) StatsHandler(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	if sm.Store == nil {
+		return fmt.Errorf("attendance store is not configured")
 	}
-	if err := f.Event(ctx, states.AddDescription.String()); err != nil {
-		log.Println(err)
-		return
+	name := i.Member.User.Username
+	data := i.ApplicationCommandData()
+	for _, opt := range data.Options {
+		if opt.Name != "member" || data.Resolved == nil {
+			continue
+		}
+		if u, ok := data.Resolved.Users[fmt.Sprint(opt.Value)]; ok {
+			name = u.Username
+		}
 	}
-	if err := f.Event(ctx, states.SetAttendeeLimit.String()); err != nil {
-		log.Println(err)
-		return
+
+	embed := discord.MemberStatsEmbed(i.GuildID, name, sm.Store.MemberStats(name))
+	if _, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Embeds: &[]*discordgo.MessageEmbed{sm.printer(i).Embed(embed)},
+	}); err != nil {
+		return errs.NewUpstream("failed to edit response", err)
 	}
-	if err := f.Event(ctx, states.SetDate.String()); err != nil {
-		log.Println(err)
-		return
+	return nil
+}
+
+func (sm *StateManager) ListUpcomingEventsHandler(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	events, err := sm.listLinkedEvents(s, i)
+	if err != nil {
+		return err
 	}
-	if err := f.Event(ctx, states.SetLocation.String()); err != nil {
-		log.Println(err)
-		return
+
+	var desc string
+	category := categoryOption(i)
+	for _, event := range events {
+		if inCategory(event, category) {
+			desc += util.PrintEventListItem(event.Start, event.Title, event.DiscordLink)
+		}
 	}
-	if err := f.Event(ctx, states.SetDuration.String()); err != nil {
-		log.Println(err)
-		return
+
+	if desc == "" {
+		desc = "No events found!"
 	}
-	if err := f.Event(ctx, states.CreateEvent.String()); err != nil {
-		log.Println(err)
-		return
+
+	if _, err = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Embeds: &[]*discordgo.MessageEmbed{
+			sm.printer(i).Embed(&discordgo.MessageEmbed{
+				Title:       "Upcoming Events",
+				Color:       discord.Purple,
+				Description: desc,
+			}),
+		},
+	}); err != nil {
+		return errs.NewUpstream("failed to edit response", err)
 	}
+	return nil
 }
 
-func (sm *StateManager) ListMyEventsHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	if sm == nil {
-		log.Printf("commands manager is nil")
-		return
+// categoryOption gets the category listed events are filtered by, if any
+func categoryOption(i *discordgo.InteractionCreate) string {
+	for _, opt := range i.ApplicationCommandData().Options {
+		if opt.Name == "category" {
+			return opt.StringValue()
+		}
 	}
-	if sm.CalendarClient == nil {
-		log.Println("calendar client is nil")
-		return
+	return ""
+}
+
+// inCategory reports whether an event belongs to a category. Every event matches an empty category.
+func inCategory(event *discord.Event, category string) bool {
+	return category == "" || strings.EqualFold(event.Category.Name, category)
+}
+
+// addCategoryChoices offers the configured categories for command options named "category"
+func addCategoryChoices(commands []*discordgo.ApplicationCommand, categories []discord.Category) {
+	var choices []*discordgo.ApplicationCommandOptionChoice
+	for _, c := range categories {
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+			Name:  c.String(),
+			Value: c.Name,
+		})
 	}
-	if i.Member == nil {
-		log.Println("cannot find user")
-		return
+	for _, cmd := range commands {
+		for _, opt := range cmd.Options {
+			if opt.Name == "category" {
+				opt.Choices = choices
+			}
+		}
 	}
+}
 
+// listLinkedEvents gets upcoming calendar events from their Discord posts. Events which cannot be read are skipped so
+// one bad post does not hide the rest.
+func (sm *StateManager) listLinkedEvents(s *discordgo.Session, i *discordgo.InteractionCreate) ([]*discord.Event, error) {
 	events, err := sm.CalendarClient.ListEvents()
 	if err != nil {
-		log.Printf("cannot get events from calendar: %v", err)
-		return
+		return nil, errs.NewUpstream("cannot list events from Google calendar", err)
 	}
+	return linkedEvents(s, logging.FromInteraction(i), events), nil
+}
 
-	var desc string
+// linkedEvents gets calendar events from their Discord posts, skipping events which cannot be read
+func linkedEvents(s *discordgo.Session, logger *slog.Logger, events []*calendar.Event) []*discord.Event {
+	var linked []*discord.Event
 	for _, e := range events {
 		link, err := util.GetDiscordLinkFromCalendarDescription(e.Description)
 		if err != nil {
-			log.Printf("failed to get discord link from calendar: %v", err)
-			return
+			logger.Warn("skipping calendar event without discord link", "calendarEvent", e.Id, logging.ErrorKey, err)
+			continue
 		}
 		_, channelID, messageID, err := util.GetIDsFromDiscordLink(link)
 		if err != nil {
-			log.Printf("failed to get id: %v", err)
-			return
+			logger.Warn("skipping calendar event with invalid discord link", "calendarEvent", e.Id, logging.ErrorKey, err)
+			continue
 		}
 
 		msg, err := s.ChannelMessage(channelID, messageID)
 		if err != nil {
-			log.Printf("failed to get message: %v", err)
-			return
+			logger.Warn("skipping calendar event with missing message", logging.EventKey, logging.EventPath(channelID, messageID), logging.ErrorKey, err)
+			continue
 		}
 
 		event, err := discord.GetEventFromMessage(msg)
 		if err != nil {
-			log.Printf("failed to convert message: %v", err)
-			return
+			logger.Warn("skipping unreadable event", logging.EventKey, logging.EventPath(channelID, messageID), logging.ErrorKey, err)
+			continue
 		}
+		event.DiscordLink = link
+		linked = append(linked, event)
+	}
+	return linked
+}
 
-		if event.Owner == i.Member.User.Username || event.RoleGroup.HasUser(i.Member.User.Username, role.AcceptedField) {
-			desc += util.PrintEventListItem(event.Start, event.Title, link)
+// AnnounceHandler messages the members who responded to an event. Only the organizer or members who can manage events
+// can use it.
+func (sm *StateManager) AnnounceHandler(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	var link, text string
+	fields := map[role.FieldType]bool{role.AcceptedField: true}
+	for _, opt := range i.ApplicationCommandData().Options {
+		switch opt.Name {
+		case "event":
+			link = opt.StringValue()
+		case "message":
+			text = opt.StringValue()
+		case "accepted":
+			fields[role.AcceptedField] = opt.BoolValue()
+		case "tentative":
+			fields[role.TentativeField] = opt.BoolValue()
+		case "waitlist":
+			fields[role.WaitlistField] = opt.BoolValue()
 		}
 	}
+	var selected []role.FieldType
+	for _, f := range []role.FieldType{role.AcceptedField, role.TentativeField, role.WaitlistField} {
+		if fields[f] {
+			selected = append(selected, f)
+		}
+	}
+	if len(selected) == 0 {
+		return errs.NewValidation("Choose at least one group of members to message")
+	}
 
-	if desc == "" {
-		desc = "No events found!"
+	guildID, channelID, messageID, err := util.GetIDsFromDiscordLink(link)
+	if err != nil || guildID != i.GuildID {
+		return errs.NewValidation("That isn't a link to an event in this server")
+	}
+	event, err := getEvent(s, guildID, channelID, messageID)
+	if err != nil {
+		return errs.NewValidation("That isn't a link to an event in this server")
+	}
+	if i.Member.Permissions&discordgo.PermissionManageEvents == 0 && event.Owner != i.Member.User.Username {
+		return errs.NewPermission("You must either be the event organizer or have the `Manage Events` permission to do that")
 	}
 
-	if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseChannelMessageWithSource,
-		Data: &discordgo.InteractionResponseData{
-			Embeds: []*discordgo.MessageEmbed{
-				{
-					Title:       "My Events",
-					Color:       discord.Purple,
-					Description: desc,
-				},
-			},
-			Flags: discordgo.MessageFlagsEphemeral,
-		},
+	report, err := discord.Announce(s, sm.Store, sm.memberPrinter, guildID, discord.Announcement{
+		Event:     event,
+		ChannelID: channelID,
+		MessageID: messageID,
+		Author:    i.Member.User.Username,
+		Text:      text,
+		Fields:    selected,
+	})
+	if err != nil {
+		return err
+	}
+	if _, err = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Embeds: &[]*discordgo.MessageEmbed{discord.DeliveryReportEmbed(sm.printer(i), report)},
 	}); err != nil {
-		log.Printf("failed to reponsd: %v", err)
-		return
+		return errs.NewUpstream("failed to edit response", err)
 	}
+	logging.FromInteraction(i).Info("User messaged attendees", logging.EventKey, logging.EventPath(channelID, messageID), "delivered", len(report.Delivered), "mentioned", len(report.Mentioned), "failed", len(report.Failed))
+	return nil
 }
 
-func (sm *StateManager) ListUpcomingEventsHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	if sm == nil {
-		log.Printf("commands manager is nil")
-		return
+func (sm *StateManager) ImportEventsHandler(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	logger := logging.FromInteraction(i)
+	channelID := sm.Config.Discord.EventsChannelID
+	if channelID == "" {
+		channelID = i.ChannelID
 	}
-	if sm.CalendarClient == nil {
-		log.Println("calendar client is nil")
-		return
-	}
-	events, err := sm.CalendarClient.ListEvents()
+
+	guildEvents, err := s.GuildScheduledEvents(i.GuildID, false)
 	if err != nil {
-		log.Printf("cannot list events from Google calendar")
-		return
+		return errs.NewUpstream("cannot get guild events", err)
 	}
 
 	var desc string
-	for _, e := range events {
-		link, err := util.GetDiscordLinkFromCalendarDescription(e.Description)
-		if err != nil {
-			log.Printf("failed to get discord link from calendar: %v", err)
-			return
-		}
-		_, channelID, messageID, err := util.GetIDsFromDiscordLink(link)
-		if err != nil {
-			log.Printf("failed to get id: %v", err)
-			return
-		}
-
-		msg, err := s.ChannelMessage(channelID, messageID)
-		if err != nil {
-			log.Printf("failed to get message: %v", err)
-			return
+	for _, guildEvent := range guildEvents {
+		if util.FindDiscordLink(guildEvent.Description) != "" {
+			continue
 		}
-
-		event, err := discord.GetEventFromMessage(msg)
+		event, err := sm.importScheduledEvent(s, guildEvent, channelID)
 		if err != nil {
-			log.Printf("failed to convert message: %v", err)
-			return
+			logger.Error("failed to import guild event", "guildEvent", guildEvent.ID, logging.ErrorKey, err)
+			continue
 		}
-		desc += util.PrintEventListItem(event.Start, event.Title, link)
+		desc += util.PrintEventListItem(event.Start, event.Title, event.DiscordLink)
 	}
 
+	p := sm.printer(i)
 	if desc == "" {
-		desc = "No events found!"
+		desc = p.T("No events to import!")
 	}
 
-	if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseChannelMessageWithSource,
-		Data: &discordgo.InteractionResponseData{
-			Embeds: []*discordgo.MessageEmbed{
-				{
-					Title:       "Upcoming Events",
-					Color:       discord.Purple,
-					Description: desc,
-				},
+	if _, err = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Embeds: &[]*discordgo.MessageEmbed{
+			{
+				Title:       p.T("Imported Events"),
+				Color:       discord.Purple,
+				Description: desc,
 			},
-			Flags: discordgo.MessageFlagsEphemeral,
 		},
 	}); err != nil {
-		log.Printf("failed to reponsd: %v", err)
-		return
+		return errs.NewUpstream("failed to edit response", err)
 	}
+	return nil
 }
 
 //func EditEventHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
@@ -7,25 +7,35 @@ import (
 )
 
 func CreateEvents() fsm.Events {
-	return fsm.Events{
+	return withBack(fsm.Events{
 		{
 			Name: states.StartCreate.String(),
 			Src:  []string{states.Idle.String()},
 			Dst:  states.StartCreate.String(),
 		},
 		{
-			Name: states.AddTitle.String(),
+			Name: states.ResumeDraft.String(),
 			Src:  []string{states.StartCreate.String()},
+			Dst:  states.ResumeDraft.String(),
+		},
+		{
+			Name: states.ResumeDraftRetry.String(),
+			Src:  []string{states.ResumeDraft.String(), states.SelfTransition.String()},
+			Dst:  states.ResumeDraftRetry.String(),
+		},
+		{
+			Name: states.AddTitle.String(),
+			Src:  fromReview(states.StartCreate.String(), states.ResumeDraft.String(), states.ResumeDraftRetry.String()),
 			Dst:  states.AddTitle.String(),
 		},
 		{
 			Name: states.AddDescription.String(),
-			Src:  []string{states.AddTitle.String()},
+			Src:  fromReview(states.AddTitle.String()),
 			Dst:  states.AddDescription.String(),
 		},
 		{
 			Name: states.SetAttendeeLimit.String(),
-			Src:  []string{states.AddDescription.String()},
+			Src:  fromReview(states.AddDescription.String()),
 			Dst:  states.SetAttendeeLimit.String(),
 		},
 		{
@@ -35,8 +45,15 @@ func CreateEvents() fsm.Events {
 		},
 		{
 			Name: states.SetDate.String(),
-			Src:  []string{states.SetAttendeeLimit.String(), states.SetAttendeeRetry.String()},
-			Dst:  states.SetDate.String(),
+			Src: fromReview(
+				states.SetAttendeeLimit.String(),
+				states.SetAttendeeRetry.String(),
+				states.ConfirmDate.String(),
+				states.ConfirmDateRetry.String(),
+				states.ReviewEvent.String(),
+				states.ReviewEventRetry.String(),
+			),
+			Dst: states.SetDate.String(),
 		},
 		{
 			Name: states.SetDateRetry.String(),
@@ -44,13 +61,28 @@ func CreateEvents() fsm.Events {
 			Dst:  states.SetDateRetry.String(),
 		},
 		{
-			Name: states.SetLocation.String(),
+			Name: states.ConfirmDate.String(),
 			Src:  []string{states.SetDate.String(), states.SetDateRetry.String()},
-			Dst:  states.SetLocation.String(),
+			Dst:  states.ConfirmDate.String(),
+		},
+		{
+			Name: states.ConfirmDateRetry.String(),
+			Src:  []string{states.ConfirmDate.String(), states.SelfTransition.String()},
+			Dst:  states.ConfirmDateRetry.String(),
+		},
+		{
+			Name: states.SetLocation.String(),
+			Src: fromReview(
+				states.SetDate.String(),
+				states.SetDateRetry.String(),
+				states.ConfirmDate.String(),
+				states.ConfirmDateRetry.String(),
+			),
+			Dst: states.SetLocation.String(),
 		},
 		{
 			Name: states.SetDuration.String(),
-			Src:  []string{states.SetLocation.String()},
+			Src:  fromReview(states.SetLocation.String()),
 			Dst:  states.SetDuration.String(),
 		},
 		{
@@ -59,68 +91,209 @@ func CreateEvents() fsm.Events {
 			Dst:  states.SetDurationRetry.String(),
 		},
 		{
-			Name: states.CreateEvent.String(),
-			Src:  []string{states.SetDuration.String(), states.SetDurationRetry.String()},
-			Dst:  states.CreateEvent.String(),
+			Name: states.SetDeadline.String(),
+			Src:  fromReview(states.SetDuration.String(), states.SetDurationRetry.String()),
+			Dst:  states.SetDeadline.String(),
 		},
 		{
-			Name: states.SelfTransition.String(),
-			Src: []string{
-				states.SetAttendeeRetry.String(),
-				states.SetDateRetry.String(),
-				states.SetDurationRetry.String(),
-			},
-			Dst: states.SelfTransition.String(),
+			Name: states.SetDeadlineRetry.String(),
+			Src:  []string{states.SetDeadline.String(), states.SelfTransition.String()},
+			Dst:  states.SetDeadlineRetry.String(),
 		},
 		{
-			Name: states.Cancel.String(),
+			Name: states.SetCategory.String(),
+			Src:  fromReview(states.SetDeadline.String(), states.SetDeadlineRetry.String()),
+			Dst:  states.SetCategory.String(),
+		},
+		{
+			Name: states.SetCategoryRetry.String(),
+			Src:  []string{states.SetCategory.String(), states.SelfTransition.String()},
+			Dst:  states.SetCategoryRetry.String(),
+		},
+		{
+			Name: states.ReviewEvent.String(),
 			Src: []string{
+				states.StartCreate.String(),
+				states.ResumeDraft.String(),
+				states.ResumeDraftRetry.String(),
 				states.AddTitle.String(),
 				states.AddDescription.String(),
 				states.SetAttendeeLimit.String(),
 				states.SetAttendeeRetry.String(),
 				states.SetDate.String(),
 				states.SetDateRetry.String(),
+				states.ConfirmDate.String(),
+				states.ConfirmDateRetry.String(),
 				states.SetLocation.String(),
 				states.SetDuration.String(),
 				states.SetDurationRetry.String(),
+				states.SetDeadline.String(),
+				states.SetDeadlineRetry.String(),
+				states.SetCategory.String(),
+				states.SetCategoryRetry.String(),
 			},
-			Dst: states.Cancel.String(),
+			Dst: states.ReviewEvent.String(),
 		},
 		{
-			Name: states.Timeout.String(),
+			Name: states.ReviewEventRetry.String(),
+			Src:  []string{states.ReviewEvent.String(), states.SelfTransition.String()},
+			Dst:  states.ReviewEventRetry.String(),
+		},
+		{
+			Name: states.ReviewField.String(),
+			Src:  []string{states.ReviewEvent.String(), states.ReviewEventRetry.String()},
+			Dst:  states.ReviewField.String(),
+		},
+		{
+			Name: states.ReviewFieldRetry.String(),
+			Src:  []string{states.ReviewField.String(), states.SelfTransition.String()},
+			Dst:  states.ReviewFieldRetry.String(),
+		},
+		{
+			Name: states.SaveDraft.String(),
+			Src:  []string{states.ReviewEvent.String(), states.ReviewEventRetry.String()},
+			Dst:  states.SaveDraft.String(),
+		},
+		{
+			Name: states.SchedulePublish.String(),
+			Src:  []string{states.ReviewEvent.String(), states.ReviewEventRetry.String()},
+			Dst:  states.SchedulePublish.String(),
+		},
+		{
+			Name: states.SchedulePublishRetry.String(),
+			Src:  []string{states.SchedulePublish.String(), states.SelfTransition.String()},
+			Dst:  states.SchedulePublishRetry.String(),
+		},
+		{
+			Name: states.CreateEvent.String(),
+			Src:  []string{states.ReviewEvent.String(), states.ReviewEventRetry.String()},
+			Dst:  states.CreateEvent.String(),
+		},
+		{
+			Name: states.SelfTransition.String(),
 			Src: []string{
-				states.AddTitle.String(),
-				states.AddDescription.String(),
-				states.SetAttendeeLimit.String(),
+				states.ResumeDraftRetry.String(),
 				states.SetAttendeeRetry.String(),
-				states.SetDate.String(),
 				states.SetDateRetry.String(),
-				states.SetLocation.String(),
-				states.SetDuration.String(),
+				states.ConfirmDateRetry.String(),
 				states.SetDurationRetry.String(),
+				states.SetDeadlineRetry.String(),
+				states.SetCategoryRetry.String(),
+				states.ReviewEventRetry.String(),
+				states.ReviewFieldRetry.String(),
+				states.SchedulePublishRetry.String(),
 			},
-			Dst: states.Timeout.String(),
+			Dst: states.SelfTransition.String(),
 		},
+		{
+			Name: states.Cancel.String(),
+			Src:  createPrompts(),
+			Dst:  states.Cancel.String(),
+		},
+		{
+			Name: states.Timeout.String(),
+			Src:  createPrompts(),
+			Dst:  states.Timeout.String(),
+		},
+	}, createPrompts())
+}
+
+// fromReview adds the preview, where any field can be changed, to the states a field is asked for after
+func fromReview(src ...string) []string {
+	return append(src, states.ReviewField.String(), states.ReviewFieldRetry.String())
+}
+
+// createPrompts are the states waiting for input, which can be canceled, time out or go back to the previous prompt
+func createPrompts() []string {
+	return []string{
+		states.ResumeDraft.String(),
+		states.ResumeDraftRetry.String(),
+		states.AddTitle.String(),
+		states.AddDescription.String(),
+		states.SetAttendeeLimit.String(),
+		states.SetAttendeeRetry.String(),
+		states.SetDate.String(),
+		states.SetDateRetry.String(),
+		states.ConfirmDate.String(),
+		states.ConfirmDateRetry.String(),
+		states.SetLocation.String(),
+		states.SetDuration.String(),
+		states.SetDurationRetry.String(),
+		states.SetDeadline.String(),
+		states.SetDeadlineRetry.String(),
+		states.SetCategory.String(),
+		states.SetCategoryRetry.String(),
+		states.ReviewEvent.String(),
+		states.ReviewEventRetry.String(),
+		states.ReviewField.String(),
+		states.ReviewFieldRetry.String(),
+		states.SchedulePublish.String(),
+		states.SchedulePublishRetry.String(),
+	}
+}
+
+// createSteps are the states of the creation DMs in order, each followed by the next one
+var createSteps = map[string]string{
+	states.StartCreate.String():      states.ResumeDraft.String(),
+	states.ResumeDraft.String():      states.AddTitle.String(),
+	states.AddTitle.String():         states.AddDescription.String(),
+	states.AddDescription.String():   states.SetAttendeeLimit.String(),
+	states.SetAttendeeLimit.String(): states.SetDate.String(),
+	states.SetDate.String():          states.SetLocation.String(),
+	states.ConfirmDate.String():      states.SetLocation.String(),
+	states.SetLocation.String():      states.SetDuration.String(),
+	states.SetDuration.String():      states.SetDeadline.String(),
+	states.SetDeadline.String():      states.SetCategory.String(),
+	states.SetCategory.String():      states.ReviewEvent.String(),
+}
+
+// CreateNext is the state of the creation DMs after the current one, or empty once the preview took over. A resumed
+// draft goes straight to the preview, as does a field asked for again after the preview was shown.
+func CreateNext(f *fsm.FSM) string {
+	current := states.Prompt(f.Current())
+	next, ok := createSteps[current]
+	if !ok {
+		return ""
+	}
+	_, resumed := f.Metadata(discord.DraftID.String())
+	_, previewed := f.Metadata(discord.EventObject.String())
+	if current != states.StartCreate.String() && (resumed || previewed) {
+		return states.ReviewEvent.String()
 	}
+	return next
 }
 
 func CreateEventStates(o discord.Options) map[string]FSMState {
 	return map[string]FSMState{
-		states.Cancel.String():           states.NewCancelState(o),
-		states.Timeout.String():          states.NewTimeoutState(o),
-		states.StartCreate.String():      states.NewStartCreateState(o),
-		states.AddTitle.String():         states.NewAddTitleState(o),
-		states.AddDescription.String():   states.NewAddDescriptionState(o),
-		states.SetAttendeeLimit.String(): states.NewSetAttendeeState(o),
-		states.SetAttendeeRetry.String(): states.NewSetAttendeeRetryState(o),
-		states.SetDate.String():          states.NewSetDateState(o),
-		states.SetDateRetry.String():     states.NewSetDateRetryState(o),
-		states.SetLocation.String():      states.NewSetLocationState(o),
-		states.SetDuration.String():      states.NewDurationState(o),
-		states.SetDurationRetry.String(): states.NewDurationRetryState(o),
-		states.CreateEvent.String():      states.NewCreateEventState(o),
-		states.SelfTransition.String():   states.NewSelfTransitionState(o),
+		states.Cancel.String():               states.NewCancelState(o),
+		states.Timeout.String():              states.NewTimeoutState(o),
+		states.StartCreate.String():          states.NewStartCreateState(o),
+		states.ResumeDraft.String():          states.NewResumeDraftState(o),
+		states.ResumeDraftRetry.String():     states.NewResumeDraftRetryState(o),
+		states.AddTitle.String():             states.NewAddTitleState(o),
+		states.AddDescription.String():       states.NewAddDescriptionState(o),
+		states.SetAttendeeLimit.String():     states.NewSetAttendeeState(o),
+		states.SetAttendeeRetry.String():     states.NewSetAttendeeRetryState(o),
+		states.SetDate.String():              states.NewSetDateState(o),
+		states.SetDateRetry.String():         states.NewSetDateRetryState(o),
+		states.ConfirmDate.String():          states.NewConfirmDateState(o),
+		states.ConfirmDateRetry.String():     states.NewConfirmDateRetryState(o),
+		states.SetLocation.String():          states.NewSetLocationState(o),
+		states.SetDuration.String():          states.NewDurationState(o),
+		states.SetDurationRetry.String():     states.NewDurationRetryState(o),
+		states.SetDeadline.String():          states.NewDeadlineState(o),
+		states.SetDeadlineRetry.String():     states.NewDeadlineRetryState(o),
+		states.SetCategory.String():          states.NewCategoryState(o),
+		states.SetCategoryRetry.String():     states.NewCategoryRetryState(o),
+		states.ReviewEvent.String():          states.NewReviewEventState(o),
+		states.ReviewEventRetry.String():     states.NewReviewEventRetryState(o),
+		states.ReviewField.String():          states.NewReviewFieldState(o),
+		states.ReviewFieldRetry.String():     states.NewReviewFieldRetryState(o),
+		states.SaveDraft.String():            states.NewSaveDraftState(o),
+		states.SchedulePublish.String():      states.NewSchedulePublishState(o),
+		states.SchedulePublishRetry.String(): states.NewSchedulePublishRetryState(o),
+		states.CreateEvent.String():          states.NewCreateEventState(o),
+		states.SelfTransition.String():       states.NewSelfTransitionState(o),
 	}
 }
 
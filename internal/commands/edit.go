@@ -7,7 +7,7 @@ import (
 )
 
 func EditEvents() fsm.Events {
-	return fsm.Events{
+	return withBack(fsm.Events{
 		{
 			Name: states.StartEdit.String(),
 			Src:  []string{states.Idle.String(), states.ContinueEdit.String()},
@@ -42,6 +42,36 @@ func EditEvents() fsm.Events {
 			Src:  []string{states.RemoveResponse.String(), states.SelfTransition.String()},
 			Dst:  states.RemoveResponseRetry.String(),
 		},
+		{
+			Name: states.TakeAttendance.String(),
+			Src:  []string{states.StartEdit.String(), states.StartEditRetry.String()},
+			Dst:  states.TakeAttendance.String(),
+		},
+		{
+			Name: states.TakeAttendanceRetry.String(),
+			Src:  []string{states.TakeAttendance.String(), states.SelfTransition.String()},
+			Dst:  states.TakeAttendanceRetry.String(),
+		},
+		{
+			Name: states.MessageAttendees.String(),
+			Src:  []string{states.StartEdit.String(), states.StartEditRetry.String()},
+			Dst:  states.MessageAttendees.String(),
+		},
+		{
+			Name: states.MessageAttendeesRetry.String(),
+			Src:  []string{states.MessageAttendees.String(), states.SelfTransition.String()},
+			Dst:  states.MessageAttendeesRetry.String(),
+		},
+		{
+			Name: states.EnterAnnouncement.String(),
+			Src:  []string{states.MessageAttendees.String(), states.MessageAttendeesRetry.String()},
+			Dst:  states.EnterAnnouncement.String(),
+		},
+		{
+			Name: states.EnterAnnouncementRetry.String(),
+			Src:  []string{states.EnterAnnouncement.String(), states.SelfTransition.String()},
+			Dst:  states.EnterAnnouncementRetry.String(),
+		},
 		{
 			Name: states.AddResponse.String(),
 			Src: []string{
@@ -91,26 +121,128 @@ func EditEvents() fsm.Events {
 		},
 		{
 			Name: states.SetDate.String(),
-			Src:  []string{states.ModifyEvent.String(), states.ModifyEventRetry.String()},
-			Dst:  states.SetDate.String(),
+			Src: []string{
+				states.ModifyEvent.String(),
+				states.ModifyEventRetry.String(),
+				states.ConfirmDate.String(),
+				states.ConfirmDateRetry.String(),
+			},
+			Dst: states.SetDate.String(),
+		},
+		{
+			Name: states.SetDateRetry.String(),
+			Src:  []string{states.SetDate.String(), states.SelfTransition.String()},
+			Dst:  states.SetDateRetry.String(),
+		},
+		{
+			Name: states.ConfirmDate.String(),
+			Src:  []string{states.SetDate.String(), states.SetDateRetry.String()},
+			Dst:  states.ConfirmDate.String(),
+		},
+		{
+			Name: states.ConfirmDateRetry.String(),
+			Src:  []string{states.ConfirmDate.String(), states.SelfTransition.String()},
+			Dst:  states.ConfirmDateRetry.String(),
 		},
 		{
 			Name: states.SetDuration.String(),
 			Src:  []string{states.ModifyEvent.String(), states.ModifyEventRetry.String()},
 			Dst:  states.SetDuration.String(),
 		},
+		{
+			Name: states.SetDurationRetry.String(),
+			Src:  []string{states.SetDuration.String(), states.SelfTransition.String()},
+			Dst:  states.SetDurationRetry.String(),
+		},
 		{
 			Name: states.SetLocation.String(),
 			Src:  []string{states.ModifyEvent.String(), states.ModifyEventRetry.String()},
 			Dst:  states.SetLocation.String(),
 		},
+		{
+			Name: states.SetDeadline.String(),
+			Src:  []string{states.ModifyEvent.String(), states.ModifyEventRetry.String()},
+			Dst:  states.SetDeadline.String(),
+		},
+		{
+			Name: states.SetDeadlineRetry.String(),
+			Src:  []string{states.SetDeadline.String(), states.SelfTransition.String()},
+			Dst:  states.SetDeadlineRetry.String(),
+		},
+		{
+			Name: states.SetGuests.String(),
+			Src:  []string{states.ModifyEvent.String(), states.ModifyEventRetry.String()},
+			Dst:  states.SetGuests.String(),
+		},
+		{
+			Name: states.SetGuestsRetry.String(),
+			Src:  []string{states.SetGuests.String(), states.SelfTransition.String()},
+			Dst:  states.SetGuestsRetry.String(),
+		},
+		{
+			Name: states.SetCategory.String(),
+			Src:  []string{states.ModifyEvent.String(), states.ModifyEventRetry.String()},
+			Dst:  states.SetCategory.String(),
+		},
+		{
+			Name: states.SetCategoryRetry.String(),
+			Src:  []string{states.SetCategory.String(), states.SelfTransition.String()},
+			Dst:  states.SetCategoryRetry.String(),
+		},
+		{
+			Name: states.SetAttendeeLimit.String(),
+			Src:  []string{states.ModifyEvent.String(), states.ModifyEventRetry.String()},
+			Dst:  states.SetAttendeeLimit.String(),
+		},
+		{
+			Name: states.SetAttendeeRetry.String(),
+			Src:  []string{states.SetAttendeeLimit.String(), states.SelfTransition.String()},
+			Dst:  states.SetAttendeeRetry.String(),
+		},
+		{
+			Name: states.SetOwner.String(),
+			Src:  []string{states.ModifyEvent.String(), states.ModifyEventRetry.String()},
+			Dst:  states.SetOwner.String(),
+		},
+		{
+			Name: states.SetOwnerRetry.String(),
+			Src:  []string{states.SetOwner.String(), states.SelfTransition.String()},
+			Dst:  states.SetOwnerRetry.String(),
+		},
+		{
+			Name: states.SetChannel.String(),
+			Src:  []string{states.ModifyEvent.String(), states.ModifyEventRetry.String()},
+			Dst:  states.SetChannel.String(),
+		},
+		{
+			Name: states.SetChannelRetry.String(),
+			Src:  []string{states.SetChannel.String(), states.SelfTransition.String()},
+			Dst:  states.SetChannelRetry.String(),
+		},
 		{
 			Name: states.ContinueEdit.String(),
 			Src: []string{
 				states.AddTitle.String(),
 				states.AddDescription.String(),
 				states.SetDate.String(),
+				states.SetDateRetry.String(),
+				states.ConfirmDate.String(),
+				states.ConfirmDateRetry.String(),
+				states.SetDuration.String(),
+				states.SetDurationRetry.String(),
 				states.SetLocation.String(),
+				states.SetDeadline.String(),
+				states.SetDeadlineRetry.String(),
+				states.SetGuests.String(),
+				states.SetGuestsRetry.String(),
+				states.SetCategory.String(),
+				states.SetCategoryRetry.String(),
+				states.SetAttendeeLimit.String(),
+				states.SetAttendeeRetry.String(),
+				states.SetOwner.String(),
+				states.SetOwnerRetry.String(),
+				states.SetChannel.String(),
+				states.SetChannelRetry.String(),
 			},
 			Dst: states.ContinueEdit.String(),
 		},
@@ -128,6 +260,8 @@ func EditEvents() fsm.Events {
 				states.SignUpRetry.String(),
 				states.RemoveResponse.String(),
 				states.RemoveResponseRetry.String(),
+				states.TakeAttendance.String(),
+				states.TakeAttendanceRetry.String(),
 			},
 			Dst: states.ProcessEdit.String(),
 		},
@@ -141,79 +275,126 @@ func EditEvents() fsm.Events {
 				states.SignUpRetry.String(),
 				states.RemoveResponseRetry.String(),
 				states.UnknownUserRetry.String(),
+				states.SetDateRetry.String(),
+				states.ConfirmDateRetry.String(),
+				states.SetDurationRetry.String(),
+				states.SetDeadlineRetry.String(),
+				states.SetGuestsRetry.String(),
+				states.SetCategoryRetry.String(),
+				states.SetAttendeeRetry.String(),
+				states.SetOwnerRetry.String(),
+				states.SetChannelRetry.String(),
+				states.TakeAttendanceRetry.String(),
+				states.MessageAttendeesRetry.String(),
+				states.EnterAnnouncementRetry.String(),
 			},
 			Dst: states.SelfTransition.String(),
 		},
 		{
 			Name: states.Cancel.String(),
-			Src: []string{
-				states.StartEdit.String(),
-				states.ModifyEvent.String(),
-				states.RemoveResponse.String(),
-				states.RemoveResponseRetry.String(),
-				states.AddResponse.String(),
-				states.AddTitle.String(),
-				states.AddDescription.String(),
-				states.SetDate.String(),
-				states.SetDuration.String(),
-				states.SetLocation.String(),
-				states.ContinueEdit.String(),
-				states.ContinueEditRetry.String(),
-				states.UnknownUser.String(),
-				states.UnknownUserRetry.String(),
-				states.SignUp.String(),
-				states.SignUpRetry.String(),
-			},
-			Dst: states.Cancel.String(),
+			Src:  editPrompts(),
+			Dst:  states.Cancel.String(),
 		},
 		{
 			Name: states.Timeout.String(),
-			Src: []string{
-				states.StartEdit.String(),
-				states.ModifyEvent.String(),
-				states.RemoveResponse.String(),
-				states.RemoveResponseRetry.String(),
-				states.AddResponse.String(),
-				states.AddTitle.String(),
-				states.AddDescription.String(),
-				states.SetDate.String(),
-				states.SetDuration.String(),
-				states.SetLocation.String(),
-				states.ContinueEdit.String(),
-				states.ContinueEditRetry.String(),
-				states.UnknownUser.String(),
-				states.UnknownUserRetry.String(),
-				states.SignUp.String(),
-				states.SignUpRetry.String(),
-			},
-			Dst: states.Timeout.String(),
+			Src:  editPrompts(),
+			Dst:  states.Timeout.String(),
 		},
+	}, editPrompts())
+}
+
+// editPrompts are the states waiting for input, which can be canceled, time out or go back to the previous prompt
+func editPrompts() []string {
+	return []string{
+		states.StartEdit.String(),
+		states.StartEditRetry.String(),
+		states.ModifyEvent.String(),
+		states.ModifyEventRetry.String(),
+		states.RemoveResponse.String(),
+		states.RemoveResponseRetry.String(),
+		states.AddResponse.String(),
+		states.AddTitle.String(),
+		states.AddDescription.String(),
+		states.SetDate.String(),
+		states.SetDateRetry.String(),
+		states.ConfirmDate.String(),
+		states.ConfirmDateRetry.String(),
+		states.SetDuration.String(),
+		states.SetDurationRetry.String(),
+		states.SetLocation.String(),
+		states.SetDeadline.String(),
+		states.SetDeadlineRetry.String(),
+		states.SetGuests.String(),
+		states.SetGuestsRetry.String(),
+		states.SetCategory.String(),
+		states.SetCategoryRetry.String(),
+		states.SetAttendeeLimit.String(),
+		states.SetAttendeeRetry.String(),
+		states.SetOwner.String(),
+		states.SetOwnerRetry.String(),
+		states.SetChannel.String(),
+		states.SetChannelRetry.String(),
+		states.ContinueEdit.String(),
+		states.ContinueEditRetry.String(),
+		states.UnknownUser.String(),
+		states.UnknownUserRetry.String(),
+		states.SignUp.String(),
+		states.SignUpRetry.String(),
+		states.TakeAttendance.String(),
+		states.TakeAttendanceRetry.String(),
+		states.MessageAttendees.String(),
+		states.MessageAttendeesRetry.String(),
+		states.EnterAnnouncement.String(),
+		states.EnterAnnouncementRetry.String(),
 	}
 }
 
 func EditEventStates(o discord.Options) map[string]FSMState {
 	return map[string]FSMState{
-		states.Cancel.String():              states.NewCancelState(o),
-		states.Timeout.String():             states.NewTimeoutState(o),
-		states.StartEdit.String():           states.NewStartEditState(o),
-		states.StartEditRetry.String():      states.NewStartEditRetryState(o),
-		states.ModifyEvent.String():         states.NewModifyEventState(o),
-		states.ModifyEventRetry.String():    states.NewModifyEventRetryState(o),
-		states.AddTitle.String():            states.NewAddTitleState(o),
-		states.AddDescription.String():      states.NewAddDescriptionState(o),
-		states.SetDate.String():             states.NewSetDateState(o),
-		states.SetLocation.String():         states.NewSetLocationState(o),
-		states.ContinueEdit.String():        states.NewContinueEditState(o),
-		states.ContinueEditRetry.String():   states.NewContinueEditRetryState(o),
-		states.RemoveResponse.String():      states.NewRemoveResponseState(o),
-		states.RemoveResponseRetry.String(): states.NewRemoveResponseRetryState(o),
-		states.ProcessEdit.String():         states.NewProcessEditState(o),
-		states.AddResponse.String():         states.NewAddResponseState(o),
-		states.UnknownUser.String():         states.NewUnknownUserState(o),
-		states.UnknownUserRetry.String():    states.NewUnknownUserRetryState(o),
-		states.SignUp.String():              states.NewSignUpState(o),
-		states.SignUpRetry.String():         states.NewSignUpRetryState(o),
-		states.SelfTransition.String():      states.NewSelfTransitionState(o),
+		states.Cancel.String():                 states.NewCancelState(o),
+		states.Timeout.String():                states.NewTimeoutState(o),
+		states.StartEdit.String():              states.NewStartEditState(o),
+		states.StartEditRetry.String():         states.NewStartEditRetryState(o),
+		states.ModifyEvent.String():            states.NewModifyEventState(o),
+		states.ModifyEventRetry.String():       states.NewModifyEventRetryState(o),
+		states.AddTitle.String():               states.NewAddTitleState(o),
+		states.AddDescription.String():         states.NewAddDescriptionState(o),
+		states.SetDate.String():                states.NewSetDateState(o),
+		states.SetDateRetry.String():           states.NewSetDateRetryState(o),
+		states.ConfirmDate.String():            states.NewConfirmDateState(o),
+		states.ConfirmDateRetry.String():       states.NewConfirmDateRetryState(o),
+		states.SetDuration.String():            states.NewDurationState(o),
+		states.SetDurationRetry.String():       states.NewDurationRetryState(o),
+		states.SetLocation.String():            states.NewSetLocationState(o),
+		states.SetDeadline.String():            states.NewDeadlineState(o),
+		states.SetDeadlineRetry.String():       states.NewDeadlineRetryState(o),
+		states.SetGuests.String():              states.NewGuestsState(o),
+		states.SetGuestsRetry.String():         states.NewGuestsRetryState(o),
+		states.SetCategory.String():            states.NewCategoryState(o),
+		states.SetCategoryRetry.String():       states.NewCategoryRetryState(o),
+		states.SetAttendeeLimit.String():       states.NewSetAttendeeState(o),
+		states.SetAttendeeRetry.String():       states.NewSetAttendeeRetryState(o),
+		states.SetOwner.String():               states.NewSetOwnerState(o),
+		states.SetOwnerRetry.String():          states.NewSetOwnerRetryState(o),
+		states.SetChannel.String():             states.NewSetChannelState(o),
+		states.SetChannelRetry.String():        states.NewSetChannelRetryState(o),
+		states.ContinueEdit.String():           states.NewContinueEditState(o),
+		states.ContinueEditRetry.String():      states.NewContinueEditRetryState(o),
+		states.RemoveResponse.String():         states.NewRemoveResponseState(o),
+		states.RemoveResponseRetry.String():    states.NewRemoveResponseRetryState(o),
+		states.ProcessEdit.String():            states.NewProcessEditState(o),
+		states.TakeAttendance.String():         states.NewTakeAttendanceState(o),
+		states.TakeAttendanceRetry.String():    states.NewTakeAttendanceRetryState(o),
+		states.MessageAttendees.String():       states.NewMessageAttendeesState(o),
+		states.MessageAttendeesRetry.String():  states.NewMessageAttendeesRetryState(o),
+		states.EnterAnnouncement.String():      states.NewEnterAnnouncementState(o),
+		states.EnterAnnouncementRetry.String(): states.NewEnterAnnouncementRetryState(o),
+		states.AddResponse.String():            states.NewAddResponseState(o),
+		states.UnknownUser.String():            states.NewUnknownUserState(o),
+		states.UnknownUserRetry.String():       states.NewUnknownUserRetryState(o),
+		states.SignUp.String():                 states.NewSignUpState(o),
+		states.SignUpRetry.String():            states.NewSignUpRetryState(o),
+		states.SelfTransition.String():         states.NewSelfTransitionState(o),
 	}
 }
 
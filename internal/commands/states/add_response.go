@@ -8,13 +8,13 @@ import (
 	"github.com/GuessWhoSamFoo/gang-gang-bot/pkg/util"
 	"github.com/bwmarrin/discordgo"
 	"github.com/lithammer/fuzzysearch/fuzzy"
-	"time"
 )
 
 type AddResponseState struct {
 	session           *discordgo.Session
 	interactionCreate *discordgo.InteractionCreate
 	channel           *discordgo.Channel
+	printer           *discord.Printer
 
 	inputHandler *InputHandler
 }
@@ -24,13 +24,14 @@ func NewAddResponseState(o discord.Options) *AddResponseState {
 		session:           o.Session,
 		interactionCreate: o.InteractionCreate,
 		channel:           o.Channel,
+		printer:           o.Printer,
 
 		inputHandler: NewInputHandler(&o),
 	}
 }
 
 func (a *AddResponseState) OnState(ctx context.Context, e *fsm.Event) {
-	if _, err := a.session.ChannelMessageSendEmbed(a.channel.ID, &discord.EnterUserNameMessage); err != nil {
+	if _, err := a.session.ChannelMessageSendEmbed(a.channel.ID, a.printer.Embed(&discord.EnterUserNameMessage)); err != nil {
 		e.Err = err
 		return
 	}
@@ -56,7 +57,7 @@ func (a *AddResponseState) OnState(ctx context.Context, e *fsm.Event) {
 		names = append(names, m.User.Username)
 	}
 
-	if err = a.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.Username, 60*time.Second); err != nil {
+	if err = a.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.Username, promptTimeout); err != nil {
 		e.Err = err
 		return
 	}
@@ -85,7 +86,7 @@ func (a *AddResponseState) findUser(names []string, e *fsm.Event, event *discord
 		return UnknownUser.String(), nil
 	}
 	if numMatches > 1 {
-		if _, err = a.session.ChannelMessageSend(a.channel.ID, discord.FoundMultipleText); err != nil {
+		if _, err = a.session.ChannelMessageSend(a.channel.ID, a.printer.T(discord.FoundMultipleText)); err != nil {
 			return "", err
 		}
 		return SelfTransition.String(), nil
@@ -93,7 +94,7 @@ func (a *AddResponseState) findUser(names []string, e *fsm.Event, event *discord
 	user := matches[0]
 	for _, r := range event.RoleGroup.Roles {
 		if util.ContainsUser(r.Users, user) {
-			if _, err = a.session.ChannelMessageSend(a.channel.ID, discord.UserSignedUpText); err != nil {
+			if _, err = a.session.ChannelMessageSend(a.channel.ID, a.printer.T(discord.UserSignedUpText)); err != nil {
 				return "", err
 			}
 			return Cancel.String(), nil
@@ -107,6 +108,7 @@ type UnknownUserState struct {
 	session           *discordgo.Session
 	interactionCreate *discordgo.InteractionCreate
 	channel           *discordgo.Channel
+	printer           *discord.Printer
 
 	inputHandler *InputHandler
 }
@@ -116,6 +118,7 @@ func NewUnknownUserState(o discord.Options) *UnknownUserState {
 		session:           o.Session,
 		interactionCreate: o.InteractionCreate,
 		channel:           o.Channel,
+		printer:           o.Printer,
 
 		inputHandler: NewInputHandler(&o),
 	}
@@ -127,19 +130,19 @@ func (u *UnknownUserState) OnState(ctx context.Context, e *fsm.Event) {
 		e.Err = fmt.Errorf("cannot find username")
 	}
 
-	if _, err := u.session.ChannelMessageSendEmbed(u.channel.ID, &discordgo.MessageEmbed{
+	if _, err := u.session.ChannelMessageSendEmbed(u.channel.ID, u.printer.Embed(&discordgo.MessageEmbed{
 		Title:       "We couldn't find a Discord user with that name",
 		Color:       discord.Purple,
-		Description: fmt.Sprintf("**1** Try another name\n**2** Add **%s** as a non Discord user\n**3** Cancel", name),
+		Description: u.printer.Tf("**1** Try another name\n**2** Add **%s** as a non Discord user\n**3** Cancel", name),
 		Footer: &discordgo.MessageEmbedFooter{
 			Text: discord.OptionText,
 		},
-	}); err != nil {
+	})); err != nil {
 		e.Err = err
 		return
 	}
 
-	if err := u.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.MenuOption, 60*time.Second); err != nil {
+	if err := u.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.MenuOption, promptTimeout); err != nil {
 		e.Err = err
 		return
 	}
@@ -164,6 +167,7 @@ type UnknownUserRetryState struct {
 	session           *discordgo.Session
 	interactionCreate *discordgo.InteractionCreate
 	channel           *discordgo.Channel
+	printer           *discord.Printer
 
 	inputHandler *InputHandler
 }
@@ -173,18 +177,19 @@ func NewUnknownUserRetryState(o discord.Options) *UnknownUserRetryState {
 		session:           o.Session,
 		interactionCreate: o.InteractionCreate,
 		channel:           o.Channel,
+		printer:           o.Printer,
 
 		inputHandler: NewInputHandler(&o),
 	}
 }
 
 func (u *UnknownUserRetryState) OnState(ctx context.Context, e *fsm.Event) {
-	if _, err := u.session.ChannelMessageSend(u.channel.ID, discord.InvalidEntryText); err != nil {
+	if _, err := u.session.ChannelMessageSend(u.channel.ID, u.printer.T(discord.InvalidEntryText)); err != nil {
 		e.Err = err
 		return
 	}
 
-	if err := u.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.MenuOption, 60*time.Second); err != nil {
+	if err := u.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.MenuOption, promptTimeout); err != nil {
 		e.Err = err
 		return
 	}
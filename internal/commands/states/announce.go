@@ -0,0 +1,263 @@
+package states
+
+import (
+	"context"
+	"fmt"
+	"github.com/GuessWhoSamFoo/fsm"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/discord"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/role"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/store"
+	"github.com/bwmarrin/discordgo"
+	"strings"
+	"unicode/utf8"
+)
+
+type MessageAttendeesState struct {
+	session           *discordgo.Session
+	interactionCreate *discordgo.InteractionCreate
+	channel           *discordgo.Channel
+	printer           *discord.Printer
+
+	inputHandler *InputHandler
+}
+
+func NewMessageAttendeesState(o discord.Options) *MessageAttendeesState {
+	return &MessageAttendeesState{
+		session:           o.Session,
+		interactionCreate: o.InteractionCreate,
+		channel:           o.Channel,
+		printer:           o.Printer,
+		inputHandler:      NewInputHandler(&o),
+	}
+}
+
+func (m *MessageAttendeesState) OnState(ctx context.Context, e *fsm.Event) {
+	if _, err := m.session.ChannelMessageSendEmbed(m.channel.ID, m.printer.Embed(&discord.EnterRecipientsMessage)); err != nil {
+		e.Err = err
+		return
+	}
+	if err := m.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.Recipients, promptTimeout); err != nil {
+		e.Err = err
+		return
+	}
+	if err := validateRecipients(e.FSM, discord.Recipients); err != nil {
+		eventErr := e.FSM.Event(ctx, MessageAttendeesRetry.String())
+		if eventErr != nil {
+			e.Err = fmt.Errorf("%v: %v", err, eventErr)
+		}
+		return
+	}
+	if err := e.FSM.Event(ctx, EnterAnnouncement.String()); err != nil {
+		e.Err = err
+		return
+	}
+}
+
+type MessageAttendeesRetryState struct {
+	session           *discordgo.Session
+	interactionCreate *discordgo.InteractionCreate
+	channel           *discordgo.Channel
+	printer           *discord.Printer
+
+	inputHandler *InputHandler
+}
+
+func NewMessageAttendeesRetryState(o discord.Options) *MessageAttendeesRetryState {
+	return &MessageAttendeesRetryState{
+		session:           o.Session,
+		interactionCreate: o.InteractionCreate,
+		channel:           o.Channel,
+		printer:           o.Printer,
+		inputHandler:      NewInputHandler(&o),
+	}
+}
+
+func (m *MessageAttendeesRetryState) OnState(ctx context.Context, e *fsm.Event) {
+	if _, err := m.session.ChannelMessageSend(m.channel.ID, m.printer.T(discord.InvalidRecipientsText)); err != nil {
+		e.Err = err
+		return
+	}
+	if err := m.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.Recipients, promptTimeout); err != nil {
+		e.Err = err
+		return
+	}
+	if err := validateRecipients(e.FSM, discord.Recipients); err != nil {
+		eventErr := e.FSM.Event(ctx, SelfTransition.String())
+		if eventErr != nil {
+			e.Err = fmt.Errorf("%v: %v", err, eventErr)
+		}
+		return
+	}
+	if err := e.FSM.Event(ctx, EnterAnnouncement.String()); err != nil {
+		e.Err = err
+		return
+	}
+}
+
+// validateRecipients converts the selected options into the roles which get a message
+func validateRecipients(f *fsm.FSM, key discord.MetadataKey) error {
+	val, err := Get(f, key)
+	if err != nil {
+		return err
+	}
+	opts := map[string]role.FieldType{
+		"1": role.AcceptedField,
+		"2": role.TentativeField,
+		"3": role.WaitlistField,
+	}
+	var fields []role.FieldType
+	for _, option := range strings.Fields(fmt.Sprintf("%v", val)) {
+		field, ok := opts[option]
+		if !ok {
+			return fmt.Errorf("invalid recipients option: %s", option)
+		}
+		fields = append(fields, field)
+	}
+	if len(fields) == 0 {
+		return fmt.Errorf("no recipients selected")
+	}
+	f.SetMetadata(key.String(), fields)
+	return nil
+}
+
+type EnterAnnouncementState struct {
+	session           *discordgo.Session
+	interactionCreate *discordgo.InteractionCreate
+	channel           *discordgo.Channel
+	printer           *discord.Printer
+	store             *store.Store
+	userPrinter       discord.PrinterFunc
+
+	inputHandler *InputHandler
+}
+
+func NewEnterAnnouncementState(o discord.Options) *EnterAnnouncementState {
+	return &EnterAnnouncementState{
+		session:           o.Session,
+		interactionCreate: o.InteractionCreate,
+		channel:           o.Channel,
+		printer:           o.Printer,
+		store:             o.Store,
+		userPrinter:       o.UserPrinter,
+		inputHandler:      NewInputHandler(&o),
+	}
+}
+
+func (a *EnterAnnouncementState) OnState(ctx context.Context, e *fsm.Event) {
+	if _, err := a.session.ChannelMessageSendEmbed(a.channel.ID, a.printer.Embed(&discord.EnterAnnouncementMessage)); err != nil {
+		e.Err = err
+		return
+	}
+	if err := a.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.AnnounceText, longPromptTimeout); err != nil {
+		e.Err = err
+		return
+	}
+	if err := validateAnnouncement(e.FSM, discord.AnnounceText); err != nil {
+		eventErr := e.FSM.Event(ctx, EnterAnnouncementRetry.String())
+		if eventErr != nil {
+			e.Err = fmt.Errorf("%v: %v", err, eventErr)
+		}
+		return
+	}
+	if err := sendAnnouncement(e.FSM, a.session, a.interactionCreate, a.channel, a.store, a.printer, a.userPrinter); err != nil {
+		e.Err = err
+		return
+	}
+}
+
+type EnterAnnouncementRetryState struct {
+	session           *discordgo.Session
+	interactionCreate *discordgo.InteractionCreate
+	channel           *discordgo.Channel
+	printer           *discord.Printer
+	store             *store.Store
+	userPrinter       discord.PrinterFunc
+
+	inputHandler *InputHandler
+}
+
+func NewEnterAnnouncementRetryState(o discord.Options) *EnterAnnouncementRetryState {
+	return &EnterAnnouncementRetryState{
+		session:           o.Session,
+		interactionCreate: o.InteractionCreate,
+		channel:           o.Channel,
+		printer:           o.Printer,
+		store:             o.Store,
+		userPrinter:       o.UserPrinter,
+		inputHandler:      NewInputHandler(&o),
+	}
+}
+
+func (a *EnterAnnouncementRetryState) OnState(ctx context.Context, e *fsm.Event) {
+	if _, err := a.session.ChannelMessageSend(a.channel.ID, a.printer.T(discord.InvalidAnnouncementText)); err != nil {
+		e.Err = err
+		return
+	}
+	if err := a.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.AnnounceText, longPromptTimeout); err != nil {
+		e.Err = err
+		return
+	}
+	if err := validateAnnouncement(e.FSM, discord.AnnounceText); err != nil {
+		eventErr := e.FSM.Event(ctx, SelfTransition.String())
+		if eventErr != nil {
+			e.Err = fmt.Errorf("%v: %v", err, eventErr)
+		}
+		return
+	}
+	if err := sendAnnouncement(e.FSM, a.session, a.interactionCreate, a.channel, a.store, a.printer, a.userPrinter); err != nil {
+		e.Err = err
+		return
+	}
+}
+
+func validateAnnouncement(f *fsm.FSM, key discord.MetadataKey) error {
+	val, err := Get(f, key)
+	if err != nil {
+		return err
+	}
+	text := strings.TrimSpace(fmt.Sprintf("%v", val))
+	if text == "" || utf8.RuneCountInString(text) > discord.MaxAnnouncementLength {
+		return fmt.Errorf("invalid announcement length")
+	}
+	f.SetMetadata(key.String(), text)
+	return nil
+}
+
+// sendAnnouncement messages the selected members of the event being edited, each in their language, and reports who it
+// reached
+func sendAnnouncement(f *fsm.FSM, s *discordgo.Session, i *discordgo.InteractionCreate, c *discordgo.Channel, st *store.Store, p *discord.Printer, printers discord.PrinterFunc) error {
+	obj, err := Get(f, discord.EventObject)
+	if err != nil {
+		return err
+	}
+	event, ok := obj.(discord.Event)
+	if !ok {
+		return fmt.Errorf("cannot get event")
+	}
+	recipients, err := Get(f, discord.Recipients)
+	if err != nil {
+		return err
+	}
+	fields, ok := recipients.([]role.FieldType)
+	if !ok {
+		return fmt.Errorf("cannot cast key: %s", discord.Recipients.String())
+	}
+	text, err := Get(f, discord.AnnounceText)
+	if err != nil {
+		return err
+	}
+
+	report, err := discord.Announce(s, st, printers, i.GuildID, discord.Announcement{
+		Event:     &event,
+		ChannelID: i.Interaction.ChannelID,
+		MessageID: i.Interaction.Message.ID,
+		Author:    i.Member.User.Username,
+		Text:      fmt.Sprintf("%v", text),
+		Fields:    fields,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = s.ChannelMessageSendEmbed(c.ID, discord.DeliveryReportEmbed(p, report))
+	return err
+}
@@ -0,0 +1,95 @@
+package states
+
+import (
+	"github.com/GuessWhoSamFoo/fsm"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/discord"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/role"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func Test_validateRecipients(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    string
+		expected []role.FieldType
+		isErr    bool
+	}{
+		{
+			name:     "accepted",
+			input:    "1",
+			expected: []role.FieldType{role.AcceptedField},
+		},
+		{
+			name:     "multiple",
+			input:    "1 3",
+			expected: []role.FieldType{role.AcceptedField, role.WaitlistField},
+		},
+		{
+			name:  "unknown option",
+			input: "1 4",
+			isErr: true,
+		},
+		{
+			name:  "empty",
+			input: " ",
+			isErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := fsm.NewFSM("idle", fsm.Events{}, fsm.Callbacks{})
+			f.SetMetadata(discord.Recipients.String(), tc.input)
+			err := validateRecipients(f, discord.Recipients)
+			if tc.isErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			actual, _ := f.Metadata(discord.Recipients.String())
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func Test_validateAnnouncement(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    string
+		expected string
+		isErr    bool
+	}{
+		{
+			name:     "trimmed",
+			input:    "  Meet at the north entrance \n",
+			expected: "Meet at the north entrance",
+		},
+		{
+			name:  "empty",
+			input: "   ",
+			isErr: true,
+		},
+		{
+			name:  "too long",
+			input: strings.Repeat("a", discord.MaxAnnouncementLength+1),
+			isErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := fsm.NewFSM("idle", fsm.Events{}, fsm.Callbacks{})
+			f.SetMetadata(discord.AnnounceText.String(), tc.input)
+			err := validateAnnouncement(f, discord.AnnounceText)
+			if tc.isErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			actual, _ := f.Metadata(discord.AnnounceText.String())
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
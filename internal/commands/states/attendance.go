@@ -0,0 +1,222 @@
+package states
+
+import (
+	"context"
+	"fmt"
+	"github.com/GuessWhoSamFoo/fsm"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/discord"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/role"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/store"
+	"github.com/bwmarrin/discordgo"
+	"strings"
+	"time"
+)
+
+type TakeAttendanceState struct {
+	session           *discordgo.Session
+	interactionCreate *discordgo.InteractionCreate
+	channel           *discordgo.Channel
+	printer           *discord.Printer
+	store             *store.Store
+
+	inputHandler *InputHandler
+}
+
+func NewTakeAttendanceState(o discord.Options) *TakeAttendanceState {
+	return &TakeAttendanceState{
+		session:           o.Session,
+		interactionCreate: o.InteractionCreate,
+		channel:           o.Channel,
+		printer:           o.Printer,
+		store:             o.Store,
+		inputHandler:      NewInputHandler(&o),
+	}
+}
+
+func (t *TakeAttendanceState) OnState(ctx context.Context, e *fsm.Event) {
+	if t.store == nil {
+		e.Err = fmt.Errorf("attendance store is not configured")
+		return
+	}
+	obj, err := Get(e.FSM, discord.EventObject)
+	if err != nil {
+		e.Err = err
+		return
+	}
+	event, ok := obj.(discord.Event)
+	if !ok {
+		e.Err = fmt.Errorf("cannot get event")
+		return
+	}
+
+	accepted := event.RoleGroup.GetUsers(role.AcceptedField)
+	reason := ""
+	switch {
+	case time.Now().Before(event.Start):
+		reason = "Attendance can be taken once the event starts"
+	case len(accepted) == 0:
+		reason = "Event doesn't have any accepted attendees"
+	}
+	if reason != "" {
+		if _, err = t.session.ChannelMessageSendEmbed(t.channel.ID, t.printer.Embed(&discordgo.MessageEmbed{
+			Title: reason,
+		})); err != nil {
+			e.Err = err
+			return
+		}
+		if err = e.FSM.Event(ctx, Cancel.String()); err != nil {
+			e.Err = err
+			return
+		}
+		e.Err = fmt.Errorf("cannot take attendance: %s", reason)
+		return
+	}
+
+	record, _ := t.store.Attendance(t.interactionCreate.Interaction.Message.ID)
+	var desc string
+	// Braille space is used instead because hard spaces in embeds are not documented
+	for i, n := range accepted {
+		mark := "⬜"
+		if record.HasCheckedIn(n) {
+			mark = "☑️"
+		}
+		desc += fmt.Sprintf("**%d**⠀%s %s\n", i+1, mark, n)
+	}
+	if _, err = t.session.ChannelMessageSendEmbed(t.channel.ID, t.printer.Embed(&discordgo.MessageEmbed{
+		Title:       "Who showed up?",
+		Description: desc,
+		Color:       discord.Purple,
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: discord.CheckInText + "\n" + discord.CancelText,
+		},
+	})); err != nil {
+		e.Err = err
+		return
+	}
+
+	if err = t.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.MenuOption, promptTimeout); err != nil {
+		e.Err = err
+		return
+	}
+	if err = recordCheckIns(e, t.store, t.session, t.channel, t.printer, t.interactionCreate, &event, accepted); err != nil {
+		eventErr := e.FSM.Event(ctx, TakeAttendanceRetry.String())
+		if eventErr != nil {
+			e.Err = fmt.Errorf("%v: %v", err, eventErr)
+			return
+		}
+	}
+}
+
+type TakeAttendanceRetryState struct {
+	session           *discordgo.Session
+	interactionCreate *discordgo.InteractionCreate
+	channel           *discordgo.Channel
+	printer           *discord.Printer
+	store             *store.Store
+
+	inputHandler *InputHandler
+}
+
+func NewTakeAttendanceRetryState(o discord.Options) *TakeAttendanceRetryState {
+	return &TakeAttendanceRetryState{
+		session:           o.Session,
+		interactionCreate: o.InteractionCreate,
+		channel:           o.Channel,
+		printer:           o.Printer,
+		store:             o.Store,
+		inputHandler:      NewInputHandler(&o),
+	}
+}
+
+func (t *TakeAttendanceRetryState) OnState(ctx context.Context, e *fsm.Event) {
+	if _, err := t.session.ChannelMessageSend(t.channel.ID, t.printer.T(discord.InvalidCheckInText)); err != nil {
+		e.Err = err
+		return
+	}
+	obj, err := Get(e.FSM, discord.EventObject)
+	if err != nil {
+		e.Err = err
+		return
+	}
+	event, ok := obj.(discord.Event)
+	if !ok {
+		e.Err = fmt.Errorf("cannot get event")
+		return
+	}
+
+	if err = t.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.MenuOption, promptTimeout); err != nil {
+		e.Err = err
+		return
+	}
+	if err = recordCheckIns(e, t.store, t.session, t.channel, t.printer, t.interactionCreate, &event, event.RoleGroup.GetUsers(role.AcceptedField)); err != nil {
+		eventErr := e.FSM.Event(ctx, SelfTransition.String())
+		if eventErr != nil {
+			e.Err = fmt.Errorf("%v: %v", err, eventErr)
+			return
+		}
+	}
+}
+
+// recordCheckIns toggles the check-in of each selected attendee and saves the result along with the event roster.
+// Typing none records that nobody else showed up.
+func recordCheckIns(e *fsm.Event, s *store.Store, session *discordgo.Session, c *discordgo.Channel, p *discord.Printer, i *discordgo.InteractionCreate, event *discord.Event, accepted []string) error {
+	val, err := Get(e.FSM, discord.MenuOption)
+	if err != nil {
+		return err
+	}
+	var selected []string
+	if !strings.EqualFold(strings.TrimSpace(fmt.Sprintf("%s", val)), "none") {
+		nameMap := map[int]string{}
+		for index, name := range accepted {
+			nameMap[index+1] = name
+		}
+		if selected, err = selectMultiple(e, nameMap); err != nil {
+			return err
+		}
+	}
+
+	channelID, messageID := i.Interaction.ChannelID, i.Interaction.Message.ID
+	prev, _ := s.Attendance(messageID)
+	record := discord.ToAttendance(event, channelID, messageID, time.Now())
+	record.TookAttendance = true
+	record.CheckedIn = toggleCheckIns(prev.CheckedIn, selected)
+	if err = s.RecordAttendance(record); err != nil {
+		return fmt.Errorf("cannot record attendance: %w", err)
+	}
+
+	_, err = session.ChannelMessageSendEmbed(c.ID, &discordgo.MessageEmbed{
+		Title: p.Tf("Checked in %d of %d attendees", countCheckedIn(record, accepted), len(accepted)),
+		Color: discord.Purple,
+	})
+	return err
+}
+
+// toggleCheckIns checks in selected members who were not checked in and checks out the rest
+func toggleCheckIns(checkedIn, selected []string) []string {
+	result := append([]string{}, checkedIn...)
+	for _, name := range selected {
+		index := -1
+		for i, n := range result {
+			if n == name {
+				index = i
+				break
+			}
+		}
+		if index >= 0 {
+			result = append(result[:index], result[index+1:]...)
+			continue
+		}
+		result = append(result, name)
+	}
+	return result
+}
+
+func countCheckedIn(record store.Attendance, accepted []string) int {
+	var count int
+	for _, n := range accepted {
+		if record.HasCheckedIn(n) {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,165 @@
+package states
+
+import (
+	"context"
+	"github.com/GuessWhoSamFoo/fsm"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/discord"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/role"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/store"
+	"github.com/bwmarrin/discordgo"
+	"github.com/ewohltman/discordgo-mock/mockconstants"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewTakeAttendanceState(t *testing.T) {
+	opts, err := discord.NewMockOptions()
+	assert.NoError(t, err)
+	s := NewTakeAttendanceState(*opts)
+	assert.NotNil(t, s)
+}
+
+func TestTakeAttendanceState_OnState(t *testing.T) {
+	opts, err := discord.NewMockOptions()
+	assert.NoError(t, err)
+	opts.InteractionCreate.Interaction.Message = &discordgo.Message{ID: "message"}
+
+	cases := []struct {
+		name              string
+		input             string
+		start             time.Time
+		expected          string
+		expectedCheckedIn []string
+		isErr             bool
+	}{
+		{
+			name:              "check in",
+			input:             "2",
+			start:             time.Now().Add(-time.Hour),
+			expected:          TakeAttendance.String(),
+			expectedCheckedIn: []string{"leo"},
+		},
+		{
+			name:     "nobody showed up",
+			input:    "none",
+			start:    time.Now().Add(-time.Hour),
+			expected: TakeAttendance.String(),
+		},
+		{
+			name:     "invalid",
+			input:    "3",
+			start:    time.Now().Add(-time.Hour),
+			expected: TakeAttendanceRetry.String(),
+		},
+		{
+			name:     "not started",
+			start:    time.Now().Add(time.Hour),
+			expected: Cancel.String(),
+			isErr:    true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			opts.Store, err = store.Open(filepath.Join(t.TempDir(), "store.json"))
+			assert.NoError(t, err)
+
+			rg := role.NewDefaultRoleGroup()
+			rg.Roles[0].Users = []string{mockconstants.TestUser, "leo"}
+			event := discord.Event{
+				Title:     "event",
+				Start:     tc.start,
+				RoleGroup: rg,
+				Owner:     mockconstants.TestUser,
+			}
+
+			s := NewTakeAttendanceState(*opts)
+			f := fsm.NewFSM(
+				"idle",
+				fsm.Events{
+					{
+						Name: TakeAttendance.String(),
+						Src:  []string{"idle"},
+						Dst:  TakeAttendance.String(),
+					},
+					{
+						Name: TakeAttendanceRetry.String(),
+						Src:  []string{TakeAttendance.String()},
+						Dst:  TakeAttendanceRetry.String(),
+					},
+					{
+						Name: Cancel.String(),
+						Src:  []string{TakeAttendance.String()},
+						Dst:  Cancel.String(),
+					},
+				},
+				fsm.Callbacks{
+					TakeAttendance.String(): s.OnState,
+				},
+			)
+			f.SetMetadata(discord.EventObject.String(), event)
+			s.inputHandler.handlerFunc = func(session *discordgo.Session, create *discordgo.MessageCreate) {
+				s.inputHandler.inputChan <- tc.input
+			}
+			var wg sync.WaitGroup
+			if tc.input != "" {
+				wg.Add(1)
+				go func() {
+					s.inputHandler.handlerFunc(opts.Session, &discordgo.MessageCreate{})
+					wg.Done()
+				}()
+			}
+			err = f.Event(context.TODO(), TakeAttendance.String())
+			if tc.isErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			wg.Wait()
+			assert.Equal(t, tc.expected, f.Current())
+
+			record, ok := opts.Store.Attendance("message")
+			if tc.expected != TakeAttendance.String() {
+				assert.False(t, ok)
+				return
+			}
+			assert.True(t, record.TookAttendance)
+			assert.ElementsMatch(t, tc.expectedCheckedIn, record.CheckedIn)
+		})
+	}
+}
+
+func Test_toggleCheckIns(t *testing.T) {
+	cases := []struct {
+		name      string
+		checkedIn []string
+		selected  []string
+		expected  []string
+	}{
+		{
+			name:     "check in",
+			selected: []string{"a", "b"},
+			expected: []string{"a", "b"},
+		},
+		{
+			name:      "check out",
+			checkedIn: []string{"a", "b"},
+			selected:  []string{"a"},
+			expected:  []string{"b"},
+		},
+		{
+			name:      "none selected",
+			checkedIn: []string{"a"},
+			expected:  []string{"a"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, toggleCheckIns(tc.checkedIn, tc.selected))
+		})
+	}
+}
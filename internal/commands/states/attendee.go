@@ -9,13 +9,13 @@ import (
 	"github.com/bwmarrin/discordgo"
 	"strconv"
 	"strings"
-	"time"
 )
 
 type SetAttendeeState struct {
 	session           *discordgo.Session
 	interactionCreate *discordgo.InteractionCreate
 	channel           *discordgo.Channel
+	printer           *discord.Printer
 
 	inputHandler *InputHandler
 }
@@ -25,18 +25,19 @@ func NewSetAttendeeState(o discord.Options) *SetAttendeeState {
 		session:           o.Session,
 		interactionCreate: o.InteractionCreate,
 		channel:           o.Channel,
+		printer:           o.Printer,
 		inputHandler:      NewInputHandler(&o),
 	}
 }
 
 func (s *SetAttendeeState) OnState(ctx context.Context, e *fsm.Event) {
-	_, err := s.session.ChannelMessageSendEmbed(s.channel.ID, &discord.EnterAttendeeLimitMessage)
+	_, err := s.session.ChannelMessageSendEmbed(s.channel.ID, s.printer.Embed(&discord.EnterAttendeeLimitMessage))
 	if err != nil {
 		e.Err = err
 		return
 	}
 
-	if err = s.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.Attendee, 60*time.Second); err != nil {
+	if err = s.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.Attendee, promptTimeout); err != nil {
 		e.Err = err
 		return
 	}
@@ -54,6 +55,7 @@ type SetAttendeeRetryState struct {
 	session           *discordgo.Session
 	interactionCreate *discordgo.InteractionCreate
 	channel           *discordgo.Channel
+	printer           *discord.Printer
 
 	inputHandler *InputHandler
 }
@@ -63,17 +65,18 @@ func NewSetAttendeeRetryState(o discord.Options) *SetAttendeeRetryState {
 		session:           o.Session,
 		interactionCreate: o.InteractionCreate,
 		channel:           o.Channel,
+		printer:           o.Printer,
 		inputHandler:      NewInputHandler(&o),
 	}
 }
 
 func (r *SetAttendeeRetryState) OnState(ctx context.Context, e *fsm.Event) {
-	_, err := r.session.ChannelMessageSend(r.channel.ID, discord.InvalidEventLimitText)
+	_, err := r.session.ChannelMessageSend(r.channel.ID, r.printer.T(discord.InvalidEventLimitText))
 	if err != nil {
 		e.Err = err
 		return
 	}
-	if err = r.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.Attendee, 60*time.Second); err != nil {
+	if err = r.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.Attendee, promptTimeout); err != nil {
 		e.Err = err
 		return
 	}
@@ -0,0 +1,76 @@
+package states
+
+import (
+	"github.com/GuessWhoSamFoo/fsm"
+)
+
+// historyKey keeps the prompts answered so far, which "back" returns to
+const historyKey = "promptHistory"
+
+// retries are the states asking a prompt again after an invalid answer. They count as the prompt they retry, so going
+// back from a retry skips the prompt too.
+var retries = map[chatState]chatState{
+	ResumeDraftRetry:       ResumeDraft,
+	SetAttendeeRetry:       SetAttendeeLimit,
+	SetDateRetry:           SetDate,
+	ConfirmDateRetry:       ConfirmDate,
+	SetDurationRetry:       SetDuration,
+	SetDeadlineRetry:       SetDeadline,
+	SetGuestsRetry:         SetGuests,
+	SetCategoryRetry:       SetCategory,
+	ReviewEventRetry:       ReviewEvent,
+	ReviewFieldRetry:       ReviewField,
+	SchedulePublishRetry:   SchedulePublish,
+	StartEditRetry:         StartEdit,
+	ModifyEventRetry:       ModifyEvent,
+	SetChannelRetry:        SetChannel,
+	SetOwnerRetry:          SetOwner,
+	RemoveResponseRetry:    RemoveResponse,
+	ContinueEditRetry:      ContinueEdit,
+	TakeAttendanceRetry:    TakeAttendance,
+	MessageAttendeesRetry:  MessageAttendees,
+	EnterAnnouncementRetry: EnterAnnouncement,
+	UnknownUserRetry:       UnknownUser,
+	SignUpRetry:            SignUp,
+}
+
+// Prompt is the prompt a state asks, which is the state itself unless it retries another
+func Prompt(state string) string {
+	if prompt, ok := retries[chatState(state)]; ok {
+		return prompt.String()
+	}
+	return state
+}
+
+// PreviousPrompt leaves the prompt where "back" was typed and returns the one answered before it, which is asked
+// again with its earlier answer kept until a new one is given
+func PreviousPrompt(f *fsm.FSM) (string, bool) {
+	history := promptHistory(f)
+	if len(history) < 2 {
+		return "", false
+	}
+	previous := history[len(history)-2]
+	f.SetMetadata(historyKey, history[:len(history)-2])
+	return previous, true
+}
+
+// visitPrompt adds the prompt of the current state to the history, once for a prompt and its retries
+func visitPrompt(f *fsm.FSM) {
+	history := promptHistory(f)
+	prompt := Prompt(f.Current())
+	if len(history) > 0 && history[len(history)-1] == prompt {
+		return
+	}
+	f.SetMetadata(historyKey, append(history, prompt))
+}
+
+// hasPreviousPrompt reports whether there is a prompt before the current one to go back to
+func hasPreviousPrompt(f *fsm.FSM) bool {
+	return len(promptHistory(f)) > 1
+}
+
+func promptHistory(f *fsm.FSM) []string {
+	val, _ := f.Metadata(historyKey)
+	history, _ := val.([]string)
+	return history
+}
@@ -0,0 +1,32 @@
+package states
+
+import (
+	"github.com/GuessWhoSamFoo/fsm"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPrompt(t *testing.T) {
+	assert.Equal(t, SetDate.String(), Prompt(SetDateRetry.String()))
+	assert.Equal(t, SetAttendeeLimit.String(), Prompt(SetAttendeeRetry.String()))
+	assert.Equal(t, AddTitle.String(), Prompt(AddTitle.String()))
+}
+
+func TestPreviousPrompt(t *testing.T) {
+	f := fsm.NewFSM(AddTitle.String(), fsm.Events{}, fsm.Callbacks{})
+	_, ok := PreviousPrompt(f)
+	assert.False(t, ok)
+
+	for _, state := range []string{AddTitle.String(), SetDate.String(), SetDateRetry.String(), SetDateRetry.String()} {
+		f.SetState(state)
+		visitPrompt(f)
+	}
+	assert.Equal(t, []string{AddTitle.String(), SetDate.String()}, promptHistory(f))
+	assert.True(t, hasPreviousPrompt(f))
+
+	previous, ok := PreviousPrompt(f)
+	assert.True(t, ok)
+	assert.Equal(t, AddTitle.String(), previous)
+	assert.Empty(t, promptHistory(f))
+	assert.False(t, hasPreviousPrompt(f))
+}
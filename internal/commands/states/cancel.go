@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/GuessWhoSamFoo/fsm"
 	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/discord"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/metrics"
 )
 
 type CancelState struct {
@@ -23,8 +24,9 @@ func (c *CancelState) OnState(_ context.Context, e *fsm.Event) {
 		e.Err = err
 		return
 	}
+	metrics.RecordFSMOutcome(fmt.Sprint(action), metrics.Canceled)
 
-	_, err = c.Session.ChannelMessageSend(c.Channel.ID, fmt.Sprintf("Event %s has been canceled", action))
+	_, err = c.Session.ChannelMessageSend(c.Channel.ID, c.Printer.Tf("Event %s has been canceled", c.Printer.T(fmt.Sprint(action))))
 	if err != nil {
 		e.Err = err
 	}
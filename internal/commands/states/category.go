@@ -0,0 +1,119 @@
+package states
+
+import (
+	"context"
+	"fmt"
+	"github.com/GuessWhoSamFoo/fsm"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/discord"
+	"github.com/bwmarrin/discordgo"
+	"strconv"
+	"strings"
+)
+
+type SetCategoryState struct {
+	session           *discordgo.Session
+	interactionCreate *discordgo.InteractionCreate
+	channel           *discordgo.Channel
+	printer           *discord.Printer
+	categories        []discord.Category
+
+	inputHandler *InputHandler
+}
+
+func NewCategoryState(o discord.Options) *SetCategoryState {
+	return &SetCategoryState{
+		session:           o.Session,
+		interactionCreate: o.InteractionCreate,
+		channel:           o.Channel,
+		printer:           o.Printer,
+		categories:        o.Categories,
+		inputHandler:      NewInputHandler(&o),
+	}
+}
+
+func (c *SetCategoryState) OnState(ctx context.Context, e *fsm.Event) {
+	if len(c.categories) == 0 {
+		return
+	}
+	_, err := c.session.ChannelMessageSendEmbed(c.channel.ID, c.printer.Embed(discord.EnterCategoryMessage(c.categories)))
+	if err != nil {
+		e.Err = err
+		return
+	}
+
+	if err = c.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.CategoryName, promptTimeout); err != nil {
+		e.Err = err
+		return
+	}
+	if err = validateCategory(e.FSM, discord.CategoryName, c.categories); err != nil {
+		eventErr := e.FSM.Event(ctx, SetCategoryRetry.String())
+		if eventErr != nil {
+			e.Err = fmt.Errorf("%v: %v", err, eventErr)
+			return
+		}
+	}
+}
+
+type SetCategoryRetryState struct {
+	session           *discordgo.Session
+	interactionCreate *discordgo.InteractionCreate
+	channel           *discordgo.Channel
+	printer           *discord.Printer
+	categories        []discord.Category
+
+	inputHandler *InputHandler
+}
+
+func NewCategoryRetryState(o discord.Options) *SetCategoryRetryState {
+	return &SetCategoryRetryState{
+		session:           o.Session,
+		interactionCreate: o.InteractionCreate,
+		channel:           o.Channel,
+		printer:           o.Printer,
+		categories:        o.Categories,
+		inputHandler:      NewInputHandler(&o),
+	}
+}
+
+func (c *SetCategoryRetryState) OnState(ctx context.Context, e *fsm.Event) {
+	_, err := c.session.ChannelMessageSend(c.channel.ID, c.printer.T(discord.InvalidCategoryText))
+	if err != nil {
+		e.Err = err
+		return
+	}
+
+	if err = c.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.CategoryName, promptTimeout); err != nil {
+		e.Err = err
+		return
+	}
+	if err = validateCategory(e.FSM, discord.CategoryName, c.categories); err != nil {
+		eventErr := e.FSM.Event(ctx, SelfTransition.String())
+		if eventErr != nil {
+			e.Err = fmt.Errorf("%v: %v", err, eventErr)
+			return
+		}
+	}
+}
+
+// validateCategory gets a category by its number in the list or its name. `None` removes the category.
+func validateCategory(f *fsm.FSM, key discord.MetadataKey, categories []discord.Category) error {
+	val, err := Get(f, key)
+	if err != nil {
+		return err
+	}
+	input := strings.TrimSpace(fmt.Sprintf("%v", val))
+	if strings.EqualFold(input, "none") {
+		f.SetMetadata(key.String(), discord.Category{})
+		return nil
+	}
+	if n, err := strconv.Atoi(input); err == nil && InRange(n, len(categories), 1) {
+		f.SetMetadata(key.String(), categories[n-1])
+		return nil
+	}
+	if category, ok := discord.FindCategory(categories, input); ok {
+		f.SetMetadata(key.String(), category)
+		return nil
+	}
+	f.SetMetadata(key.String(), discord.Category{})
+	return fmt.Errorf("unknown category: %s", input)
+}
@@ -0,0 +1,62 @@
+package states
+
+import (
+	"github.com/GuessWhoSamFoo/fsm"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/discord"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewCategoryState(t *testing.T) {
+	opts, err := discord.NewMockOptions()
+	assert.NoError(t, err)
+
+	s := NewCategoryState(*opts)
+	assert.NotNil(t, s)
+}
+
+func Test_validateCategory(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    string
+		expected discord.Category
+		isErr    bool
+	}{
+		{
+			name:     "number",
+			input:    "2",
+			expected: discord.DefaultCategories[1],
+		},
+		{
+			name:     "name",
+			input:    "trip",
+			expected: discord.DefaultCategories[3],
+		},
+		{
+			name:     "none",
+			input:    "None",
+			expected: discord.Category{},
+		},
+		{
+			name:     "out of bounds",
+			input:    "9",
+			expected: discord.Category{},
+			isErr:    true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := fsm.NewFSM("idle", fsm.Events{}, fsm.Callbacks{})
+			f.SetMetadata(discord.CategoryName.String(), tc.input)
+			err := validateCategory(f, discord.CategoryName, discord.DefaultCategories)
+			if tc.isErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			actual, _ := f.Metadata(discord.CategoryName.String())
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
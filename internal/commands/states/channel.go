@@ -0,0 +1,129 @@
+package states
+
+import (
+	"context"
+	"fmt"
+	"github.com/GuessWhoSamFoo/fsm"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/discord"
+	"github.com/bwmarrin/discordgo"
+	"strings"
+)
+
+type SetChannelState struct {
+	session           *discordgo.Session
+	interactionCreate *discordgo.InteractionCreate
+	channel           *discordgo.Channel
+	printer           *discord.Printer
+
+	inputHandler *InputHandler
+}
+
+func NewSetChannelState(o discord.Options) *SetChannelState {
+	return &SetChannelState{
+		session:           o.Session,
+		interactionCreate: o.InteractionCreate,
+		channel:           o.Channel,
+		printer:           o.Printer,
+		inputHandler:      NewInputHandler(&o),
+	}
+}
+
+func (c *SetChannelState) OnState(ctx context.Context, e *fsm.Event) {
+	_, err := c.session.ChannelMessageSendEmbed(c.channel.ID, c.printer.Embed(&discord.EnterChannelMessage))
+	if err != nil {
+		e.Err = err
+		return
+	}
+
+	if err = c.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.ChannelID, promptTimeout); err != nil {
+		e.Err = err
+		return
+	}
+	if err = validateChannel(c.session, e.FSM, c.interactionCreate.GuildID); err != nil {
+		eventErr := e.FSM.Event(ctx, SetChannelRetry.String())
+		if eventErr != nil {
+			e.Err = fmt.Errorf("%v: %v", err, eventErr)
+			return
+		}
+	}
+}
+
+type SetChannelRetryState struct {
+	session           *discordgo.Session
+	interactionCreate *discordgo.InteractionCreate
+	channel           *discordgo.Channel
+	printer           *discord.Printer
+
+	inputHandler *InputHandler
+}
+
+func NewSetChannelRetryState(o discord.Options) *SetChannelRetryState {
+	return &SetChannelRetryState{
+		session:           o.Session,
+		interactionCreate: o.InteractionCreate,
+		channel:           o.Channel,
+		printer:           o.Printer,
+		inputHandler:      NewInputHandler(&o),
+	}
+}
+
+func (c *SetChannelRetryState) OnState(ctx context.Context, e *fsm.Event) {
+	_, err := c.session.ChannelMessageSend(c.channel.ID, c.printer.T(discord.InvalidChannelText))
+	if err != nil {
+		e.Err = err
+		return
+	}
+
+	if err = c.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.ChannelID, promptTimeout); err != nil {
+		e.Err = err
+		return
+	}
+	if err = validateChannel(c.session, e.FSM, c.interactionCreate.GuildID); err != nil {
+		eventErr := e.FSM.Event(ctx, SelfTransition.String())
+		if eventErr != nil {
+			e.Err = fmt.Errorf("%v: %v", err, eventErr)
+			return
+		}
+	}
+}
+
+// validateChannel gets a text channel of the guild the bot can post in. An invalid channel is cleared so the event
+// stays where it is.
+func validateChannel(s *discordgo.Session, f *fsm.FSM, guildID string) error {
+	val, err := Get(f, discord.ChannelID)
+	if err != nil {
+		return err
+	}
+	f.SetMetadata(discord.ChannelID.String(), "")
+	channels, err := s.GuildChannels(guildID)
+	if err != nil {
+		return err
+	}
+	c, ok := findChannel(channels, fmt.Sprintf("%v", val))
+	if !ok {
+		return fmt.Errorf("unknown channel: %v", val)
+	}
+	if err = discord.CheckCanPost(s, c.ID); err != nil {
+		return err
+	}
+	f.SetMetadata(discord.ChannelID.String(), c.ID)
+	return nil
+}
+
+// findChannel gets a text channel by its mention, ID or name
+func findChannel(channels []*discordgo.Channel, input string) (*discordgo.Channel, bool) {
+	input = strings.TrimSpace(input)
+	if strings.HasPrefix(input, "<#") && strings.HasSuffix(input, ">") {
+		input = strings.TrimSuffix(strings.TrimPrefix(input, "<#"), ">")
+	}
+	input = strings.TrimPrefix(input, "#")
+	for _, c := range channels {
+		if c.Type != discordgo.ChannelTypeGuildText && c.Type != discordgo.ChannelTypeGuildNews {
+			continue
+		}
+		if c.ID == input || strings.EqualFold(c.Name, input) {
+			return c, true
+		}
+	}
+	return nil, false
+}
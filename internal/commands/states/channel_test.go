@@ -0,0 +1,86 @@
+package states
+
+import (
+	"github.com/GuessWhoSamFoo/fsm"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/discord"
+	"github.com/bwmarrin/discordgo"
+	"github.com/ewohltman/discordgo-mock/mockconstants"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewSetChannelState(t *testing.T) {
+	opts, err := discord.NewMockOptions()
+	assert.NoError(t, err)
+
+	s := NewSetChannelState(*opts)
+	assert.NotNil(t, s)
+}
+
+func TestNewSetChannelRetryState(t *testing.T) {
+	opts, err := discord.NewMockOptions()
+	assert.NoError(t, err)
+
+	s := NewSetChannelRetryState(*opts)
+	assert.NotNil(t, s)
+}
+
+func Test_validateChannel(t *testing.T) {
+	opts, err := discord.NewMockOptions()
+	assert.NoError(t, err)
+
+	// The mock guild only has voice channels, which events cannot be posted in
+	f := fsm.NewFSM("idle", fsm.Events{}, fsm.Callbacks{})
+	f.SetMetadata(discord.ChannelID.String(), "<#"+mockconstants.TestChannel+">")
+	assert.Error(t, validateChannel(opts.Session, f, mockconstants.TestGuild))
+	actual, _ := f.Metadata(discord.ChannelID.String())
+	assert.Equal(t, "", actual)
+}
+
+func Test_findChannel(t *testing.T) {
+	channels := []*discordgo.Channel{
+		{ID: "1", Name: "events", Type: discordgo.ChannelTypeGuildText},
+		{ID: "2", Name: "announcements", Type: discordgo.ChannelTypeGuildNews},
+		{ID: "3", Name: "lounge", Type: discordgo.ChannelTypeGuildVoice},
+	}
+
+	cases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "mention",
+			input:    "<#1>",
+			expected: "1",
+		},
+		{
+			name:     "id",
+			input:    "2",
+			expected: "2",
+		},
+		{
+			name:     "name",
+			input:    " #Events ",
+			expected: "1",
+		},
+		{
+			name:  "voice",
+			input: "lounge",
+		},
+		{
+			name:  "unknown",
+			input: "general",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, ok := findChannel(channels, tc.input)
+			assert.Equal(t, tc.expected != "", ok)
+			if ok {
+				assert.Equal(t, tc.expected, c.ID)
+			}
+		})
+	}
+}
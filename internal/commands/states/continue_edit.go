@@ -6,13 +6,13 @@ import (
 	"github.com/GuessWhoSamFoo/fsm"
 	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/discord"
 	"github.com/bwmarrin/discordgo"
-	"time"
 )
 
 type ContinueEditState struct {
 	session           *discordgo.Session
 	interactionCreate *discordgo.InteractionCreate
 	channel           *discordgo.Channel
+	printer           *discord.Printer
 
 	inputHandler *InputHandler
 }
@@ -22,17 +22,18 @@ func NewContinueEditState(o discord.Options) *ContinueEditState {
 		session:           o.Session,
 		interactionCreate: o.InteractionCreate,
 		channel:           o.Channel,
+		printer:           o.Printer,
 		inputHandler:      NewInputHandler(&o),
 	}
 }
 
 func (c *ContinueEditState) OnState(ctx context.Context, e *fsm.Event) {
-	if _, err := c.session.ChannelMessageSendEmbed(c.channel.ID, &discord.EditConfirmationMessage); err != nil {
+	if _, err := c.session.ChannelMessageSendEmbed(c.channel.ID, c.printer.Embed(discord.ContinueEditMessage(editChanges(e.FSM)))); err != nil {
 		e.Err = err
 		return
 	}
 
-	if err := c.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.MenuOption, 60*time.Second); err != nil {
+	if err := c.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.MenuOption, promptTimeout); err != nil {
 		e.Err = err
 		return
 	}
@@ -60,6 +61,7 @@ type ContinueEditRetryState struct {
 	session           *discordgo.Session
 	interactionCreate *discordgo.InteractionCreate
 	channel           *discordgo.Channel
+	printer           *discord.Printer
 
 	inputHandler *InputHandler
 }
@@ -69,17 +71,18 @@ func NewContinueEditRetryState(o discord.Options) *ContinueEditRetryState {
 		session:           o.Session,
 		interactionCreate: o.InteractionCreate,
 		channel:           o.Channel,
+		printer:           o.Printer,
 		inputHandler:      NewInputHandler(&o),
 	}
 }
 
 func (c *ContinueEditRetryState) OnState(ctx context.Context, e *fsm.Event) {
-	if _, err := c.session.ChannelMessageSend(c.channel.ID, discord.InvalidEntryText); err != nil {
+	if _, err := c.session.ChannelMessageSend(c.channel.ID, c.printer.T(discord.InvalidEntryText)); err != nil {
 		e.Err = err
 		return
 	}
 
-	if err := c.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.MenuOption, 60*time.Second); err != nil {
+	if err := c.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.MenuOption, promptTimeout); err != nil {
 		e.Err = err
 		return
 	}
@@ -113,10 +116,20 @@ func ConfirmSelect(e *fsm.Event) (string, error) {
 	opts := map[string]chatState{
 		"1": ProcessEdit,
 		"2": ModifyEvent,
+		"3": ProcessEdit,
 	}
 	option, ok := opts[val.(string)]
 	if !ok {
 		return "", fmt.Errorf("cannot find %s response", e.FSM.Current())
 	}
+	// Option 3 finishes the edit without messaging attendees about the new time or location
+	e.FSM.SetMetadata(discord.Notify.String(), val.(string) != "3")
 	return option.String(), nil
 }
+
+// editChanges are the fields changed so far by the edit
+func editChanges(f *fsm.FSM) []discord.Change {
+	val, _ := f.Metadata(discord.Changes.String())
+	changes, _ := val.([]discord.Change)
+	return changes
+}
@@ -189,3 +189,33 @@ func TestContinueEditRetryState_OnState(t *testing.T) {
 		})
 	}
 }
+
+func TestConfirmSelect(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected string
+		notify   bool
+		isErr    bool
+	}{
+		{input: "1", expected: ProcessEdit.String(), notify: true},
+		{input: "2", expected: ModifyEvent.String(), notify: true},
+		{input: "3", expected: ProcessEdit.String(), notify: false},
+		{input: "4", isErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.input, func(t *testing.T) {
+			f := fsm.NewFSM("idle", fsm.Events{}, fsm.Callbacks{})
+			f.SetMetadata(discord.MenuOption.String(), tc.input)
+			actual, err := ConfirmSelect(&fsm.Event{FSM: f})
+			assert.Equal(t, tc.expected, actual)
+			if tc.isErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			notify, _ := f.Metadata(discord.Notify.String())
+			assert.Equal(t, tc.notify, notify)
+		})
+	}
+}
@@ -2,9 +2,11 @@ package states
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/GuessWhoSamFoo/fsm"
 	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/discord"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/pkg/timeparse"
 	"github.com/araddon/dateparse"
 	"github.com/bwmarrin/discordgo"
 	"github.com/tj/go-naturaldate"
@@ -16,6 +18,7 @@ type SetDateState struct {
 	session           *discordgo.Session
 	interactionCreate *discordgo.InteractionCreate
 	channel           *discordgo.Channel
+	printer           *discord.Printer
 
 	inputHandler *InputHandler
 }
@@ -25,28 +28,37 @@ func NewSetDateState(o discord.Options) *SetDateState {
 		session:           o.Session,
 		interactionCreate: o.InteractionCreate,
 		channel:           o.Channel,
+		printer:           o.Printer,
 		inputHandler:      NewInputHandler(&o),
 	}
 }
 
 func (d *SetDateState) OnState(ctx context.Context, e *fsm.Event) {
-	_, err := d.session.ChannelMessageSendEmbed(d.channel.ID, &discord.EnterDateStartMessage)
+	_, err := d.session.ChannelMessageSendEmbed(d.channel.ID, d.printer.Embed(&discord.EnterDateStartMessage))
 	if err != nil {
 		e.Err = err
 		return
 	}
 
-	if err = d.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.StartTime, 60*time.Second); err != nil {
+	if err = d.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.StartTime, promptTimeout); err != nil {
 		e.Err = err
 		return
 	}
 
-	if err = validateTime(e, d.session, d.channel, discord.StartTime); err != nil {
+	ambiguous, err := validateTime(e, d.session, d.channel, d.printer, discord.StartTime)
+	if err != nil {
 		eventErr := e.FSM.Event(ctx, SetDateRetry.String())
 		if eventErr != nil {
 			e.Err = fmt.Errorf("%v: %v", err, eventErr)
 			return
 		}
+		return
+	}
+	if ambiguous {
+		if err = e.FSM.Event(ctx, ConfirmDate.String()); err != nil {
+			e.Err = err
+			return
+		}
 	}
 }
 
@@ -54,6 +66,7 @@ type SetDateRetryState struct {
 	session           *discordgo.Session
 	interactionCreate *discordgo.InteractionCreate
 	channel           *discordgo.Channel
+	printer           *discord.Printer
 
 	inputHandler *InputHandler
 }
@@ -63,54 +76,213 @@ func NewSetDateRetryState(o discord.Options) *SetDateRetryState {
 		session:           o.Session,
 		interactionCreate: o.InteractionCreate,
 		channel:           o.Channel,
+		printer:           o.Printer,
 		inputHandler:      NewInputHandler(&o),
 	}
 }
 
 func (r *SetDateRetryState) OnState(ctx context.Context, e *fsm.Event) {
-	_, err := r.session.ChannelMessageSend(r.channel.ID, discord.InvalidEventTimeText)
+	_, err := r.session.ChannelMessageSend(r.channel.ID, r.printer.T(discord.InvalidEventTimeText))
 	if err != nil {
 		e.Err = err
 		return
 	}
 
-	if err = r.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.StartTime, 60*time.Second); err != nil {
+	if err = r.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.StartTime, promptTimeout); err != nil {
 		e.Err = err
 		return
 	}
-	if err = validateTime(e, r.session, r.channel, discord.StartTime); err != nil {
+	ambiguous, err := validateTime(e, r.session, r.channel, r.printer, discord.StartTime)
+	if err != nil {
 		eventErr := e.FSM.Event(ctx, SelfTransition.String())
 		if eventErr != nil {
 			e.Err = fmt.Errorf("%v: %v", err, eventErr)
 			return
 		}
+		return
+	}
+	if ambiguous {
+		if err = e.FSM.Event(ctx, ConfirmDate.String()); err != nil {
+			e.Err = err
+			return
+		}
+	}
+}
+
+// ConfirmDateState echoes back a time which could be read more than one way, such as "8" or "next friday"
+type ConfirmDateState struct {
+	session           *discordgo.Session
+	interactionCreate *discordgo.InteractionCreate
+	channel           *discordgo.Channel
+	printer           *discord.Printer
+
+	inputHandler *InputHandler
+}
+
+func NewConfirmDateState(o discord.Options) *ConfirmDateState {
+	return &ConfirmDateState{
+		session:           o.Session,
+		interactionCreate: o.InteractionCreate,
+		channel:           o.Channel,
+		printer:           o.Printer,
+		inputHandler:      NewInputHandler(&o),
 	}
 }
 
-func validateTime(e *fsm.Event, s *discordgo.Session, c *discordgo.Channel, key discord.MetadataKey) error {
+func (c *ConfirmDateState) OnState(ctx context.Context, e *fsm.Event) {
+	start, err := Get(e.FSM, discord.StartTime)
+	if err != nil {
+		e.Err = err
+		return
+	}
+	startTime, _ := start.(time.Time)
+	var endTime time.Time
+	if end, found := e.FSM.Metadata(discord.Duration.String()); found {
+		endTime, _ = end.(time.Time)
+	}
+	if _, err = c.session.ChannelMessageSendEmbed(c.channel.ID, c.printer.Embed(discord.ConfirmDateMessage(startTime, endTime))); err != nil {
+		e.Err = err
+		return
+	}
+
+	if err = c.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.MenuOption, promptTimeout); err != nil {
+		e.Err = err
+		return
+	}
+	state, err := ConfirmDateSelect(e)
+	if err != nil {
+		eventErr := e.FSM.Event(ctx, ConfirmDateRetry.String())
+		if eventErr != nil {
+			e.Err = fmt.Errorf("%v: %v", err, eventErr)
+			return
+		}
+		return
+	}
+	if state == "" {
+		return
+	}
+	rejectDate(e.FSM)
+	if err = e.FSM.Event(ctx, state); err != nil {
+		e.Err = err
+		return
+	}
+}
+
+type ConfirmDateRetryState struct {
+	session           *discordgo.Session
+	interactionCreate *discordgo.InteractionCreate
+	channel           *discordgo.Channel
+	printer           *discord.Printer
+
+	inputHandler *InputHandler
+}
+
+func NewConfirmDateRetryState(o discord.Options) *ConfirmDateRetryState {
+	return &ConfirmDateRetryState{
+		session:           o.Session,
+		interactionCreate: o.InteractionCreate,
+		channel:           o.Channel,
+		printer:           o.Printer,
+		inputHandler:      NewInputHandler(&o),
+	}
+}
+
+func (c *ConfirmDateRetryState) OnState(ctx context.Context, e *fsm.Event) {
+	if _, err := c.session.ChannelMessageSend(c.channel.ID, c.printer.T(discord.InvalidEntryText)); err != nil {
+		e.Err = err
+		return
+	}
+
+	if err := c.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.MenuOption, promptTimeout); err != nil {
+		e.Err = err
+		return
+	}
+	state, err := ConfirmDateSelect(e)
+	if err != nil {
+		eventErr := e.FSM.Event(ctx, SelfTransition.String())
+		if eventErr != nil {
+			e.Err = fmt.Errorf("%v: %v", err, eventErr)
+			return
+		}
+		return
+	}
+	if state == "" {
+		return
+	}
+	rejectDate(e.FSM)
+	if err = e.FSM.Event(ctx, state); err != nil {
+		e.Err = err
+		return
+	}
+}
+
+// ConfirmDateSelect returns the state to go to after the member answers whether a time was read correctly. An
+// empty state means the time is confirmed.
+func ConfirmDateSelect(e *fsm.Event) (string, error) {
+	val, err := Get(e.FSM, discord.MenuOption)
+	if err != nil {
+		return "", err
+	}
+
+	opts := map[string]chatState{
+		"1": "",
+		"2": SetDate,
+	}
+	option, ok := opts[val.(string)]
+	if !ok {
+		return "", fmt.Errorf("cannot find %s response", e.FSM.Current())
+	}
+	return option.String(), nil
+}
+
+// rejectDate drops the end time from a rejected range in favor of the one saved on the event, if any
+func rejectDate(f *fsm.FSM) {
+	var end time.Time
+	if obj, found := f.Metadata(discord.EventObject.String()); found {
+		if event, ok := obj.(discord.Event); ok {
+			end = event.End
+		}
+	}
+	f.SetMetadata(discord.Duration.String(), end)
+}
+
+// validateTime saves the start time, and the end time when a range such as "8pm-midnight" is given. It reports
+// whether the input could be read more than one way.
+func validateTime(e *fsm.Event, s *discordgo.Session, c *discordgo.Channel, p *discord.Printer, key discord.MetadataKey) (bool, error) {
 	val, err := Get(e.FSM, key)
 	if err != nil {
-		return err
+		return false, err
 	}
 	input := fmt.Sprintf("%v", val)
 	now := time.Now()
 
-	var startTime time.Time
-	startTime, err = naturaldate.Parse(input, now, naturaldate.WithDirection(naturaldate.Future))
+	r, err := timeparse.Parse(input, now)
+	if errors.Is(err, timeparse.ErrNoTime) || errors.Is(err, timeparse.ErrEndBeforeStart) {
+		_, msgErr := s.ChannelMessageSend(c.ID, p.T(discord.InvalidStartTimeText))
+		return false, fmt.Errorf("%v: %v", err, msgErr)
+	}
 	if err != nil {
-		startTime, err = dateparse.ParseLocal(input)
+		// Other parsers guess at formats the time parser does not know, so the organizer confirms what they read
+		r = timeparse.Range{Ambiguous: true}
+		r.Start, err = naturaldate.Parse(input, now, naturaldate.WithDirection(naturaldate.Future))
 		if err != nil {
-			_, msgErr := s.ChannelMessageSend(c.ID, discord.InvalidStartTimeText)
-			return fmt.Errorf("%v: %v", err, msgErr)
+			r.Start, err = dateparse.ParseLocal(input)
+			if err != nil {
+				_, msgErr := s.ChannelMessageSend(c.ID, p.T(discord.InvalidStartTimeText))
+				return false, fmt.Errorf("%v: %v", err, msgErr)
+			}
+		}
+		if !strings.EqualFold(input, "now") && r.Start.Equal(now) {
+			return false, fmt.Errorf("failed to parse time")
 		}
-	}
-	if !strings.EqualFold(input, "now") && startTime.Equal(now) {
-		return fmt.Errorf("failed to parse time")
 	}
 
-	if startTime.Before(now) {
-		return fmt.Errorf("start time cannot be in the past")
+	if r.Start.Before(now) {
+		return false, fmt.Errorf("start time cannot be in the past")
+	}
+	e.FSM.SetMetadata(discord.StartTime.String(), r.Start)
+	if !r.End.IsZero() {
+		e.FSM.SetMetadata(discord.Duration.String(), r.End)
 	}
-	e.FSM.SetMetadata(discord.StartTime.String(), startTime)
-	return nil
+	return r.Ambiguous, nil
 }
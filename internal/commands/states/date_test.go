@@ -30,43 +30,79 @@ func TestNewSetDateRetryState(t *testing.T) {
 func TestSetDateState_OnState(t *testing.T) {
 	opts, err := discord.NewMockOptions()
 	assert.NoError(t, err)
+	cur := time.Now()
 
-	s := NewSetDateState(*opts)
-
-	f := fsm.NewFSM(
-		"idle",
-		fsm.Events{
-			{
-				Name: SetDate.String(),
-				Src:  []string{"idle"},
-				Dst:  SetDate.String(),
-			},
+	cases := []struct {
+		name          string
+		input         string
+		expectedState string
+		expectedStart time.Time
+	}{
+		{
+			name:          "day and time",
+			input:         "tomorrow 7pm",
+			expectedState: SetDate.String(),
+			expectedStart: time.Date(cur.Year(), cur.Month(), cur.Day()+1, 19, 0, 0, 0, cur.Location()),
 		},
-		fsm.Callbacks{
-			SetDate.String(): s.OnState,
+		{
+			name:          "weekday without a time",
+			input:         "friday",
+			expectedState: SetDateRetry.String(),
+		},
+		{
+			name:          "part of day",
+			input:         "tomorrow evening",
+			expectedState: SetDateRetry.String(),
 		},
-	)
-	s.inputHandler.handlerFunc = func(session *discordgo.Session, create *discordgo.MessageCreate) {
-		s.inputHandler.inputChan <- "tomorrow"
 	}
-	var wg sync.WaitGroup
-	wg.Add(2)
-	go func() {
-		s.inputHandler.handlerFunc(opts.Session, &discordgo.MessageCreate{})
-		wg.Done()
-	}()
-	go func() {
-		err = f.Event(context.TODO(), SetDate.String())
-		assert.NoError(t, err)
-		wg.Done()
-	}()
-	wg.Wait()
-	actual, err := Get(f, discord.StartTime)
-	assert.NoError(t, err)
 
-	cur := time.Now()
-	expected := time.Date(cur.Year(), cur.Month(), cur.Day()+1, 0, 0, 0, 0, cur.Location())
-	assert.Equal(t, expected, actual)
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewSetDateState(*opts)
+			f := fsm.NewFSM(
+				"idle",
+				fsm.Events{
+					{
+						Name: SetDate.String(),
+						Src:  []string{"idle"},
+						Dst:  SetDate.String(),
+					},
+					{
+						Name: SetDateRetry.String(),
+						Src:  []string{SetDate.String()},
+						Dst:  SetDateRetry.String(),
+					},
+				},
+				fsm.Callbacks{
+					SetDate.String(): s.OnState,
+				},
+			)
+			s.inputHandler.handlerFunc = func(session *discordgo.Session, create *discordgo.MessageCreate) {
+				s.inputHandler.inputChan <- tc.input
+			}
+			var wg sync.WaitGroup
+			wg.Add(2)
+			go func() {
+				s.inputHandler.handlerFunc(opts.Session, &discordgo.MessageCreate{})
+				wg.Done()
+			}()
+			go func() {
+				err := f.Event(context.TODO(), SetDate.String())
+				assert.NoError(t, err)
+				wg.Done()
+			}()
+			wg.Wait()
+			assert.Equal(t, tc.expectedState, f.Current())
+
+			// The raw input is left in place of a start time when it is rejected
+			actual, _ := f.Metadata(discord.StartTime.String())
+			if tc.expectedStart.IsZero() {
+				assert.Equal(t, tc.input, actual)
+				return
+			}
+			assert.Equal(t, tc.expectedStart, actual)
+		})
+	}
 }
 
 func TestSetDateRetryState_OnState(t *testing.T) {
@@ -94,7 +130,7 @@ func TestSetDateRetryState_OnState(t *testing.T) {
 		},
 	)
 	s.inputHandler.handlerFunc = func(session *discordgo.Session, create *discordgo.MessageCreate) {
-		s.inputHandler.inputChan <- "tomorrow"
+		s.inputHandler.inputChan <- "tomorrow 7pm"
 	}
 	var wg sync.WaitGroup
 	wg.Add(2)
@@ -112,6 +148,89 @@ func TestSetDateRetryState_OnState(t *testing.T) {
 	assert.NoError(t, err)
 
 	cur := time.Now()
-	expected := time.Date(cur.Year(), cur.Month(), cur.Day()+1, 0, 0, 0, 0, cur.Location())
+	expected := time.Date(cur.Year(), cur.Month(), cur.Day()+1, 19, 0, 0, 0, cur.Location())
 	assert.Equal(t, expected, actual)
 }
+
+func TestConfirmDateState_OnState(t *testing.T) {
+	opts, err := discord.NewMockOptions()
+	assert.NoError(t, err)
+	start := time.Now().Add(time.Hour)
+
+	cases := []struct {
+		name          string
+		input         string
+		expectedState string
+		expectedEnd   time.Time
+	}{
+		{
+			name:          "yes",
+			input:         "1",
+			expectedState: ConfirmDate.String(),
+			expectedEnd:   start.Add(time.Hour),
+		},
+		{
+			name:          "no",
+			input:         "2",
+			expectedState: SetDate.String(),
+			expectedEnd:   time.Time{},
+		},
+		{
+			name:          "invalid",
+			input:         "invalid",
+			expectedState: ConfirmDateRetry.String(),
+			expectedEnd:   start.Add(time.Hour),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := NewConfirmDateState(*opts)
+			f := fsm.NewFSM(
+				"idle",
+				fsm.Events{
+					{
+						Name: ConfirmDate.String(),
+						Src:  []string{"idle"},
+						Dst:  ConfirmDate.String(),
+					},
+					{
+						Name: ConfirmDateRetry.String(),
+						Src:  []string{ConfirmDate.String()},
+						Dst:  ConfirmDateRetry.String(),
+					},
+					{
+						Name: SetDate.String(),
+						Src:  []string{ConfirmDate.String()},
+						Dst:  SetDate.String(),
+					},
+				},
+				fsm.Callbacks{
+					ConfirmDate.String(): c.OnState,
+				},
+			)
+			f.SetMetadata(discord.StartTime.String(), start)
+			f.SetMetadata(discord.Duration.String(), start.Add(time.Hour))
+			c.inputHandler.handlerFunc = func(session *discordgo.Session, create *discordgo.MessageCreate) {
+				c.inputHandler.inputChan <- tc.input
+			}
+			var wg sync.WaitGroup
+			wg.Add(2)
+			go func() {
+				c.inputHandler.handlerFunc(opts.Session, &discordgo.MessageCreate{})
+				wg.Done()
+			}()
+			go func() {
+				err = f.Event(context.TODO(), ConfirmDate.String())
+				assert.NoError(t, err)
+				wg.Done()
+			}()
+			wg.Wait()
+			assert.Equal(t, tc.expectedState, f.Current())
+
+			end, err := Get(f, discord.Duration)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedEnd, end)
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package states
+
+import (
+	"context"
+	"fmt"
+	"github.com/GuessWhoSamFoo/fsm"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/discord"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/pkg/util"
+	"github.com/bwmarrin/discordgo"
+	"github.com/tj/go-naturaldate"
+	"strings"
+	"time"
+)
+
+type SetDeadlineState struct {
+	session           *discordgo.Session
+	interactionCreate *discordgo.InteractionCreate
+	channel           *discordgo.Channel
+	printer           *discord.Printer
+
+	inputHandler *InputHandler
+}
+
+func NewDeadlineState(o discord.Options) *SetDeadlineState {
+	return &SetDeadlineState{
+		session:           o.Session,
+		interactionCreate: o.InteractionCreate,
+		channel:           o.Channel,
+		printer:           o.Printer,
+		inputHandler:      NewInputHandler(&o),
+	}
+}
+
+func (d *SetDeadlineState) OnState(ctx context.Context, e *fsm.Event) {
+	_, err := d.session.ChannelMessageSendEmbed(d.channel.ID, d.printer.Embed(&discord.EnterDeadlineMessage))
+	if err != nil {
+		e.Err = err
+		return
+	}
+
+	if err = d.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.Deadline, promptTimeout); err != nil {
+		e.Err = err
+		return
+	}
+	if err = validateDeadline(e, discord.Deadline, time.Now()); err != nil {
+		eventErr := e.FSM.Event(ctx, SetDeadlineRetry.String())
+		if eventErr != nil {
+			e.Err = fmt.Errorf("%v: %v", err, eventErr)
+			return
+		}
+	}
+}
+
+type SetDeadlineRetryState struct {
+	session           *discordgo.Session
+	interactionCreate *discordgo.InteractionCreate
+	channel           *discordgo.Channel
+	printer           *discord.Printer
+
+	inputHandler *InputHandler
+}
+
+func NewDeadlineRetryState(o discord.Options) *SetDeadlineRetryState {
+	return &SetDeadlineRetryState{
+		session:           o.Session,
+		interactionCreate: o.InteractionCreate,
+		channel:           o.Channel,
+		printer:           o.Printer,
+		inputHandler:      NewInputHandler(&o),
+	}
+}
+
+func (d *SetDeadlineRetryState) OnState(ctx context.Context, e *fsm.Event) {
+	_, err := d.session.ChannelMessageSend(d.channel.ID, d.printer.T(discord.InvalidDeadlineText))
+	if err != nil {
+		e.Err = err
+		return
+	}
+
+	if err = d.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.Deadline, promptTimeout); err != nil {
+		e.Err = err
+		return
+	}
+	if err = validateDeadline(e, discord.Deadline, time.Now()); err != nil {
+		eventErr := e.FSM.Event(ctx, SelfTransition.String())
+		if eventErr != nil {
+			e.Err = fmt.Errorf("%v: %v", err, eventErr)
+			return
+		}
+	}
+}
+
+// validateDeadline parses a signup deadline which must fall between now and the start of the event. Deadlines can be
+// relative to the start such as "2 hours before" or a time such as "friday at 5pm".
+func validateDeadline(e *fsm.Event, key discord.MetadataKey, now time.Time) error {
+	val, err := Get(e.FSM, key)
+	if err != nil {
+		return err
+	}
+	input := strings.TrimSpace(fmt.Sprintf("%s", val))
+	if strings.EqualFold(input, "none") {
+		e.FSM.SetMetadata(key.String(), time.Time{})
+		return nil
+	}
+
+	startTime, err := eventStartTime(e.FSM)
+	if err != nil {
+		return err
+	}
+
+	deadline, ok := util.ParseOffsetBefore(input, startTime)
+	if !ok {
+		deadline, err = naturaldate.Parse(input, now, naturaldate.WithDirection(naturaldate.Future))
+		if err != nil {
+			return err
+		}
+	}
+
+	// Unrecognized input is parsed as the reference time
+	if input == "" || !deadline.After(now) || !deadline.Before(startTime) {
+		e.FSM.SetMetadata(key.String(), time.Time{})
+		return fmt.Errorf("invalid deadline")
+	}
+	e.FSM.SetMetadata(key.String(), deadline)
+	return nil
+}
+
+// eventStartTime gets the start time entered during the sequence, or the start of the event being edited
+func eventStartTime(f *fsm.FSM) (time.Time, error) {
+	if start, found := f.Metadata(discord.StartTime.String()); found {
+		if val, ok := start.(time.Time); ok {
+			return val, nil
+		}
+	}
+	obj, err := Get(f, discord.EventObject)
+	if err != nil {
+		return time.Time{}, err
+	}
+	event, ok := obj.(discord.Event)
+	if !ok {
+		return time.Time{}, fmt.Errorf("cannot get event")
+	}
+	return event.Start, nil
+}
@@ -0,0 +1,279 @@
+package states
+
+import (
+	"context"
+	"github.com/GuessWhoSamFoo/fsm"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/discord"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewDeadlineState(t *testing.T) {
+	opts, err := discord.NewMockOptions()
+	assert.NoError(t, err)
+
+	s := NewDeadlineState(*opts)
+	assert.NotNil(t, s)
+}
+
+func TestSetDeadlineState_OnState(t *testing.T) {
+	opts, err := discord.NewMockOptions()
+	assert.NoError(t, err)
+	start := time.Now().Add(24 * time.Hour)
+
+	cases := []struct {
+		name          string
+		input         string
+		expectedState string
+		expectedTime  time.Time
+		isErr         bool
+	}{
+		{
+			name:          "valid",
+			input:         "1 hour before",
+			expectedState: SetDeadline.String(),
+			expectedTime:  start.Add(-time.Hour),
+		},
+		{
+			name:          "after start",
+			input:         "2 days",
+			expectedState: SetDeadlineRetry.String(),
+			expectedTime:  time.Time{},
+		},
+		{
+			name:          "none",
+			input:         "none",
+			expectedState: SetDeadline.String(),
+			expectedTime:  time.Time{},
+		},
+		{
+			name:          "cancel",
+			input:         "cancel",
+			expectedState: Cancel.String(),
+			isErr:         true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := NewDeadlineState(*opts)
+			f := fsm.NewFSM(
+				"idle",
+				fsm.Events{
+					{
+						Name: SetDeadline.String(),
+						Src:  []string{"idle"},
+						Dst:  SetDeadline.String(),
+					},
+					{
+						Name: SetDeadlineRetry.String(),
+						Src:  []string{SetDeadline.String()},
+						Dst:  SetDeadlineRetry.String(),
+					},
+					{
+						Name: Cancel.String(),
+						Src:  []string{SetDeadline.String()},
+						Dst:  Cancel.String(),
+					},
+				},
+				fsm.Callbacks{
+					SetDeadline.String(): d.OnState,
+				},
+			)
+			f.SetMetadata(discord.StartTime.String(), start)
+			d.inputHandler.handlerFunc = func(session *discordgo.Session, create *discordgo.MessageCreate) {
+				d.inputHandler.inputChan <- tc.input
+			}
+			var wg sync.WaitGroup
+			wg.Add(2)
+			go func() {
+				d.inputHandler.handlerFunc(opts.Session, &discordgo.MessageCreate{})
+				wg.Done()
+			}()
+
+			go func() {
+				err = f.Event(context.TODO(), SetDeadline.String())
+				if tc.isErr {
+					assert.Error(t, err)
+				} else {
+					assert.NoError(t, err)
+					got, err := Get(f, discord.Deadline)
+					assert.NoError(t, err)
+					assert.Equal(t, tc.expectedTime, got)
+				}
+				wg.Done()
+			}()
+			wg.Wait()
+			assert.Equal(t, tc.expectedState, f.Current())
+		})
+	}
+}
+
+func TestSetDeadlineRetryState_OnState(t *testing.T) {
+	opts, err := discord.NewMockOptions()
+	assert.NoError(t, err)
+	start := time.Now().Add(24 * time.Hour)
+
+	cases := []struct {
+		name          string
+		input         string
+		expectedState string
+		expectedTime  time.Time
+		isErr         bool
+	}{
+		{
+			name:          "valid",
+			input:         "1 hour before",
+			expectedState: SetDeadlineRetry.String(),
+			expectedTime:  start.Add(-time.Hour),
+		},
+		{
+			name:          "invalid",
+			input:         "invalid",
+			expectedState: SelfTransition.String(),
+			expectedTime:  time.Time{},
+		},
+		{
+			name:          "cancel",
+			input:         "cancel",
+			expectedState: Cancel.String(),
+			isErr:         true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := NewDeadlineRetryState(*opts)
+			f := fsm.NewFSM(
+				"idle",
+				fsm.Events{
+					{
+						Name: SetDeadlineRetry.String(),
+						Src:  []string{"idle"},
+						Dst:  SetDeadlineRetry.String(),
+					},
+					{
+						Name: SelfTransition.String(),
+						Src:  []string{SetDeadlineRetry.String()},
+						Dst:  SelfTransition.String(),
+					},
+					{
+						Name: Cancel.String(),
+						Src:  []string{SetDeadlineRetry.String()},
+						Dst:  Cancel.String(),
+					},
+				},
+				fsm.Callbacks{
+					SetDeadlineRetry.String(): d.OnState,
+				},
+			)
+			f.SetMetadata(discord.StartTime.String(), start)
+			d.inputHandler.handlerFunc = func(session *discordgo.Session, create *discordgo.MessageCreate) {
+				d.inputHandler.inputChan <- tc.input
+			}
+			var wg sync.WaitGroup
+			wg.Add(2)
+			go func() {
+				d.inputHandler.handlerFunc(opts.Session, &discordgo.MessageCreate{})
+				wg.Done()
+			}()
+			go func() {
+				err = f.Event(context.TODO(), SetDeadlineRetry.String())
+				if tc.isErr {
+					assert.Error(t, err)
+				} else {
+					assert.NoError(t, err)
+
+					got, err := Get(f, discord.Deadline)
+					assert.NoError(t, err)
+					assert.Equal(t, tc.expectedTime, got)
+				}
+				wg.Done()
+			}()
+			wg.Wait()
+			assert.Equal(t, tc.expectedState, f.Current())
+		})
+	}
+}
+
+func Test_validateDeadline(t *testing.T) {
+	now := time.Date(2023, 6, 7, 12, 0, 0, 0, time.Local)
+	start := now.Add(72 * time.Hour)
+
+	cases := []struct {
+		name     string
+		input    string
+		metadata map[discord.MetadataKey]interface{}
+		expected time.Time
+		isErr    bool
+	}{
+		{
+			name:     "before start",
+			input:    "2 hours before",
+			metadata: map[discord.MetadataKey]interface{}{discord.StartTime: start},
+			expected: start.Add(-2 * time.Hour),
+		},
+		{
+			name:     "absolute",
+			input:    "in 1 day",
+			metadata: map[discord.MetadataKey]interface{}{discord.StartTime: start},
+			expected: now.Add(24 * time.Hour),
+		},
+		{
+			name:     "from edited event",
+			input:    "1 day before",
+			metadata: map[discord.MetadataKey]interface{}{discord.EventObject: discord.Event{Start: start}},
+			expected: start.Add(-24 * time.Hour),
+		},
+		{
+			name:     "none",
+			input:    "None",
+			metadata: map[discord.MetadataKey]interface{}{discord.StartTime: start},
+			expected: time.Time{},
+		},
+		{
+			name:     "after start",
+			input:    "1 week",
+			metadata: map[discord.MetadataKey]interface{}{discord.StartTime: start},
+			expected: time.Time{},
+			isErr:    true,
+		},
+		{
+			name:     "already passed",
+			input:    "1 week before",
+			metadata: map[discord.MetadataKey]interface{}{discord.StartTime: start},
+			expected: time.Time{},
+			isErr:    true,
+		},
+		{
+			name:     "invalid",
+			input:    "invalid",
+			metadata: map[discord.MetadataKey]interface{}{discord.StartTime: start},
+			expected: time.Time{},
+			isErr:    true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := fsm.NewFSM("idle", fsm.Events{}, fsm.Callbacks{})
+			for k, v := range tc.metadata {
+				f.SetMetadata(k.String(), v)
+			}
+			f.SetMetadata(discord.Deadline.String(), tc.input)
+			err := validateDeadline(&fsm.Event{FSM: f}, discord.Deadline, now)
+			if tc.isErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			actual, exists := f.Metadata(discord.Deadline.String())
+			assert.True(t, exists)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
@@ -5,13 +5,13 @@ import (
 	"github.com/GuessWhoSamFoo/fsm"
 	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/discord"
 	"github.com/bwmarrin/discordgo"
-	"time"
 )
 
 type AddDescriptionState struct {
 	session           *discordgo.Session
 	interactionCreate *discordgo.InteractionCreate
 	channel           *discordgo.Channel
+	printer           *discord.Printer
 
 	inputHandler *InputHandler
 }
@@ -21,18 +21,19 @@ func NewAddDescriptionState(o discord.Options) *AddDescriptionState {
 		session:           o.Session,
 		interactionCreate: o.InteractionCreate,
 		channel:           o.Channel,
+		printer:           o.Printer,
 		inputHandler:      NewInputHandler(&o),
 	}
 }
 
 func (a *AddDescriptionState) OnState(ctx context.Context, e *fsm.Event) {
-	_, err := a.session.ChannelMessageSendEmbed(a.channel.ID, &discord.EnterDescriptionMessage)
+	_, err := a.session.ChannelMessageSendEmbed(a.channel.ID, a.printer.Embed(&discord.EnterDescriptionMessage))
 	if err != nil {
 		e.Err = err
 		return
 	}
 
-	err = a.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.Description, 60*time.Second)
+	err = a.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.Description, longPromptTimeout)
 	if err != nil {
 		e.Err = err
 	}
@@ -0,0 +1,114 @@
+package discord
+
+import (
+	"fmt"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/role"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/store"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/pkg/util"
+	"github.com/bwmarrin/discordgo"
+	"golang.org/x/exp/slices"
+	"strings"
+	"time"
+)
+
+// MuteAlertsPrefix routes the button which turns off late cancellation alerts for an event
+const MuteAlertsPrefix = "mute_alerts"
+
+// LateCancellation is one or more members dropping out of an event shortly before it starts
+type LateCancellation struct {
+	Event     *Event
+	ChannelID string
+	MessageID string
+	Dropped   []string
+	Promoted  []string // users moved off the waitlist into the open spots
+}
+
+// StartsWithin reports whether an event has not started yet but will within a window
+func (e *Event) StartsWithin(now time.Time, window time.Duration) bool {
+	return now.Before(e.Start) && e.Start.Sub(now) <= window
+}
+
+// Headcount prints how many spots in the accepted role are taken
+func (e *Event) Headcount() string {
+	for _, r := range e.RoleGroup.Roles {
+		if r.FieldName != role.AcceptedField {
+			continue
+		}
+		if r.Limit > 0 {
+			return fmt.Sprintf("%d/%d", r.Count, r.Limit)
+		}
+		return fmt.Sprintf("%d", r.Count)
+	}
+	return "0"
+}
+
+// NotifyLateCancellation sends the organizer of an event a DM about members who dropped out, unless they muted alerts
+// for the event. Organizers dropping out of their own event are not reported.
+func NotifyLateCancellation(s *discordgo.Session, st *store.Store, printers PrinterFunc, guildID string, c LateCancellation) error {
+	c.Dropped = util.RemoveUser(slices.Clone(c.Dropped), c.Event.Owner)
+	if len(c.Dropped) == 0 || c.Event.Owner == "" {
+		return nil
+	}
+	if st != nil && st.AlertsMuted(c.MessageID) {
+		return nil
+	}
+	m, err := FindMember(s, guildID, c.Event.Owner)
+	if err != nil || m == nil {
+		return err
+	}
+	return sendDirectMessage(s, m.User.ID, LateCancellationMessage(printers.For(m.User.ID), c))
+}
+
+// LateCancellationMessage tells an organizer who dropped out of their event with a button to mute further alerts
+func LateCancellationMessage(p *Printer, c LateCancellation) *discordgo.MessageSend {
+	promoted := "-"
+	if len(c.Promoted) > 0 {
+		promoted = strings.Join(c.Promoted, "\n")
+	}
+	return &discordgo.MessageSend{
+		Embeds: []*discordgo.MessageEmbed{
+			p.Embed(&discordgo.MessageEmbed{
+				Title:       p.Tf("%s dropped out of %s", strings.Join(c.Dropped, ", "), c.Event.Title),
+				Color:       Purple,
+				Description: p.Tf("The event starts <t:%d:R>. [Click here to view the event](%s)", c.Event.Start.Unix(), c.Event.DiscordLink),
+				Fields: []*discordgo.MessageEmbedField{
+					{
+						Name:   p.T("Moved off the waitlist"),
+						Value:  promoted,
+						Inline: true,
+					},
+					{
+						Name:   string(role.AcceptedField),
+						Value:  c.Event.Headcount(),
+						Inline: true,
+					},
+				},
+			}),
+		},
+		Components: []discordgo.MessageComponent{
+			discordgo.ActionsRow{
+				Components: []discordgo.MessageComponent{
+					discordgo.Button{
+						Label:    p.T("Mute alerts for this event"),
+						Style:    discordgo.SecondaryButton,
+						CustomID: MuteAlertsID(c.ChannelID, c.MessageID),
+					},
+				},
+			},
+		},
+	}
+}
+
+// MuteAlertsID is the custom ID of a button muting alerts for an event message
+func MuteAlertsID(channelID, messageID string) string {
+	return strings.Join([]string{MuteAlertsPrefix, channelID, messageID}, idSeparator)
+}
+
+// ParseMuteAlertsID gets the event message from a mute alerts button
+func ParseMuteAlertsID(customID string) (channelID, messageID string, err error) {
+	parts := strings.Split(customID, idSeparator)
+	if len(parts) != 3 || parts[0] != MuteAlertsPrefix || parts[1] == "" || parts[2] == "" {
+		return "", "", fmt.Errorf("invalid mute alerts ID: %s", customID)
+	}
+	return parts[1], parts[2], nil
+}
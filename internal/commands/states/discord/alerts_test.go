@@ -0,0 +1,67 @@
+package discord
+
+import (
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/role"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestEvent_StartsWithin(t *testing.T) {
+	now := time.Date(2023, 6, 10, 18, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name     string
+		start    time.Time
+		expected bool
+	}{
+		{
+			name:     "inside window",
+			start:    now.Add(3 * time.Hour),
+			expected: true,
+		},
+		{
+			name:  "outside window",
+			start: now.Add(48 * time.Hour),
+		},
+		{
+			name:  "started",
+			start: now.Add(-time.Hour),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := &Event{Start: tc.start}
+			assert.Equal(t, tc.expected, e.StartsWithin(now, 24*time.Hour))
+		})
+	}
+}
+
+func TestLateCancellationMessage(t *testing.T) {
+	rg := role.NewDefaultRoleGroup()
+	rg.SetLimit(role.AcceptedField, 5)
+	assert.NoError(t, rg.ToggleRole(role.AcceptedField, "bar"))
+	e := &Event{Title: "Social", Owner: "foo", RoleGroup: rg}
+
+	msg := LateCancellationMessage(nil, LateCancellation{
+		Event:     e,
+		ChannelID: "1",
+		MessageID: "2",
+		Dropped:   []string{"baz"},
+		Promoted:  []string{"bar"},
+	})
+	assert.Equal(t, "baz dropped out of Social", msg.Embeds[0].Title)
+	assert.Equal(t, "bar", msg.Embeds[0].Fields[0].Value)
+	assert.Equal(t, "1/5", msg.Embeds[0].Fields[1].Value)
+	assert.Equal(t, MuteAlertsPrefix, ComponentPrefix(MuteAlertsID("1", "2")))
+}
+
+func TestParseMuteAlertsID(t *testing.T) {
+	channelID, messageID, err := ParseMuteAlertsID(MuteAlertsID("123", "456"))
+	assert.NoError(t, err)
+	assert.Equal(t, "123", channelID)
+	assert.Equal(t, "456", messageID)
+
+	_, _, err = ParseMuteAlertsID("mute_alerts:123")
+	assert.Error(t, err)
+}
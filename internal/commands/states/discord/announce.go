@@ -0,0 +1,222 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/role"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/store"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/pkg/util"
+	"github.com/bwmarrin/discordgo"
+	"strings"
+	"time"
+)
+
+// MaxAnnouncementLength leaves room in a message for mentions of members with DMs closed
+const MaxAnnouncementLength = 1500
+
+// maxMentionsLength is how much of a message's 2000 characters is used for mentions
+const maxMentionsLength = 1900
+
+// maxFieldLength is the most characters Discord allows in the value of an embed field
+const maxFieldLength = 1024
+
+// AnnounceInterval spaces out DMs so messaging a large event does not trip Discord's spam protection
+var AnnounceInterval = 500 * time.Millisecond
+
+// Announcement is a message from an organizer to the members who responded to an event
+type Announcement struct {
+	Event     *Event
+	ChannelID string
+	MessageID string
+	Author    string
+	Text      string
+	Fields    []role.FieldType // WaitlistField selects the waitlist for accepted
+	// Except is a member who is not messaged, such as the one who made a change
+	Except string
+	// Embed renders the message for a recipient. It defaults to the text from the organizer.
+	Embed func(p *Printer) *discordgo.MessageEmbed
+}
+
+// embed renders the announcement for a recipient
+func (a Announcement) embed(p *Printer) *discordgo.MessageEmbed {
+	if a.Embed != nil {
+		return a.Embed(p)
+	}
+	return AnnouncementEmbed(p, a)
+}
+
+// DeliveryReport is who an announcement reached and how
+type DeliveryReport struct {
+	Delivered []string
+	Mentioned []string // members with DMs closed who were mentioned in the event channel
+	Failed    []string
+}
+
+// Recipients returns the users who responded to any of the fields without duplicates
+func (e *Event) Recipients(fields []role.FieldType) []string {
+	var result []string
+	seen := map[string]bool{}
+	add := func(users []string) {
+		for _, u := range users {
+			if !seen[u] {
+				seen[u] = true
+				result = append(result, u)
+			}
+		}
+	}
+	for _, f := range fields {
+		if f == role.WaitlistField {
+			add(e.RoleGroup.WaitlistUsers(role.AcceptedField))
+			continue
+		}
+		add(e.RoleGroup.GetUsers(f))
+	}
+	return result
+}
+
+// Announce sends an announcement to each recipient by DM in their language. Members with DMs closed are mentioned in a
+// reply to the event post instead. The result is recorded in the store for auditing.
+func Announce(s *discordgo.Session, st *store.Store, printers PrinterFunc, guildID string, a Announcement) (DeliveryReport, error) {
+	var report DeliveryReport
+	var closed []*discordgo.Member
+	ticker := time.NewTicker(AnnounceInterval)
+	defer ticker.Stop()
+	recipients := a.Event.Recipients(a.Fields)
+	if a.Except != "" {
+		recipients = util.RemoveUser(recipients, a.Except)
+	}
+	for n, name := range recipients {
+		if n > 0 {
+			<-ticker.C
+		}
+		m, err := FindMember(s, guildID, name)
+		if err != nil || m == nil {
+			report.Failed = append(report.Failed, name)
+			continue
+		}
+		err = sendDirectMessage(s, m.User.ID, &discordgo.MessageSend{
+			Embeds: []*discordgo.MessageEmbed{a.embed(printers.For(m.User.ID))},
+		})
+		switch {
+		case err == nil:
+			report.Delivered = append(report.Delivered, name)
+		case isDMClosed(err):
+			closed = append(closed, m)
+		default:
+			report.Failed = append(report.Failed, name)
+		}
+	}
+
+	if len(closed) > 0 {
+		for _, m := range closed {
+			report.Mentioned = append(report.Mentioned, m.User.Username)
+		}
+		if err := mentionMembers(s, printers.For(""), a, closed); err != nil {
+			report.Failed = append(report.Failed, report.Mentioned...)
+			report.Mentioned = nil
+		}
+	}
+
+	if st != nil {
+		roles := make([]string, 0, len(a.Fields))
+		for _, f := range a.Fields {
+			roles = append(roles, string(f))
+		}
+		if err := st.RecordAnnouncement(store.Announcement{
+			ChannelID: a.ChannelID,
+			MessageID: a.MessageID,
+			Author:    a.Author,
+			Text:      a.Text,
+			Roles:     roles,
+			Delivered: report.Delivered,
+			Mentioned: report.Mentioned,
+			Failed:    report.Failed,
+			At:        time.Now(),
+		}); err != nil {
+			return report, fmt.Errorf("cannot record announcement: %w", err)
+		}
+	}
+	return report, nil
+}
+
+// isDMClosed reports whether a DM failed because the member does not accept messages from the bot
+func isDMClosed(err error) bool {
+	var restErr *discordgo.RESTError
+	return errors.As(err, &restErr) && restErr.Message != nil && restErr.Message.Code == discordgo.ErrCodeCannotSendMessagesToThisUser
+}
+
+// mentionMembers replies to the event post with the announcement, mentioning members in as many messages as needed
+func mentionMembers(s *discordgo.Session, p *Printer, a Announcement, members []*discordgo.Member) error {
+	for len(members) > 0 {
+		var mentions, ids []string
+		var length int
+		for len(members) > 0 && (len(ids) == 0 || length+len(members[0].User.Mention())+1 <= maxMentionsLength) {
+			mentions = append(mentions, members[0].User.Mention())
+			ids = append(ids, members[0].User.ID)
+			length += len(members[0].User.Mention()) + 1
+			members = members[1:]
+		}
+		if _, err := s.ChannelMessageSendComplex(a.ChannelID, &discordgo.MessageSend{
+			Content: strings.Join(mentions, " "),
+			Embeds:  []*discordgo.MessageEmbed{a.embed(p)},
+			AllowedMentions: &discordgo.MessageAllowedMentions{
+				Users: ids,
+			},
+			Reference: &discordgo.MessageReference{
+				ChannelID: a.ChannelID,
+				MessageID: a.MessageID,
+			},
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AnnouncementEmbed shows an announcement along with the event it is about
+func AnnouncementEmbed(p *Printer, a Announcement) *discordgo.MessageEmbed {
+	return &discordgo.MessageEmbed{
+		Title:       p.Tf("Message from %s about %s", a.Author, a.Event.Title),
+		Color:       Purple,
+		Description: a.Text + "\n\n" + p.Tf("[Click here to view the event](%s)", a.Event.DiscordLink),
+	}
+}
+
+// DeliveryReportEmbed tells an organizer who their announcement reached
+func DeliveryReportEmbed(p *Printer, r DeliveryReport) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title: p.Tf("Message sent to %d of %d members", len(r.Delivered)+len(r.Mentioned), len(r.Delivered)+len(r.Mentioned)+len(r.Failed)),
+		Color: Purple,
+	}
+	if len(r.Mentioned) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  p.T("DMs closed, mentioned in the event channel"),
+			Value: joinNames(r.Mentioned),
+		})
+	}
+	if len(r.Failed) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  p.T("Could not be reached"),
+			Value: joinNames(r.Failed),
+		})
+	}
+	return embed
+}
+
+// joinNames lists names one per line, cutting the list short with a count of the rest to fit in an embed field
+func joinNames(names []string) string {
+	if all := strings.Join(names, "\n"); len(all) <= maxFieldLength {
+		return all
+	}
+	var lines []string
+	length := 0
+	for n, name := range names {
+		more := fmt.Sprintf("...and %d more", len(names)-n-1)
+		if length+len(name)+len(more)+2 > maxFieldLength {
+			return strings.Join(append(lines, fmt.Sprintf("...and %d more", len(names)-n)), "\n")
+		}
+		lines = append(lines, name)
+		length += len(name) + 1
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,71 @@
+package discord
+
+import (
+	"fmt"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/role"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestEvent_Recipients(t *testing.T) {
+	rg := role.NewDefaultRoleGroup()
+	rg.SetLimit(role.AcceptedField, 1)
+	assert.NoError(t, rg.ToggleRole(role.AcceptedField, "foo"))
+	assert.NoError(t, rg.ToggleRole(role.AcceptedField, "bar"))
+	assert.NoError(t, rg.ToggleRole(role.TentativeField, "baz"))
+	e := &Event{RoleGroup: rg}
+
+	cases := []struct {
+		name     string
+		fields   []role.FieldType
+		expected []string
+	}{
+		{
+			name:     "accepted",
+			fields:   []role.FieldType{role.AcceptedField},
+			expected: []string{"foo"},
+		},
+		{
+			name:     "waitlist",
+			fields:   []role.FieldType{role.WaitlistField},
+			expected: []string{"bar"},
+		},
+		{
+			name:     "everyone",
+			fields:   []role.FieldType{role.AcceptedField, role.TentativeField, role.WaitlistField},
+			expected: []string{"foo", "baz", "bar"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, e.Recipients(tc.fields))
+		})
+	}
+}
+
+func TestDeliveryReportEmbed(t *testing.T) {
+	embed := DeliveryReportEmbed(nil, DeliveryReport{
+		Delivered: []string{"foo", "bar"},
+		Mentioned: []string{"baz"},
+		Failed:    []string{"qux"},
+	})
+	assert.Equal(t, "Message sent to 3 of 4 members", embed.Title)
+	assert.Len(t, embed.Fields, 2)
+	assert.Equal(t, "baz", embed.Fields[0].Value)
+	assert.Equal(t, "qux", embed.Fields[1].Value)
+
+	var failed []string
+	for n := 0; n < 200; n++ {
+		failed = append(failed, fmt.Sprintf("member%03d", n))
+	}
+	embed = DeliveryReportEmbed(nil, DeliveryReport{Failed: failed})
+	assert.LessOrEqual(t, len(embed.Fields[0].Value), maxFieldLength)
+	assert.True(t, strings.HasPrefix(embed.Fields[0].Value, "member000\nmember001\n"))
+	assert.True(t, strings.HasSuffix(embed.Fields[0].Value, "more"))
+
+	embed = DeliveryReportEmbed(nil, DeliveryReport{Delivered: []string{"foo"}})
+	assert.Equal(t, "Message sent to 1 of 1 members", embed.Title)
+	assert.Empty(t, embed.Fields)
+}
@@ -0,0 +1,66 @@
+package discord
+
+import (
+	"fmt"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/store"
+	"github.com/bwmarrin/discordgo"
+	"time"
+)
+
+// ToAttendance copies the roster of an event for the store
+func ToAttendance(e *Event, channelID, messageID string, now time.Time) store.Attendance {
+	a := store.Attendance{
+		ChannelID: channelID,
+		MessageID: messageID,
+		Title:     e.Title,
+		Owner:     e.Owner,
+		Start:     e.Start,
+		End:       e.End,
+		Responses: map[string][]string{},
+		Recorded:  now,
+	}
+	for _, r := range e.RoleGroup.Roles {
+		a.Responses[string(r.FieldName)] = append([]string{}, r.Users...)
+	}
+	for _, wl := range e.RoleGroup.Waitlist {
+		a.Waitlist = append(a.Waitlist, wl.Users...)
+	}
+	return a
+}
+
+// maxStatsEvents is how many recent events are listed in attendance stats
+const maxStatsEvents = 10
+
+// PrintAttendanceSummary summarizes a member's attendance history in one line
+func PrintAttendanceSummary(stats store.MemberStats) string {
+	return fmt.Sprintf("Attended %d of %d events · %d no-shows", stats.Attended, len(stats.Events), stats.NoShows)
+}
+
+// MemberStatsEmbed shows a member's recent events and whether they attended
+func MemberStatsEmbed(guildID, name string, stats store.MemberStats) *discordgo.MessageEmbed {
+	var desc string
+	for i, e := range stats.Events {
+		if i == maxStatsEvents {
+			break
+		}
+		mark := "➖"
+		switch {
+		case e.Attended:
+			mark = "✅"
+		case e.TookAttendance:
+			mark = "❌"
+		}
+		desc += fmt.Sprintf("%s `%s` [%s](https://discord.com/channels/%s/%s/%s)\n", mark, e.Start.Local().Format("Jan 2"), e.Title, guildID, e.ChannelID, e.MessageID)
+	}
+	if desc == "" {
+		desc = "No past events found!"
+	}
+	return &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("Attendance for %s", name),
+		Color:       Purple,
+		Description: desc,
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: PrintAttendanceSummary(stats) + "\n✅ attended ⋅ ❌ no-show ⋅ ➖ attendance not taken",
+		},
+	}
+}
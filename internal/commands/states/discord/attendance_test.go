@@ -0,0 +1,67 @@
+package discord
+
+import (
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/role"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/store"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestToAttendance(t *testing.T) {
+	rg := role.NewDefaultRoleGroup()
+	rg.SetLimit(role.AcceptedField, 1)
+	assert.NoError(t, rg.ToggleRole(role.AcceptedField, "foo"))
+	assert.NoError(t, rg.ToggleRole(role.AcceptedField, "bar"))
+	assert.NoError(t, rg.ToggleRole(role.DeclinedField, "baz"))
+	now := time.Now()
+
+	a := ToAttendance(&Event{Title: "test", RoleGroup: rg}, "channel", "message", now)
+	assert.Equal(t, "message", a.MessageID)
+	assert.Equal(t, []string{"foo"}, a.Responses[string(role.AcceptedField)])
+	assert.Equal(t, []string{"baz"}, a.Responses[string(role.DeclinedField)])
+	assert.Equal(t, []string{"bar"}, a.Waitlist)
+	assert.Equal(t, now, a.Recorded)
+}
+
+func TestMemberStatsEmbed(t *testing.T) {
+	// Noon keeps the printed date the same in most time zones
+	start := time.Date(2023, 6, 10, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name     string
+		stats    store.MemberStats
+		expected []string
+	}{
+		{
+			name:     "no events",
+			expected: []string{"No past events found!"},
+		},
+		{
+			name: "history",
+			stats: store.MemberStats{
+				Events: []store.MemberEvent{
+					{Title: "attended", Start: start, ChannelID: "c", MessageID: "1", TookAttendance: true, Attended: true},
+					{Title: "missed", Start: start, ChannelID: "c", MessageID: "2", TookAttendance: true},
+					{Title: "unknown", Start: start, ChannelID: "c", MessageID: "3"},
+				},
+				Attended: 1,
+				NoShows:  1,
+			},
+			expected: []string{
+				"✅ `Jun 10` [attended](https://discord.com/channels/g/c/1)",
+				"❌ `Jun 10` [missed](https://discord.com/channels/g/c/2)",
+				"➖ `Jun 10` [unknown](https://discord.com/channels/g/c/3)",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			embed := MemberStatsEmbed("g", "foo", tc.stats)
+			assert.Equal(t, "Attendance for foo", embed.Title)
+			assert.Equal(t, tc.expected, strings.Split(strings.TrimSpace(embed.Description), "\n"))
+			assert.True(t, strings.HasPrefix(embed.Footer.Text, PrintAttendanceSummary(tc.stats)))
+		})
+	}
+}
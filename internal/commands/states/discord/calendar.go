@@ -3,6 +3,7 @@ package discord
 import (
 	"context"
 	"fmt"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/metrics"
 	"github.com/GuessWhoSamFoo/gang-gang-bot/pkg/util"
 	"google.golang.org/api/calendar/v3"
 	"google.golang.org/api/option"
@@ -15,6 +16,9 @@ type CalendarClient struct {
 	ctx        context.Context
 	service    *calendar.Service
 	calendarID string
+
+	// Categories set the color of calendar entries
+	Categories []Category
 }
 
 // NewCalendarClient creates a new client to query a Google Calendar API
@@ -42,8 +46,10 @@ func (c *CalendarClient) CreateGoogleEvent(event *Event) error {
 	if event == nil {
 		return fmt.Errorf("event is nil")
 	}
-	gEvent := toGoogleEvent(event)
+	gEvent := toGoogleEvent(event, c.Categories)
+	start := time.Now()
 	cEvent, err := c.service.Events.Insert(c.calendarID, gEvent).Do()
+	metrics.ObserveCalendarRequest("insert", start, err)
 	if err != nil {
 		return err
 	}
@@ -52,7 +58,20 @@ func (c *CalendarClient) CreateGoogleEvent(event *Event) error {
 }
 
 func (c *CalendarClient) ListEvents() ([]*calendar.Event, error) {
+	start := time.Now()
 	events, err := c.service.Events.List(c.calendarID).SingleEvents(true).TimeMin(time.Now().Format(time.RFC3339)).OrderBy("startTime").Do()
+	metrics.ObserveCalendarRequest("list", start, err)
+	if err != nil {
+		return nil, err
+	}
+	return events.Items, nil
+}
+
+// ListEventsBetween lists events which end after timeMin and start before timeMax
+func (c *CalendarClient) ListEventsBetween(timeMin, timeMax time.Time) ([]*calendar.Event, error) {
+	start := time.Now()
+	events, err := c.service.Events.List(c.calendarID).SingleEvents(true).TimeMin(timeMin.Format(time.RFC3339)).TimeMax(timeMax.Format(time.RFC3339)).OrderBy("startTime").Do()
+	metrics.ObserveCalendarRequest("list", start, err)
 	if err != nil {
 		return nil, err
 	}
@@ -63,12 +82,14 @@ func (c *CalendarClient) UpdateEvent(event *Event) error {
 	if event == nil {
 		return fmt.Errorf("event is nil")
 	}
-	gEvent := toGoogleEvent(event)
+	gEvent := toGoogleEvent(event, c.Categories)
 	eventID, _, err := util.DecodeToGoogleEventID(event.ID)
 	if err != nil {
 		return err
 	}
+	start := time.Now()
 	_, err = c.service.Events.Patch(c.calendarID, eventID, gEvent).Do()
+	metrics.ObserveCalendarRequest("patch", start, err)
 	if err != nil {
 		return err
 	}
@@ -83,14 +104,17 @@ func (c *CalendarClient) DeleteEvent(event *Event) error {
 	if err != nil {
 		return fmt.Errorf("decoded %s: %v", eventID, err)
 	}
-	if err := c.service.Events.Delete(c.calendarID, eventID).Do(); err != nil {
-		return fmt.Errorf("failed to delete event: %v", err)
+	start := time.Now()
+	err = c.service.Events.Delete(c.calendarID, eventID).Do()
+	metrics.ObserveCalendarRequest("delete", start, err)
+	if err != nil {
+		return fmt.Errorf("failed to delete event: %w", err)
 	}
 	return nil
 }
 
-// toGoogleEvent converts the event type to a calendar event
-func toGoogleEvent(event *Event) *calendar.Event {
+// toGoogleEvent converts the event type to a calendar event colored by its category
+func toGoogleEvent(event *Event, categories []Category) *calendar.Event {
 	if event == nil {
 		return nil
 	}
@@ -99,6 +123,12 @@ func toGoogleEvent(event *Event) *calendar.Event {
 		Description: event.Description,
 		Location:    event.Location,
 	}
+	if category, ok := FindCategory(categories, event.Category.Name); ok && category.CalendarColorID != "" {
+		gEvent.ColorId = category.CalendarColorID
+	} else {
+		// Events are patched, so the color of a removed category has to be cleared explicitly
+		gEvent.NullFields = append(gEvent.NullFields, "ColorId")
+	}
 	if event.DiscordLink != "" && !strings.Contains(event.Description, util.LineFeed) {
 		gEvent.Description = util.PrintGoogleCalendarDescription(event.Description, event.DiscordLink)
 	}
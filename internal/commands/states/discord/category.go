@@ -0,0 +1,76 @@
+package discord
+
+import (
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+	"strings"
+	"unicode"
+)
+
+// Category groups similar events. Its color is used for the event post and the calendar entry.
+type Category struct {
+	Name  string
+	Emoji string
+	Color int
+	// CalendarColorID is a Google Calendar event color from "1" to "11"
+	CalendarColorID string
+}
+
+// DefaultCategories are used when a guild does not configure its own
+var DefaultCategories = []Category{
+	{Name: "Social", Emoji: "🎉", Color: 0xE67E22, CalendarColorID: "6"},
+	{Name: "Workshop", Emoji: "🛠️", Color: 0x3498DB, CalendarColorID: "9"},
+	{Name: "Performance", Emoji: "💃", Color: 0xE91E63, CalendarColorID: "4"},
+	{Name: "Trip", Emoji: "🚌", Color: 0x2ECC71, CalendarColorID: "10"},
+}
+
+// String prints a category as shown on an event post
+func (c Category) String() string {
+	if c.Emoji == "" {
+		return c.Name
+	}
+	return fmt.Sprintf("%s %s", c.Emoji, c.Name)
+}
+
+// ParseCategory reads a category from an event post. Only the name and emoji are kept on the post.
+func ParseCategory(value string) Category {
+	emoji, name, found := strings.Cut(value, " ")
+	// Names start with a letter or digit while unicode and custom emoji do not
+	if !found || startsWithLetterOrDigit(emoji) {
+		return Category{Name: value}
+	}
+	return Category{Name: name, Emoji: emoji}
+}
+
+func startsWithLetterOrDigit(s string) bool {
+	for _, r := range s {
+		return unicode.IsLetter(r) || unicode.IsDigit(r)
+	}
+	return false
+}
+
+// FindCategory gets a category by name, ignoring case
+func FindCategory(categories []Category, name string) (Category, bool) {
+	for _, c := range categories {
+		if strings.EqualFold(c.Name, name) {
+			return c, true
+		}
+	}
+	return Category{}, false
+}
+
+// EnterCategoryMessage asks for the category of an event from a numbered list
+func EnterCategoryMessage(categories []Category) *discordgo.MessageEmbed {
+	lines := make([]string, 0, len(categories))
+	for n, c := range categories {
+		lines = append(lines, fmt.Sprintf("**%d** %s", n+1, c))
+	}
+	return &discordgo.MessageEmbed{
+		Title:       "Which category does the event belong to?",
+		Color:       Purple,
+		Description: strings.Join(lines, "\n") + "\n\nType `None` to leave it uncategorized",
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: OptionText + "\n" + CancelText,
+		},
+	}
+}
@@ -0,0 +1,79 @@
+package discord
+
+import (
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/role"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestParseCategory(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    string
+		expected Category
+	}{
+		{
+			name:     "emoji",
+			input:    "🎉 Social",
+			expected: Category{Name: "Social", Emoji: "🎉"},
+		},
+		{
+			name:     "custom emoji",
+			input:    "<:salsa:1234> Salsa Night",
+			expected: Category{Name: "Salsa Night", Emoji: "<:salsa:1234>"},
+		},
+		{
+			name:     "no emoji",
+			input:    "Open Practice",
+			expected: Category{Name: "Open Practice"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, ParseCategory(tc.input))
+			assert.Equal(t, tc.input, tc.expected.String())
+		})
+	}
+}
+
+func TestFindCategory(t *testing.T) {
+	c, ok := FindCategory(DefaultCategories, "workshop")
+	assert.True(t, ok)
+	assert.Equal(t, "Workshop", c.Name)
+
+	_, ok = FindCategory(DefaultCategories, "concert")
+	assert.False(t, ok)
+}
+
+func TestEvent_CategoryRoundTrip(t *testing.T) {
+	e := &Event{
+		Title:     "test",
+		Start:     time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC),
+		Owner:     "foo",
+		RoleGroup: role.NewDefaultRoleGroup(),
+	}
+	e.SetCategory(DefaultCategories[1])
+	embed, err := ConvertEventToMessageEmbed(e)
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultCategories[1].Color, embed.Color)
+
+	got, err := GetEventFromMessage(&discordgo.Message{Embeds: []*discordgo.MessageEmbed{embed}})
+	assert.NoError(t, err)
+	assert.Equal(t, "Workshop", got.Category.Name)
+	assert.Equal(t, DefaultCategories[1].Color, got.Color)
+
+	got.SetCategory(Category{})
+	assert.Equal(t, Purple, got.Color)
+}
+
+func Test_toGoogleEventColor(t *testing.T) {
+	gEvent := toGoogleEvent(&Event{Category: Category{Name: "Trip"}}, DefaultCategories)
+	assert.Equal(t, "10", gEvent.ColorId)
+
+	gEvent = toGoogleEvent(&Event{}, DefaultCategories)
+	assert.Empty(t, gEvent.ColorId)
+	assert.Contains(t, gEvent.NullFields, "ColorId")
+}
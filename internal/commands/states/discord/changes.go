@@ -0,0 +1,124 @@
+package discord
+
+import (
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+	"golang.org/x/exp/slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Fields of an event an edit can change
+const (
+	TitleField       = "Title"
+	DescriptionField = "Description"
+	StartField       = "Start"
+	EndField         = "End"
+	LocationField    = "Location"
+	DeadlineField    = "Signup deadline"
+	GuestsField      = "Guests per member"
+	CategoryField    = "Category"
+	OwnerField       = "Organizer"
+)
+
+// attendeeFields are the changes members who signed up are messaged about
+var attendeeFields = []string{StartField, EndField, LocationField}
+
+// Change is a field of an event which was edited
+type Change struct {
+	Field  string
+	Before string
+	After  string
+}
+
+// AffectsAttendees reports whether members who signed up should be told about a change
+func (c Change) AffectsAttendees() bool {
+	return slices.Contains(attendeeFields, c.Field)
+}
+
+// DiffEvents lists the fields which differ between two versions of an event
+func DiffEvents(before, after *Event) []Change {
+	var changes []Change
+	add := func(field, b, a string) {
+		if b != a {
+			changes = append(changes, Change{Field: field, Before: b, After: a})
+		}
+	}
+	add(TitleField, before.Title, after.Title)
+	add(DescriptionField, before.Description, after.Description)
+	add(StartField, printChangedTime(before.Start), printChangedTime(after.Start))
+	add(EndField, printChangedTime(before.End), printChangedTime(after.End))
+	add(LocationField, before.Location, after.Location)
+	add(DeadlineField, printChangedTime(before.Deadline), printChangedTime(after.Deadline))
+	add(GuestsField, strconv.Itoa(before.MaxGuests), strconv.Itoa(after.MaxGuests))
+	add(CategoryField, before.Category.String(), after.Category.String())
+	add(OwnerField, before.Owner, after.Owner)
+	return changes
+}
+
+// AttendeeChanges are the changes members who signed up are messaged about
+func AttendeeChanges(changes []Change) []Change {
+	var result []Change
+	for _, c := range changes {
+		if c.AffectsAttendees() {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+func printChangedTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("<t:%d:F>", t.Unix())
+}
+
+func printChangedValue(p *Printer, value string) string {
+	if value == "" {
+		return p.T("None")
+	}
+	return value
+}
+
+// ContinueEditMessage asks whether to keep editing. When the time or location changed, the edit can also be finished
+// without messaging the members who signed up.
+func ContinueEditMessage(changes []Change) *discordgo.MessageEmbed {
+	lines := []string{
+		"**1** No, I'm all done",
+		"**2** Yes, keep editing",
+	}
+	var desc string
+	if len(AttendeeChanges(changes)) > 0 {
+		lines = append(lines, "**3** No, and don't message attendees")
+		desc = "Accepted and tentative members will be messaged about the new time or location.\n\n"
+	}
+	return &discordgo.MessageEmbed{
+		Title:       "Would you like to keep editing?",
+		Description: desc + strings.Join(lines, "\n"),
+		Color:       Purple,
+	}
+}
+
+// EventChangedEmbed tells a member who signed up for an event what changed about its time or location
+func EventChangedEmbed(p *Printer, e *Event, changes []Change) *discordgo.MessageEmbed {
+	fields := make([]*discordgo.MessageEmbedField, 0, len(changes))
+	for _, c := range changes {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:  p.T(c.Field),
+			Value: p.Tf("Before: %s\nAfter: %s", printChangedValue(p, c.Before), printChangedValue(p, c.After)),
+		})
+	}
+	return &discordgo.MessageEmbed{
+		Title:       p.Tf("%s has changed", e.Title),
+		Color:       e.Color,
+		Description: p.Tf("[Click here to view the event](%s)", e.DiscordLink),
+		Fields:      fields,
+	}
+}
+
+// String describes a change in English for the announcement log
+func (c Change) String() string {
+	return fmt.Sprintf("%s: %s -> %s", c.Field, printChangedValue(nil, c.Before), printChangedValue(nil, c.After))
+}
@@ -0,0 +1,105 @@
+package discord
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestDiffEvents(t *testing.T) {
+	start := time.Date(2023, 6, 9, 20, 0, 0, 0, time.UTC)
+	before := &Event{
+		Title:    "Board games",
+		Location: "Library",
+		Start:    start,
+		End:      start.Add(2 * time.Hour),
+		Owner:    "foo",
+	}
+
+	cases := []struct {
+		name     string
+		edit     func(e *Event)
+		expected []Change
+	}{
+		{
+			name:     "unchanged",
+			edit:     func(e *Event) {},
+			expected: nil,
+		},
+		{
+			name: "moved a day",
+			edit: func(e *Event) {
+				e.Start = e.Start.Add(24 * time.Hour)
+				e.End = e.End.Add(24 * time.Hour)
+			},
+			expected: []Change{
+				{Field: StartField, Before: "<t:1686340800:F>", After: "<t:1686427200:F>"},
+				{Field: EndField, Before: "<t:1686348000:F>", After: "<t:1686434400:F>"},
+			},
+		},
+		{
+			name: "title and location",
+			edit: func(e *Event) {
+				e.Title = "Chess"
+				e.Location = ""
+			},
+			expected: []Change{
+				{Field: TitleField, Before: "Board games", After: "Chess"},
+				{Field: LocationField, Before: "Library"},
+			},
+		},
+		{
+			name: "guests",
+			edit: func(e *Event) {
+				e.MaxGuests = 2
+			},
+			expected: []Change{
+				{Field: GuestsField, Before: "0", After: "2"},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			after := *before
+			tc.edit(&after)
+			assert.Equal(t, tc.expected, DiffEvents(before, &after))
+		})
+	}
+}
+
+func TestAttendeeChanges(t *testing.T) {
+	changes := []Change{
+		{Field: TitleField},
+		{Field: StartField},
+		{Field: LocationField},
+		{Field: OwnerField},
+	}
+	assert.Equal(t, []Change{{Field: StartField}, {Field: LocationField}}, AttendeeChanges(changes))
+	assert.Empty(t, AttendeeChanges(changes[:1]))
+}
+
+func TestContinueEditMessage(t *testing.T) {
+	msg := ContinueEditMessage(nil)
+	assert.Equal(t, "**1** No, I'm all done\n**2** Yes, keep editing", msg.Description)
+
+	msg = ContinueEditMessage([]Change{{Field: LocationField, Before: "Library", After: "Park"}})
+	assert.Contains(t, msg.Description, "**3** No, and don't message attendees")
+	assert.Contains(t, msg.Description, "will be messaged")
+}
+
+func TestEventChangedEmbed(t *testing.T) {
+	start := time.Date(2023, 6, 9, 20, 0, 0, 0, time.UTC)
+	e := &Event{Title: "Board games", DiscordLink: "https://discord.com/channels/1/2/3"}
+	embed := EventChangedEmbed(nil, e, []Change{
+		{Field: StartField, Before: "<t:" + strconv.FormatInt(start.Unix(), 10) + ":F>", After: "<t:1686427200:F>"},
+		{Field: LocationField, Before: "Library"},
+	})
+
+	assert.Equal(t, "Board games has changed", embed.Title)
+	assert.Contains(t, embed.Description, e.DiscordLink)
+	assert.Len(t, embed.Fields, 2)
+	assert.Equal(t, "Before: <t:1686340800:F>\nAfter: <t:1686427200:F>", embed.Fields[0].Value)
+	assert.Equal(t, "Before: Library\nAfter: None", embed.Fields[1].Value)
+}
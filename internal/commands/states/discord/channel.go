@@ -0,0 +1,22 @@
+package discord
+
+import (
+	"fmt"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/errs"
+	"github.com/bwmarrin/discordgo"
+)
+
+// postPermissions are what the bot needs to publish an event post
+const postPermissions = discordgo.PermissionViewChannel | discordgo.PermissionSendMessages | discordgo.PermissionEmbedLinks
+
+// CheckCanPost rejects channels the bot cannot publish event posts to
+func CheckCanPost(s *discordgo.Session, channelID string) error {
+	perms, err := s.UserChannelPermissions(s.State.User.ID, channelID)
+	if err != nil {
+		return errs.NewUpstream(fmt.Sprintf("cannot get permissions for channel %s", channelID), err)
+	}
+	if perms&postPermissions != postPermissions {
+		return errs.NewValidationf("I can't post events in <#%s>. Ask an admin to let me view the channel, send messages and embed links there.", channelID)
+	}
+	return nil
+}
@@ -0,0 +1,227 @@
+package discord
+
+import (
+	"fmt"
+	"github.com/GuessWhoSamFoo/fsm"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/role"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/store"
+	"github.com/bwmarrin/discordgo"
+	"strings"
+	"time"
+)
+
+// Buttons of the drafts list are formatted as prefix:draftID
+const (
+	DraftEditPrefix   = "draft_edit"
+	DraftCancelPrefix = "draft_cancel"
+)
+
+// maxDraftButtons is how many drafts get edit and cancel buttons. Discord allows 5 rows of buttons on a message.
+const maxDraftButtons = 5
+
+// ToDraft keeps the details of an event being created so it can be finished later
+func ToDraft(event *Event) store.Draft {
+	d := store.Draft{
+		GuildID:     event.GuildID,
+		Title:       event.Title,
+		Description: event.Description,
+		Location:    event.Location,
+		Start:       event.Start,
+		End:         event.End,
+		Deadline:    event.Deadline,
+		Category:    event.Category.Name,
+		Owner:       event.Owner,
+	}
+	if event.RoleGroup != nil {
+		d.Limit = event.RoleGroup.GetLimit(role.AcceptedField)
+	}
+	return d
+}
+
+// FromDraft builds the event of a scheduled post. A category which was removed since is dropped.
+func FromDraft(d store.Draft, categories []Category) *Event {
+	rg := role.NewDefaultRoleGroup()
+	rg.SetLimit(role.AcceptedField, d.Limit)
+	category, _ := FindCategory(categories, d.Category)
+
+	e := &Event{
+		Title:       d.Title,
+		Description: d.Description,
+		Location:    d.Location,
+		Start:       d.Start,
+		End:         d.End,
+		Deadline:    d.Deadline,
+		RoleGroup:   rg,
+		Owner:       d.Owner,
+		GuildID:     d.GuildID,
+	}
+	e.SetCategory(category)
+	return e
+}
+
+// LoadDraft fills in the answers of the creation DMs from a draft. A category which was removed since is dropped.
+func LoadDraft(f *fsm.FSM, d store.Draft, categories []Category) {
+	rg := role.NewDefaultRoleGroup()
+	rg.SetLimit(role.AcceptedField, d.Limit)
+	category, _ := FindCategory(categories, d.Category)
+
+	f.SetMetadata(DraftID.String(), d.ID)
+	f.SetMetadata(Title.String(), d.Title)
+	f.SetMetadata(Description.String(), d.Description)
+	f.SetMetadata(Location.String(), d.Location)
+	f.SetMetadata(StartTime.String(), d.Start)
+	f.SetMetadata(Duration.String(), d.End)
+	f.SetMetadata(Deadline.String(), d.Deadline)
+	f.SetMetadata(Attendee.String(), rg)
+	f.SetMetadata(CategoryName.String(), category)
+	if d.ChannelID != "" {
+		f.SetMetadata(ChannelID.String(), d.ChannelID)
+	}
+	if d.Scheduled() {
+		f.SetMetadata(PublishAt.String(), d.PublishAt)
+	}
+}
+
+// ResumeDraftMessage asks whether to start a new event or finish one of the drafts, which are numbered from 2
+func ResumeDraftMessage(drafts []store.Draft) *discordgo.MessageEmbed {
+	lines := []string{"**1** Start a new event"}
+	for n, d := range drafts {
+		line := fmt.Sprintf("**%d** %s", n+2, d.Title)
+		if !d.Start.IsZero() {
+			line += fmt.Sprintf(" ⋅ <t:%d:F>", d.Start.Unix())
+		}
+		lines = append(lines, line)
+	}
+	return &discordgo.MessageEmbed{
+		Title:       "Would you like to finish a draft?",
+		Color:       Purple,
+		Description: strings.Join(lines, "\n"),
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: OptionText + "\n" + CancelText,
+		},
+	}
+}
+
+// ReviewEventMessage asks what to do with a previewed event. Scheduled posts keep their schedule when saved.
+func ReviewEventMessage(publishAt time.Time) *discordgo.MessageEmbed {
+	lines := []string{
+		"**1** Publish the event now",
+		"**2** Change something",
+		"**3** Save as a draft to finish later",
+		"**4** Schedule the post for later",
+	}
+	var desc string
+	if !publishAt.IsZero() {
+		lines[2] = "**3** Save the changes and keep the schedule"
+		desc = fmt.Sprintf("Scheduled to be posted <t:%d:F>\n\n", publishAt.Unix())
+	}
+	return &discordgo.MessageEmbed{
+		Title:       "Ready to publish?",
+		Description: desc + strings.Join(lines, "\n"),
+		Color:       Purple,
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: OptionText + "\n" + CancelText,
+		},
+	}
+}
+
+// PostScheduledMessage confirms when a scheduled post goes live
+func PostScheduledMessage(publishAt time.Time) *discordgo.MessageEmbed {
+	return &discordgo.MessageEmbed{
+		Title:       "Your event has been scheduled",
+		Description: fmt.Sprintf("It will be posted <t:%d:F>. Use `/drafts` to change or cancel it.", publishAt.Unix()),
+		Color:       Purple,
+	}
+}
+
+// NotifyPostLive sends the organizer a DM once their scheduled post went live
+func NotifyPostLive(s *discordgo.Session, p *Printer, userID string, event *Event) error {
+	return sendDirectMessage(s, userID, &discordgo.MessageSend{
+		Embeds: []*discordgo.MessageEmbed{
+			p.Embed(&discordgo.MessageEmbed{
+				Title:       event.Title,
+				Color:       event.Color,
+				Description: p.Tf("Your scheduled event is now live. [Click here to view the event](%s)", event.DiscordLink),
+			}),
+		},
+	})
+}
+
+// NotifyPostFailed sends the organizer a DM when their scheduled post could not go out. It is kept as a draft.
+func NotifyPostFailed(s *discordgo.Session, p *Printer, userID string, d store.Draft) error {
+	return sendDirectMessage(s, userID, &discordgo.MessageSend{
+		Embeds: []*discordgo.MessageEmbed{
+			p.Embed(&discordgo.MessageEmbed{
+				Title:       d.Title,
+				Color:       Purple,
+				Description: p.T("Your scheduled event couldn't be posted. It was saved as a draft, so use `/drafts` to fix it and try again."),
+			}),
+		},
+	})
+}
+
+// DraftsMessage lists the drafts and scheduled posts of an organizer with buttons to edit or cancel them
+func DraftsMessage(drafts []store.Draft) (*discordgo.MessageEmbed, []discordgo.MessageComponent) {
+	embed := &discordgo.MessageEmbed{
+		Title: "Your drafts",
+		Color: Purple,
+	}
+	if len(drafts) == 0 {
+		embed.Description = "You don't have any drafts or scheduled posts"
+		return embed, []discordgo.MessageComponent{}
+	}
+
+	lines := make([]string, 0, len(drafts))
+	components := []discordgo.MessageComponent{}
+	for n, d := range drafts {
+		line := fmt.Sprintf("**%d** %s", n+1, d.Title)
+		if !d.Start.IsZero() {
+			line += fmt.Sprintf(" ⋅ <t:%d:F>", d.Start.Unix())
+		}
+		cancel := "Delete"
+		if d.Scheduled() {
+			line += fmt.Sprintf("\n⠀Posts <t:%d:R>", d.PublishAt.Unix())
+			cancel = "Cancel"
+		}
+		lines = append(lines, line)
+
+		if n >= maxDraftButtons {
+			continue
+		}
+		components = append(components, discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    fmt.Sprintf("Edit %d", n+1),
+					Style:    discordgo.SecondaryButton,
+					CustomID: DraftActionID(DraftEditPrefix, d.ID),
+				},
+				discordgo.Button{
+					Label:    fmt.Sprintf("%s %d", cancel, n+1),
+					Style:    discordgo.DangerButton,
+					CustomID: DraftActionID(DraftCancelPrefix, d.ID),
+				},
+			},
+		})
+	}
+	embed.Description = strings.Join(lines, "\n")
+	if len(drafts) > maxDraftButtons {
+		embed.Footer = &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("Only the first %d drafts have buttons", maxDraftButtons),
+		}
+	}
+	return embed, components
+}
+
+// DraftActionID is the custom ID of a button acting on a draft
+func DraftActionID(prefix, draftID string) string {
+	return strings.Join([]string{prefix, draftID}, idSeparator)
+}
+
+// ParseDraftActionID gets the draft a button acts on
+func ParseDraftActionID(customID string) (string, error) {
+	prefix, draftID, _ := strings.Cut(customID, idSeparator)
+	if (prefix != DraftEditPrefix && prefix != DraftCancelPrefix) || draftID == "" {
+		return "", fmt.Errorf("invalid draft ID: %s", customID)
+	}
+	return draftID, nil
+}
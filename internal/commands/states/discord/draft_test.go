@@ -0,0 +1,112 @@
+package discord
+
+import (
+	"github.com/GuessWhoSamFoo/fsm"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/role"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/store"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestToDraft(t *testing.T) {
+	start := time.Date(2023, 6, 10, 20, 0, 0, 0, time.UTC)
+	rg := role.NewDefaultRoleGroup()
+	rg.SetLimit(role.AcceptedField, 8)
+	event := &Event{
+		GuildID:   "guild",
+		Title:     "Board games",
+		Location:  "Library",
+		Start:     start,
+		End:       start.Add(2 * time.Hour),
+		Owner:     "foo",
+		RoleGroup: rg,
+		Category:  Category{Name: "Social"},
+	}
+
+	d := ToDraft(event)
+	assert.Equal(t, "guild", d.GuildID)
+	assert.Equal(t, "Board games", d.Title)
+	assert.Equal(t, "foo", d.Owner)
+	assert.Equal(t, "Social", d.Category)
+	assert.Equal(t, 8, d.Limit)
+
+	published := FromDraft(d, DefaultCategories)
+	assert.Equal(t, event.Title, published.Title)
+	assert.Equal(t, event.Location, published.Location)
+	assert.Equal(t, event.End, published.End)
+	assert.Equal(t, "foo", published.Owner)
+	assert.Equal(t, "guild", published.GuildID)
+	assert.Equal(t, "Social", published.Category.Name)
+	assert.NotEqual(t, 0, published.Color)
+	assert.Equal(t, 8, published.RoleGroup.GetLimit(role.AcceptedField))
+
+	d.Category = "removed"
+	assert.Equal(t, Purple, FromDraft(d, DefaultCategories).Color)
+}
+
+func TestLoadDraft(t *testing.T) {
+	publishAt := time.Date(2023, 6, 9, 12, 0, 0, 0, time.UTC)
+	f := fsm.NewFSM("idle", fsm.Events{}, fsm.Callbacks{})
+	LoadDraft(f, store.Draft{ID: "1", Title: "Board games", Limit: 4, ChannelID: "channel", PublishAt: publishAt}, DefaultCategories)
+
+	event, err := FromFSMToEvent(f)
+	assert.NoError(t, err)
+	assert.Equal(t, "Board games", event.Title)
+	assert.Equal(t, 4, event.RoleGroup.GetLimit(role.AcceptedField))
+
+	for key, expected := range map[MetadataKey]interface{}{DraftID: "1", ChannelID: "channel", PublishAt: publishAt} {
+		actual, found := f.Metadata(key.String())
+		assert.True(t, found)
+		assert.Equal(t, expected, actual)
+	}
+}
+
+func TestReviewEventMessage(t *testing.T) {
+	msg := ReviewEventMessage(time.Time{})
+	assert.Contains(t, msg.Description, "**3** Save as a draft to finish later")
+	assert.NotContains(t, msg.Description, "Scheduled")
+
+	publishAt := time.Date(2023, 6, 9, 12, 0, 0, 0, time.UTC)
+	msg = ReviewEventMessage(publishAt)
+	assert.Contains(t, msg.Description, "**3** Save the changes and keep the schedule")
+	assert.Contains(t, msg.Description, strconv.FormatInt(publishAt.Unix(), 10))
+}
+
+func TestDraftsMessage(t *testing.T) {
+	embed, components := DraftsMessage(nil)
+	assert.Equal(t, "You don't have any drafts or scheduled posts", embed.Description)
+	assert.Empty(t, components)
+
+	start := time.Date(2023, 6, 10, 20, 0, 0, 0, time.UTC)
+	var drafts []store.Draft
+	for n := 1; n <= 6; n++ {
+		drafts = append(drafts, store.Draft{ID: strconv.Itoa(n), Title: "Draft", Start: start})
+	}
+	drafts[0].PublishAt = start.Add(-time.Hour)
+
+	embed, components = DraftsMessage(drafts)
+	assert.Contains(t, embed.Description, "**6** Draft")
+	assert.Contains(t, embed.Description, "Posts <t:")
+	assert.NotNil(t, embed.Footer)
+	assert.Len(t, components, maxDraftButtons)
+
+	buttons := components[0].(discordgo.ActionsRow).Components
+	assert.Equal(t, "Edit 1", buttons[0].(discordgo.Button).Label)
+	assert.Equal(t, "Cancel 1", buttons[1].(discordgo.Button).Label)
+	assert.Equal(t, "Delete 2", components[1].(discordgo.ActionsRow).Components[1].(discordgo.Button).Label)
+	assert.Equal(t, DraftCancelPrefix, ComponentPrefix(buttons[1].(discordgo.Button).CustomID))
+}
+
+func TestParseDraftActionID(t *testing.T) {
+	id, err := ParseDraftActionID(DraftActionID(DraftEditPrefix, "123"))
+	assert.NoError(t, err)
+	assert.Equal(t, "123", id)
+
+	_, err = ParseDraftActionID("draft_edit")
+	assert.Error(t, err)
+	_, err = ParseDraftActionID("undo:123")
+	assert.Error(t, err)
+}
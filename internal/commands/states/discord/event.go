@@ -4,10 +4,11 @@ import (
 	"fmt"
 	"github.com/GuessWhoSamFoo/fsm"
 	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/role"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/logging"
 	"github.com/GuessWhoSamFoo/gang-gang-bot/pkg/util"
 	"github.com/bwmarrin/discordgo"
 	"github.com/tj/go-naturaldate"
-	"log"
+	"log/slog"
 	"strings"
 	"time"
 )
@@ -15,21 +16,39 @@ import (
 type MetadataKey string
 
 const (
-	Action      MetadataKey = "action"
-	GuildID     MetadataKey = "guildID"
-	Title       MetadataKey = "title"
-	Description MetadataKey = "description"
-	Attendee    MetadataKey = "attendee"
-	Location    MetadataKey = "location"
-	StartTime   MetadataKey = "start"
-	Duration    MetadataKey = "duration"
-	Owner       MetadataKey = "owner"
-	Color       MetadataKey = "color"
-	ID          MetadataKey = "id"
-	MenuOption  MetadataKey = "menuOption"
+	Action       MetadataKey = "action"
+	GuildID      MetadataKey = "guildID"
+	Title        MetadataKey = "title"
+	Description  MetadataKey = "description"
+	Attendee     MetadataKey = "attendee"
+	Location     MetadataKey = "location"
+	StartTime    MetadataKey = "start"
+	Duration     MetadataKey = "duration"
+	Deadline     MetadataKey = "deadline"
+	Guests       MetadataKey = "guests"
+	Recipients   MetadataKey = "recipients"
+	AnnounceText MetadataKey = "announceText"
+	Owner        MetadataKey = "owner"
+	Color        MetadataKey = "color"
+	CategoryName MetadataKey = "category"
+	ID           MetadataKey = "id"
+	MenuOption   MetadataKey = "menuOption"
+	DraftID      MetadataKey = "draftID"
+	ChannelID    MetadataKey = "channelID"
+	PublishAt    MetadataKey = "publishAt"
 
 	EventObject MetadataKey = "eventObject"
 	Username    MetadataKey = "username"
+
+	// Original is the event as it was before the edit, which changes are compared against
+	Original MetadataKey = "original"
+	Changes  MetadataKey = "changes"
+	// Notify is whether members who signed up are messaged about changes to the time or location
+	Notify MetadataKey = "notify"
+	// Removed are the users whose responses an edit removes
+	Removed MetadataKey = "removed"
+	// Response is the signup option an edit adds the user to
+	Response MetadataKey = "response"
 )
 
 func (m MetadataKey) String() string {
@@ -54,6 +73,33 @@ type Event struct {
 	Color       int
 	ID          string // base64 encoded eventID + calendarID
 	DiscordLink string
+
+	GuildID          string
+	ScheduledEventID string // ID of the linked Discord guild scheduled event
+
+	Deadline time.Time // signups close at the deadline when set
+	Locked   bool      // signups were closed by an organizer
+	Archived bool      // the event ended and its buttons were disabled
+
+	MaxGuests int // how many guests each member can bring, or 0 if guests are not allowed
+
+	ThreadID string // discussion thread attached to the event message, if any
+
+	Category Category // empty when the event has no category
+}
+
+// Ended reports whether an event is over. Events without a duration end when they start.
+func (e *Event) Ended(now time.Time) bool {
+	end := e.End
+	if end.IsZero() {
+		end = e.Start
+	}
+	return !now.Before(end)
+}
+
+// SignupsClosed reports whether new signups are no longer accepted
+func (e *Event) SignupsClosed(now time.Time) bool {
+	return e.Archived || e.Locked || (!e.Deadline.IsZero() && !now.Before(e.Deadline))
 }
 
 func (e *Event) AddTitle(title string) {
@@ -92,6 +138,15 @@ func (e *Event) SetDuration(length string) error {
 	return nil
 }
 
+// SetCategory changes the category of an event along with its color. Events without a category are purple.
+func (e *Event) SetCategory(category Category) {
+	e.Category = category
+	e.Color = Purple
+	if category.Color != 0 {
+		e.Color = category.Color
+	}
+}
+
 func (e *Event) SetLocation(location string) error {
 	if location == "" {
 		return nil
@@ -100,55 +155,66 @@ func (e *Event) SetLocation(location string) error {
 	return nil
 }
 
-func (e *Event) ToggleAccept(s *discordgo.Session, i *discordgo.InteractionCreate, name string) error {
+// ToggleResponse toggles a user's response and returns the users moved off the waitlist as a result, if any
+func (e *Event) ToggleResponse(field role.FieldType, name string) ([]string, error) {
 	if e.RoleGroup == nil {
-		return fmt.Errorf("missing role group")
+		return nil, fmt.Errorf("missing role group")
 	}
-	prev := e.RoleGroup.PeekWaitlist(role.AcceptedField)
-	if err := e.RoleGroup.ToggleRole(role.AcceptedField, name); err != nil {
-		return err
-	}
-	if prev != "" && prev != e.RoleGroup.PeekWaitlist(role.AcceptedField) {
-		if err := e.NotifyUserOffWaitlist(s, i.Interaction, prev); err != nil {
-			return err
-		}
+	prev := e.RoleGroup.WaitlistUsers(role.AcceptedField)
+	if err := e.RoleGroup.ToggleRole(field, name); err != nil {
+		return nil, err
 	}
-	return nil
+	return e.promotedFrom(prev), nil
 }
 
-func (e *Event) ToggleDecline(s *discordgo.Session, i *discordgo.InteractionCreate, name string) error {
+// SetGuests changes how many guests a user is bringing and returns the users moved off the waitlist as a result
+func (e *Event) SetGuests(name string, guests int) ([]string, error) {
 	if e.RoleGroup == nil {
-		return fmt.Errorf("missing role group")
+		return nil, fmt.Errorf("missing role group")
 	}
-	prev := e.RoleGroup.PeekWaitlist(role.AcceptedField)
-	if err := e.RoleGroup.ToggleRole(role.DeclinedField, name); err != nil {
-		return err
+	if guests < 0 || guests > e.MaxGuests {
+		return nil, fmt.Errorf("guests out of range: %d", guests)
 	}
-	if prev != "" && prev != e.RoleGroup.PeekWaitlist(role.AcceptedField) {
-		if err := e.NotifyUserOffWaitlist(s, i.Interaction, prev); err != nil {
-			return err
+	return e.RoleGroup.SetGuests(name, guests)
+}
+
+// promotedFrom returns the users who were on the waitlist and have since been moved into the role
+func (e *Event) promotedFrom(waitlisted []string) []string {
+	var promoted []string
+	for _, name := range waitlisted {
+		if e.RoleGroup.HasUser(name, role.AcceptedField) {
+			promoted = append(promoted, name)
 		}
 	}
-	return nil
+	return promoted
 }
 
-func (e *Event) ToggleTentative(s *discordgo.Session, i *discordgo.InteractionCreate, name string) error {
-	if e.RoleGroup == nil {
-		return fmt.Errorf("missing role group")
-	}
-	prev := e.RoleGroup.PeekWaitlist(role.AcceptedField)
-	if err := e.RoleGroup.ToggleRole(role.TentativeField, name); err != nil {
+func (e *Event) ToggleAccept(s *discordgo.Session, i *discordgo.InteractionCreate, printers PrinterFunc, name string) error {
+	return e.toggleAndNotify(s, i, printers, role.AcceptedField, name)
+}
+
+func (e *Event) ToggleDecline(s *discordgo.Session, i *discordgo.InteractionCreate, printers PrinterFunc, name string) error {
+	return e.toggleAndNotify(s, i, printers, role.DeclinedField, name)
+}
+
+func (e *Event) ToggleTentative(s *discordgo.Session, i *discordgo.InteractionCreate, printers PrinterFunc, name string) error {
+	return e.toggleAndNotify(s, i, printers, role.TentativeField, name)
+}
+
+func (e *Event) toggleAndNotify(s *discordgo.Session, i *discordgo.InteractionCreate, printers PrinterFunc, field role.FieldType, name string) error {
+	promoted, err := e.ToggleResponse(field, name)
+	if err != nil {
 		return err
 	}
-	if prev != "" && prev != e.RoleGroup.PeekWaitlist(role.AcceptedField) {
-		if err := e.NotifyUserOffWaitlist(s, i.Interaction, prev); err != nil {
+	for _, name := range promoted {
+		if err = e.NotifyUserOffWaitlist(s, printers, i.GuildID, name); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (e *Event) RemoveFromAllLists(s *discordgo.Session, i *discordgo.InteractionCreate, name string) error {
+func (e *Event) RemoveFromAllLists(s *discordgo.Session, i *discordgo.InteractionCreate, printers PrinterFunc, name string) error {
 	if e.RoleGroup == nil {
 		return fmt.Errorf("missing role group")
 	}
@@ -157,7 +223,7 @@ func (e *Event) RemoveFromAllLists(s *discordgo.Session, i *discordgo.Interactio
 		return err
 	}
 	if prev != "" && prev != e.RoleGroup.PeekWaitlist(role.AcceptedField) {
-		if err := e.NotifyUserOffWaitlist(s, i.Interaction, prev); err != nil {
+		if err := e.NotifyUserOffWaitlist(s, printers, i.GuildID, prev); err != nil {
 			return err
 		}
 	}
@@ -168,64 +234,123 @@ func (e *Event) PromoteFromWaitlists() ([]string, error) {
 	if e.RoleGroup == nil {
 		return []string{}, fmt.Errorf("missing role group")
 	}
-	promoted := make([]string, 0)
-	for _, r := range e.RoleGroup.Roles {
-		wl, hasWaitlist := e.RoleGroup.Waitlist[r.FieldName]
-		if hasWaitlist && r.Limit > len(r.Users) {
-			count := r.Limit - len(r.Users)
-			for c := 0; c < count && len(wl.Users) > 0; c++ {
-				var user string
-				user, wl.Users = wl.Users[0], wl.Users[1:]
-				wl.Count--
-				r.Users = append(r.Users, user)
-				r.Count++
-				promoted = append(promoted, user)
-			}
-		}
-	}
-	return promoted, nil
+	return e.RoleGroup.PromoteFromWaitlists(), nil
 }
 
-func (e *Event) NotifyUserOffWaitlist(s *discordgo.Session, i *discordgo.Interaction, name string) error {
+// NotifyUserOffWaitlist sends a DM with a link to the event to a user who was moved off the waitlist and adds them to
+// the event thread
+func (e *Event) NotifyUserOffWaitlist(s *discordgo.Session, printers PrinterFunc, guildID, name string) error {
 	if name == "" {
 		return nil
 	}
-	c, err := s.UserChannelCreate(i.Member.User.ID)
+	m, err := FindMember(s, guildID, name)
+	if err != nil || m == nil {
+		return err
+	}
+	p := printers.For(m.User.ID)
+	if err = sendDirectMessage(s, m.User.ID, &discordgo.MessageSend{
+		Embeds: []*discordgo.MessageEmbed{
+			p.Embed(&discordgo.MessageEmbed{
+				Title:       "You have been moved off the waitlist!",
+				Color:       Purple,
+				Description: p.Tf("[Click here to view the event](%s)", e.DiscordLink),
+			}),
+		},
+	}); err != nil {
+		return err
+	}
+	return e.SyncThreadMember(s, m.User.ID, role.Response{Field: role.AcceptedField, Waitlist: 1}, e.RoleGroup.GetResponse(name))
+}
+
+// NotifyUserOnWaitlist sends a DM with a link to the event to a user who was moved to the waitlist because the
+// attendee limit was lowered and removes them from the event thread
+func (e *Event) NotifyUserOnWaitlist(s *discordgo.Session, printers PrinterFunc, guildID, name string) error {
+	after := e.RoleGroup.GetResponse(name)
+	if after.Waitlist == 0 {
+		return nil
+	}
+	m, err := FindMember(s, guildID, name)
+	if err != nil || m == nil {
+		return err
+	}
+	p := printers.For(m.User.ID)
+	if err = sendDirectMessage(s, m.User.ID, &discordgo.MessageSend{
+		Embeds: []*discordgo.MessageEmbed{
+			p.Embed(&discordgo.MessageEmbed{
+				Title:       "You have been moved to the waitlist",
+				Color:       Purple,
+				Description: p.Tf("The organizer lowered the attendee limit. You are #%d on the waitlist and will be messaged if a spot opens up. [Click here to view the event](%s)", after.Waitlist, e.DiscordLink),
+			}),
+		},
+	}); err != nil {
+		return err
+	}
+	return e.SyncThreadMember(s, m.User.ID, role.Response{Field: role.AcceptedField}, after)
+}
+
+// NotifyNewOwner sends a DM with a link to the event to the member who was made its organizer
+func NotifyNewOwner(s *discordgo.Session, printers PrinterFunc, guildID string, e *Event) error {
+	m, err := FindMember(s, guildID, e.Owner)
+	if err != nil || m == nil {
+		return err
+	}
+	p := printers.For(m.User.ID)
+	return sendDirectMessage(s, m.User.ID, &discordgo.MessageSend{
+		Embeds: []*discordgo.MessageEmbed{
+			p.Embed(&discordgo.MessageEmbed{
+				Title:       p.Tf("You are now organizing %s", e.Title),
+				Color:       e.Color,
+				Description: p.Tf("You can edit the event and take attendance. [Click here to view the event](%s)", e.DiscordLink),
+			}),
+		},
+	})
+}
+
+// SendDirectMessage sends a DM to the guild member with a username. Members who cannot be found are skipped.
+func SendDirectMessage(s *discordgo.Session, guildID, name string, msg *discordgo.MessageSend) error {
+	m, err := FindMember(s, guildID, name)
+	if err != nil || m == nil {
+		return err
+	}
+	return sendDirectMessage(s, m.User.ID, msg)
+}
+
+func sendDirectMessage(s *discordgo.Session, userID string, msg *discordgo.MessageSend) error {
+	c, err := s.UserChannelCreate(userID)
 	if err != nil {
 		return err
 	}
+	_, err = s.ChannelMessageSendComplex(c.ID, msg)
+	return err
+}
+
+// FindMember gets the guild member with a username, or nil if there is none
+func FindMember(s *discordgo.Session, guildID, name string) (*discordgo.Member, error) {
 	// TODO: Handle guilds with more than 1000 members
-	members, err := s.GuildMembersSearch(i.GuildID, name, 1000)
+	members, err := s.GuildMembersSearch(guildID, name, 1000)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	for _, m := range members {
 		if m.User.Username == name {
-			if _, err := s.ChannelMessageSendEmbed(c.ID, &discordgo.MessageEmbed{
-				Title:       "You have been moved off the waitlist!",
-				Color:       Purple,
-				Description: fmt.Sprintf("[Click here to view the event](https://discord.com/channels/%s/%s/%s)", i.GuildID, i.ChannelID, i.Message.ID),
-			}); err != nil {
-				return err
-			}
-			break
+			return m, nil
 		}
 	}
-	return nil
+	return nil, nil
 }
 
 // NotifyCommandInProgress notifies a user if another interaction is pending input
-func NotifyCommandInProgress(s *discordgo.Session, i *discordgo.InteractionCreate) {
+func NotifyCommandInProgress(s *discordgo.Session, i *discordgo.InteractionCreate, p *Printer) {
 	if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
 			Embeds: []*discordgo.MessageEmbed{
-				&CommandInProcessMessage,
+				p.Embed(&CommandInProcessMessage),
 			},
 			Flags: discordgo.MessageFlagsEphemeral,
 		},
 	}); err != nil {
-		log.Printf("failed to send message: %v", err)
+		slog.Error("failed to send message", logging.ErrorKey, err)
 	}
 }
 
@@ -255,6 +380,13 @@ func FromFSMToEvent(f *fsm.FSM) (*Event, error) {
 		}
 		e.End = val
 	}
+	if deadline, found := f.Metadata(Deadline.String()); found {
+		val, ok := deadline.(time.Time)
+		if !ok {
+			return nil, fmt.Errorf("cannot cast key: %s", Deadline.String())
+		}
+		e.Deadline = val
+	}
 	if rg, found := f.Metadata(Attendee.String()); found {
 		val, ok := rg.(*role.RoleGroup)
 		if !ok {
@@ -271,9 +403,73 @@ func FromFSMToEvent(f *fsm.FSM) (*Event, error) {
 			e.Color = val
 		}
 	}
+	if category, found := f.Metadata(CategoryName.String()); found {
+		val, ok := category.(Category)
+		if !ok {
+			return nil, fmt.Errorf("cannot cast key: %s", CategoryName.String())
+		}
+		e.SetCategory(val)
+	}
+	if guildID, found := f.Metadata(GuildID.String()); found {
+		e.GuildID = fmt.Sprintf("%s", guildID)
+	}
 	return e, nil
 }
 
+// FromGuildScheduledEvent converts a Discord guild scheduled event into an event type
+func FromGuildScheduledEvent(guildEvent *discordgo.GuildScheduledEvent) *Event {
+	e := &Event{
+		Title:            guildEvent.Name,
+		Description:      util.RemoveDiscordLink(guildEvent.Description),
+		Location:         guildEvent.EntityMetadata.Location,
+		Start:            guildEvent.ScheduledStartTime,
+		RoleGroup:        role.NewDefaultRoleGroup(),
+		Color:            Purple,
+		GuildID:          guildEvent.GuildID,
+		ScheduledEventID: guildEvent.ID,
+	}
+	// Voice and stage events are hosted in a channel rather than an external location
+	if e.Location == "" && guildEvent.ChannelID != "" {
+		e.Location = fmt.Sprintf("<#%s>", guildEvent.ChannelID)
+	}
+	if guildEvent.ScheduledEndTime != nil {
+		e.End = *guildEvent.ScheduledEndTime
+	}
+	if guildEvent.Creator != nil {
+		e.Owner = guildEvent.Creator.Username
+	}
+	return e
+}
+
+// SyncFromGuildScheduledEvent copies details from a linked guild scheduled event and reports whether anything changed
+func (e *Event) SyncFromGuildScheduledEvent(guildEvent *discordgo.GuildScheduledEvent) bool {
+	updated := FromGuildScheduledEvent(guildEvent)
+	var changed bool
+	if updated.Title != "" && updated.Title != e.Title {
+		e.Title = updated.Title
+		changed = true
+	}
+	// Descriptions written by the bot may have been cut short to fit, which is not a change
+	written := util.AppendDiscordLink(e.Description, util.FindDiscordLink(guildEvent.Description), GuildEventDescriptionLimit)
+	if updated.Description != "" && updated.Description != e.Description && guildEvent.Description != written {
+		e.Description = updated.Description
+		changed = true
+	}
+	if updated.Location != "" && updated.Location != e.Location {
+		e.Location = updated.Location
+		changed = true
+	}
+	if !updated.Start.IsZero() && !updated.Start.Equal(e.Start) {
+		e.Start = updated.Start
+		changed = true
+	}
+	if !updated.End.IsZero() && !updated.End.Equal(e.End) {
+		e.End = updated.End
+		changed = true
+	}
+	return changed
+}
+
 // GetEventFromMessage converts a Discord message into an event type
 func GetEventFromMessage(msg *discordgo.Message) (*Event, error) {
 	if len(msg.Embeds) != 1 {
@@ -302,29 +498,29 @@ func GetEventFromMessage(msg *discordgo.Message) (*Event, error) {
 			if err != nil {
 				return nil, err
 			}
-			users := util.GetUsersFromValues(f.Value)
+			users, count := parseUsers(e.RoleGroup, f.Value)
 			e.RoleGroup.Roles = append(e.RoleGroup.Roles, &role.Role{
 				Icon:      role.AcceptedIcon,
 				FieldName: role.AcceptedField,
 				Users:     users,
-				Count:     len(users),
+				Count:     count,
 				Limit:     limit,
 			})
 		case strings.Contains(f.Name, DeclinedBase):
-			users := util.GetUsersFromValues(f.Value)
+			users, count := parseUsers(e.RoleGroup, f.Value)
 			e.RoleGroup.Roles = append(e.RoleGroup.Roles, &role.Role{
 				Icon:      role.DeclinedIcon,
 				FieldName: role.DeclinedField,
 				Users:     users,
-				Count:     len(users),
+				Count:     count,
 			})
 		case strings.Contains(f.Name, TentativeBase):
-			users := util.GetUsersFromValues(f.Value)
+			users, count := parseUsers(e.RoleGroup, f.Value)
 			e.RoleGroup.Roles = append(e.RoleGroup.Roles, &role.Role{
 				Icon:      role.TentativeIcon,
 				FieldName: role.TentativeField,
 				Users:     users,
-				Count:     len(users),
+				Count:     count,
 			})
 		case f.Name == "Links":
 			e.Start, e.End, err = util.GetTimesFromLink(f.Value)
@@ -332,20 +528,48 @@ func GetEventFromMessage(msg *discordgo.Message) (*Event, error) {
 				return nil, err
 			}
 		case f.Name == "Calendar":
-			e.ID, err = util.ParseEventID(f.Value)
-			if err != nil {
-				return nil, err
+			for _, link := range strings.Split(f.Value, "\n") {
+				if util.IsScheduledEventLink(link) {
+					e.GuildID, e.ScheduledEventID, err = util.ParseScheduledEventLink(link)
+					if err != nil {
+						return nil, err
+					}
+					continue
+				}
+				e.ID, err = util.ParseEventID(link)
+				if err != nil {
+					return nil, err
+				}
 			}
 		case f.Name == "Location":
 			e.Location = f.Value
+		case f.Name == "Status":
+			e.Archived = f.Value == EndedText
+		case f.Name == "Signups":
+			e.Deadline, e.Locked, err = util.ParseSignups(f.Value)
+			if err != nil {
+				return nil, err
+			}
+		case f.Name == "Guests":
+			e.MaxGuests, err = util.ParseGuestLimit(f.Value)
+			if err != nil {
+				return nil, err
+			}
+		case f.Name == "Discussion":
+			e.ThreadID, err = util.ParseChannelMention(f.Value)
+			if err != nil {
+				return nil, err
+			}
 		case f.Name == string(role.WaitlistField):
-			users := util.GetUsersFromValues(f.Value)
+			users, count := parseUsers(e.RoleGroup, f.Value)
 			e.RoleGroup.Waitlist[role.AcceptedField] = &role.Role{
 				Icon:      "",
 				FieldName: role.WaitlistField,
 				Users:     users,
-				Count:     len(users),
+				Count:     count,
 			}
+		case f.Name == "Category":
+			e.Category = ParseCategory(f.Value)
 		case f.Name == "Time":
 			// no-op since start/end times comes from Links
 		default:
@@ -362,20 +586,93 @@ func GetEventFromMessage(msg *discordgo.Message) (*Event, error) {
 	return e, nil
 }
 
+// parseUsers gets the users listed in a field value, recording their guests in the role group, and the number of spots
+// they take up
+func parseUsers(rg *role.RoleGroup, value string) ([]string, int) {
+	users := util.GetUsersFromValues(value)
+	count := len(users)
+	for i, u := range users {
+		name, guests := util.ParseGuests(u)
+		if guests == 0 {
+			continue
+		}
+		if rg.Guests == nil {
+			rg.Guests = map[string]int{}
+		}
+		users[i] = name
+		rg.Guests[name] = guests
+		count += guests
+	}
+	return users, count
+}
+
+// printUsers lists users for a field value along with their guests
+func printUsers(rg *role.RoleGroup, users []string) string {
+	listed := make([]string, 0, len(users))
+	for _, u := range users {
+		listed = append(listed, util.PrintGuests(u, rg.Guests[u]))
+	}
+	return util.NameListToValues(listed)
+}
+
+// PrintCalendarLinks prints the Google calendar link of an event followed by the Discord scheduled event link. Either
+// is left out when the event has none, and an event without either has no links.
+func PrintCalendarLinks(event *Event) string {
+	var links []string
+	if event.ID != "" {
+		links = append(links, util.PrintGoogleCalendarEventLink(event.ID))
+	}
+	if event.GuildID != "" && event.ScheduledEventID != "" {
+		links = append(links, util.PrintScheduledEventLink(event.GuildID, event.ScheduledEventID))
+	}
+	return strings.Join(links, "\n")
+}
+
+// GuildEventDescriptionLimit is how many characters the description of a guild scheduled event can have
+const GuildEventDescriptionLimit = 1000
+
+// ToGuildScheduledEventParams converts an event into parameters for a Discord guild scheduled event
+func ToGuildScheduledEventParams(event *Event) *discordgo.GuildScheduledEventParams {
+	return &discordgo.GuildScheduledEventParams{
+		Name:               event.Title,
+		Description:        util.AppendDiscordLink(event.Description, event.DiscordLink, GuildEventDescriptionLimit),
+		ScheduledStartTime: &event.Start,
+		ScheduledEndTime:   &event.End,
+		PrivacyLevel:       discordgo.GuildScheduledEventPrivacyLevelGuildOnly,
+		EntityType:         discordgo.GuildScheduledEventEntityTypeExternal,
+		EntityMetadata: &discordgo.GuildScheduledEventEntityMetadata{
+			Location: event.Location,
+		},
+	}
+}
+
 // ConvertEventToMessageEmbed converts an internal Event into a Discord Embed message
 func ConvertEventToMessageEmbed(event *Event) (*discordgo.MessageEmbed, error) {
 	msg := &discordgo.MessageEmbed{}
-	fields := []*discordgo.MessageEmbedField{
-		{
+	var fields []*discordgo.MessageEmbedField
+	if event.Archived {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:  "Status",
+			Value: EndedText,
+		})
+	}
+	if event.Category.Name != "" {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:  "Category",
+			Value: event.Category.String(),
+		})
+	}
+	fields = append(fields,
+		&discordgo.MessageEmbedField{
 			Name:  "Time",
 			Value: util.PrintTime(event.Start, event.End),
 		},
-		{
+		&discordgo.MessageEmbedField{
 			Name:   "Links",
 			Value:  util.PrintAddGoogleCalendarLink(event.Title, event.Description, event.Start, event.End),
 			Inline: true,
 		},
-	}
+	)
 
 	if event.Location != "" {
 		fields = append(fields, &discordgo.MessageEmbedField{
@@ -384,10 +681,31 @@ func ConvertEventToMessageEmbed(event *Event) (*discordgo.MessageEmbed, error) {
 			Inline: true,
 		})
 	}
-	if event.ID != "" {
+	if links := PrintCalendarLinks(event); links != "" {
 		fields = append(fields, &discordgo.MessageEmbedField{
 			Name:  "Calendar",
-			Value: util.PrintGoogleCalendarEventLink(event.ID),
+			Value: links,
+		})
+	}
+
+	if event.Locked || !event.Deadline.IsZero() {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:  "Signups",
+			Value: util.PrintSignups(event.Deadline, event.Locked),
+		})
+	}
+
+	if event.MaxGuests > 0 {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:  "Guests",
+			Value: util.PrintGuestLimit(event.MaxGuests),
+		})
+	}
+
+	if event.ThreadID != "" {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:  "Discussion",
+			Value: fmt.Sprintf("<#%s>", event.ThreadID),
 		})
 	}
 
@@ -401,7 +719,7 @@ func ConvertEventToMessageEmbed(event *Event) (*discordgo.MessageEmbed, error) {
 		}
 		fields = append(fields, &discordgo.MessageEmbedField{
 			Name:   name,
-			Value:  util.NameListToValues(r.Users),
+			Value:  printUsers(event.RoleGroup, r.Users),
 			Inline: true,
 		})
 	}
@@ -410,7 +728,7 @@ func ConvertEventToMessageEmbed(event *Event) (*discordgo.MessageEmbed, error) {
 		if wl, ok := event.RoleGroup.Waitlist[r.FieldName]; ok && wl.Count > 0 {
 			fields = append(fields, &discordgo.MessageEmbedField{
 				Name:  string(wl.FieldName),
-				Value: util.NameListToValues(wl.Users),
+				Value: printUsers(event.RoleGroup, wl.Users),
 			})
 		}
 	}
@@ -420,7 +738,25 @@ func ConvertEventToMessageEmbed(event *Event) (*discordgo.MessageEmbed, error) {
 	msg.Color = event.Color
 	msg.Fields = fields
 	msg.Footer = &discordgo.MessageEmbedFooter{
-		Text: fmt.Sprintf("Created by %v", event.Owner),
+		Text: util.PrintFooter(event.Owner),
 	}
 	return msg, nil
 }
+
+// EditEventMessage replaces the embed of an event message
+func EditEventMessage(s *discordgo.Session, channelID, messageID string, event *Event) error {
+	return EditEventMessageComponents(s, channelID, messageID, event, nil)
+}
+
+// EditEventMessageComponents replaces the embed of an event message along with its buttons in a single edit, so one
+// cannot change without the other. Nil components keep the buttons as they are.
+func EditEventMessageComponents(s *discordgo.Session, channelID, messageID string, event *Event, components []discordgo.MessageComponent) error {
+	embed, err := ConvertEventToMessageEmbed(event)
+	if err != nil {
+		return err
+	}
+	edit := discordgo.NewMessageEdit(channelID, messageID).SetEmbeds([]*discordgo.MessageEmbed{embed})
+	edit.Components = components
+	_, err = s.ChannelMessageEditComplex(edit)
+	return err
+}
@@ -8,8 +8,10 @@ import (
 	"github.com/bwmarrin/discordgo"
 	"github.com/ewohltman/discordgo-mock/mockconstants"
 	"github.com/stretchr/testify/assert"
+	"strings"
 	"testing"
 	"time"
+	"unicode/utf8"
 )
 
 func TestEvent_AddTitle(t *testing.T) {
@@ -26,7 +28,7 @@ func TestEvent_ToggleAccept(t *testing.T) {
 	e := Event{}
 	e.RoleGroup = rg
 
-	err = e.ToggleAccept(session, nil, mockconstants.TestUser)
+	err = e.ToggleAccept(session, nil, nil, mockconstants.TestUser)
 	assert.NoError(t, err)
 }
 
@@ -37,7 +39,7 @@ func TestEvent_ToggleDecline(t *testing.T) {
 	e := Event{}
 	e.RoleGroup = rg
 
-	err = e.ToggleDecline(session, nil, mockconstants.TestUser)
+	err = e.ToggleDecline(session, nil, nil, mockconstants.TestUser)
 	assert.NoError(t, err)
 }
 
@@ -48,10 +50,256 @@ func TestEvent_ToggleTentative(t *testing.T) {
 	e := Event{}
 	e.RoleGroup = rg
 
-	err = e.ToggleTentative(session, nil, mockconstants.TestUser)
+	err = e.ToggleTentative(session, nil, nil, mockconstants.TestUser)
 	assert.NoError(t, err)
 }
 
+func TestEvent_ToggleResponse(t *testing.T) {
+	rg := role.NewDefaultRoleGroup()
+	rg.Roles[0].Limit = 1
+	e := Event{RoleGroup: rg}
+
+	promoted, err := e.ToggleResponse(role.AcceptedField, "foo")
+	assert.NoError(t, err)
+	assert.Empty(t, promoted)
+
+	promoted, err = e.ToggleResponse(role.AcceptedField, "bar")
+	assert.NoError(t, err)
+	assert.Empty(t, promoted)
+	assert.Equal(t, "bar", e.RoleGroup.PeekWaitlist(role.AcceptedField))
+
+	promoted, err = e.ToggleResponse(role.DeclinedField, "foo")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"bar"}, promoted)
+	assert.True(t, e.RoleGroup.HasUser("bar", role.AcceptedField))
+}
+
+func TestEvent_OwnerRoundTrip(t *testing.T) {
+	e := &Event{
+		Title:     "test",
+		Start:     time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC),
+		End:       time.Date(2023, 1, 1, 13, 0, 0, 0, time.UTC),
+		Owner:     "foo",
+		RoleGroup: role.NewDefaultRoleGroup(),
+	}
+	embed, err := ConvertEventToMessageEmbed(e)
+	assert.NoError(t, err)
+	assert.Equal(t, "Created by foo", embed.Footer.Text)
+
+	got, err := GetEventFromMessage(&discordgo.Message{Embeds: []*discordgo.MessageEmbed{embed}})
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", got.Owner)
+}
+
+func TestEvent_SignupsRoundTrip(t *testing.T) {
+	e := &Event{
+		Title:     "test",
+		Start:     time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC),
+		Owner:     "foo",
+		RoleGroup: role.NewDefaultRoleGroup(),
+		Deadline:  time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC),
+		Locked:    true,
+		Archived:  true,
+	}
+	embed, err := ConvertEventToMessageEmbed(e)
+	assert.NoError(t, err)
+
+	got, err := GetEventFromMessage(&discordgo.Message{Embeds: []*discordgo.MessageEmbed{embed}})
+	assert.NoError(t, err)
+	assert.True(t, e.Deadline.Equal(got.Deadline))
+	assert.True(t, got.Locked)
+	assert.True(t, got.Archived)
+}
+
+func TestEvent_CalendarLinksRoundTrip(t *testing.T) {
+	cases := []struct {
+		name             string
+		id               string
+		guildID          string
+		scheduledEventID string
+	}{
+		{
+			name:             "calendar and scheduled event",
+			id:               "abc",
+			guildID:          "123",
+			scheduledEventID: "456",
+		},
+		{
+			name:             "scheduled event without calendar",
+			guildID:          "123",
+			scheduledEventID: "456",
+		},
+		{
+			name: "calendar without scheduled event",
+			id:   "abc",
+		},
+		{
+			name: "neither",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := &Event{
+				ID:               tc.id,
+				Title:            "test",
+				Start:            time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC),
+				Owner:            "foo",
+				RoleGroup:        role.NewDefaultRoleGroup(),
+				GuildID:          tc.guildID,
+				ScheduledEventID: tc.scheduledEventID,
+			}
+			embed, err := ConvertEventToMessageEmbed(e)
+			assert.NoError(t, err)
+
+			got, err := GetEventFromMessage(&discordgo.Message{Embeds: []*discordgo.MessageEmbed{embed}})
+			assert.NoError(t, err)
+			assert.Equal(t, tc.id, got.ID)
+			assert.Equal(t, tc.guildID, got.GuildID)
+			assert.Equal(t, tc.scheduledEventID, got.ScheduledEventID)
+		})
+	}
+}
+
+func TestEvent_GuestsRoundTrip(t *testing.T) {
+	e := &Event{
+		Title:     "test",
+		Start:     time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC),
+		Owner:     "foo",
+		RoleGroup: role.NewDefaultRoleGroup(),
+		MaxGuests: 2,
+	}
+	e.RoleGroup.SetLimit(role.AcceptedField, 4)
+	assert.NoError(t, e.RoleGroup.ToggleRole(role.AcceptedField, "alice"))
+	_, err := e.SetGuests("alice", 2)
+	assert.NoError(t, err)
+	assert.NoError(t, e.RoleGroup.ToggleRole(role.AcceptedField, "bob"))
+	assert.NoError(t, e.RoleGroup.ToggleRole(role.AcceptedField, "carol"))
+	_, err = e.SetGuests("carol", 1)
+	assert.NoError(t, err)
+
+	embed, err := ConvertEventToMessageEmbed(e)
+	assert.NoError(t, err)
+	var accepted *discordgo.MessageEmbedField
+	for _, f := range embed.Fields {
+		if strings.Contains(f.Name, AcceptedBase) {
+			accepted = f
+		}
+	}
+	assert.Equal(t, "✅ Accepted (4/4)", accepted.Name)
+	assert.Equal(t, "> alice (+2)\n> bob", accepted.Value)
+
+	got, err := GetEventFromMessage(&discordgo.Message{Embeds: []*discordgo.MessageEmbed{embed}})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, got.MaxGuests)
+	assert.Equal(t, map[string]int{"alice": 2, "carol": 1}, got.RoleGroup.Guests)
+	assert.Equal(t, []string{"alice", "bob"}, got.RoleGroup.GetUsers(role.AcceptedField))
+	assert.Equal(t, 4, got.RoleGroup.Roles[0].Count)
+	assert.Equal(t, 2, got.RoleGroup.Waitlist[role.AcceptedField].Count)
+
+	_, err = got.SetGuests("bob", 3)
+	assert.Error(t, err)
+}
+
+func TestEvent_Ended(t *testing.T) {
+	start := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name     string
+		event    Event
+		now      time.Time
+		expected bool
+	}{
+		{
+			name:  "in progress",
+			event: Event{Start: start, End: start.Add(time.Hour)},
+			now:   start.Add(time.Minute),
+		},
+		{
+			name:     "after end",
+			event:    Event{Start: start, End: start.Add(time.Hour)},
+			now:      start.Add(time.Hour),
+			expected: true,
+		},
+		{
+			name:     "no duration",
+			event:    Event{Start: start},
+			now:      start.Add(time.Minute),
+			expected: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.event.Ended(tc.now))
+		})
+	}
+}
+
+func TestDisabledEventComponents(t *testing.T) {
+	rows := DisabledEventComponents(false)
+	assert.Len(t, rows, len(EventComponents))
+	for _, r := range rows {
+		for _, c := range r.(discordgo.ActionsRow).Components {
+			b := c.(discordgo.Button)
+			assert.Equal(t, b.CustomID != EditButton.CustomID, b.Disabled, b.CustomID)
+		}
+	}
+	assert.False(t, AcceptButton.Disabled)
+}
+
+func TestEventButtons(t *testing.T) {
+	labels := func(rows []discordgo.MessageComponent) []string {
+		var result []string
+		for _, c := range rows[0].(discordgo.ActionsRow).Components {
+			result = append(result, c.(discordgo.Button).Label)
+		}
+		return result
+	}
+	assert.Equal(t, []string{"✅", "❌", "❔", "Edit", "Delete"}, labels(EventButtons(false)))
+	assert.Equal(t, []string{"Accept", "Decline", "Maybe", "Edit", "Delete"}, labels(EventButtons(true)))
+	assert.Equal(t, []string{"Accept", "Decline", "Maybe", "Edit", "Delete"}, labels(DisabledEventComponents(true)))
+	assert.Equal(t, "✅", AcceptButton.Label)
+}
+
+func TestEvent_SignupsClosed(t *testing.T) {
+	deadline := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name     string
+		event    Event
+		now      time.Time
+		expected bool
+	}{
+		{
+			name:  "no deadline",
+			event: Event{},
+			now:   deadline,
+		},
+		{
+			name:  "before deadline",
+			event: Event{Deadline: deadline},
+			now:   deadline.Add(-time.Minute),
+		},
+		{
+			name:     "after deadline",
+			event:    Event{Deadline: deadline},
+			now:      deadline.Add(time.Minute),
+			expected: true,
+		},
+		{
+			name:     "locked",
+			event:    Event{Locked: true},
+			now:      deadline,
+			expected: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.event.SignupsClosed(tc.now))
+		})
+	}
+}
+
 func TestEvent_PromoteFromWaitlist(t *testing.T) {
 	e := Event{}
 	cases := []struct {
@@ -378,6 +626,38 @@ func TestGetEventFromMessage(t *testing.T) {
 				ID:    "MnZwYWUzNDdrMmE3MGdiaG5tZ212ZTlmbGwgczhsc3I3b2hicWk1dTUyYjg5dm12bXExYWtAZw",
 			},
 		},
+		{
+			name: "scheduled event",
+			input: &discordgo.Message{
+				Embeds: []*discordgo.MessageEmbed{
+					{
+						Title: "title",
+						Fields: []*discordgo.MessageEmbedField{
+							{
+								Name:  "Calendar",
+								Value: util.PrintGoogleCalendarEventLink("MnZwYWUzNDdrMmE3MGdiaG5tZ212ZTlmbGwgczhsc3I3b2hicWk1dTUyYjg5dm12bXExYWtAZw") + "\n" + util.PrintScheduledEventLink("123", "456"),
+							},
+						},
+					},
+				},
+			},
+			expected: &Event{
+				Title: "title",
+				RoleGroup: &role.RoleGroup{
+					Roles: []*role.Role{},
+					Waitlist: map[role.FieldType]*role.Role{
+						role.AcceptedField: {
+							Icon:      "",
+							FieldName: role.WaitlistField,
+							Users:     []string{},
+						},
+					},
+				},
+				ID:               "MnZwYWUzNDdrMmE3MGdiaG5tZ212ZTlmbGwgczhsc3I3b2hicWk1dTUyYjg5dm12bXExYWtAZw",
+				GuildID:          "123",
+				ScheduledEventID: "456",
+			},
+		},
 	}
 
 	for _, tc := range cases {
@@ -504,3 +784,102 @@ func TestEvent_SetDuration(t *testing.T) {
 		})
 	}
 }
+
+func TestFromGuildScheduledEvent(t *testing.T) {
+	start := time.Date(2022, 1, 1, 20, 0, 0, 0, time.UTC)
+	end := start.Add(2 * time.Hour)
+	cases := []struct {
+		name     string
+		input    *discordgo.GuildScheduledEvent
+		expected *Event
+	}{
+		{
+			name: "external",
+			input: &discordgo.GuildScheduledEvent{
+				ID:                 "456",
+				GuildID:            "123",
+				Name:               "title",
+				Description:        "desc\n\nhttps://discord.com/channels/1/2/3",
+				ScheduledStartTime: start,
+				ScheduledEndTime:   &end,
+				EntityMetadata:     discordgo.GuildScheduledEventEntityMetadata{Location: "Seattle"},
+				Creator:            &discordgo.User{Username: "test"},
+			},
+			expected: &Event{
+				Title:            "title",
+				Description:      "desc",
+				Location:         "Seattle",
+				Start:            start,
+				End:              end,
+				RoleGroup:        role.NewDefaultRoleGroup(),
+				Owner:            "test",
+				Color:            Purple,
+				GuildID:          "123",
+				ScheduledEventID: "456",
+			},
+		},
+		{
+			name: "voice channel",
+			input: &discordgo.GuildScheduledEvent{
+				ID:                 "456",
+				GuildID:            "123",
+				ChannelID:          "789",
+				Name:               "title",
+				ScheduledStartTime: start,
+			},
+			expected: &Event{
+				Title:            "title",
+				Location:         "<#789>",
+				Start:            start,
+				RoleGroup:        role.NewDefaultRoleGroup(),
+				Color:            Purple,
+				GuildID:          "123",
+				ScheduledEventID: "456",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, FromGuildScheduledEvent(tc.input))
+		})
+	}
+}
+
+func TestEvent_SyncFromGuildScheduledEvent(t *testing.T) {
+	start := time.Date(2022, 1, 1, 20, 0, 0, 0, time.UTC)
+	guildEvent := &discordgo.GuildScheduledEvent{
+		Name:               "title",
+		Description:        "https://discord.com/channels/1/2/3",
+		ScheduledStartTime: start,
+		EntityMetadata:     discordgo.GuildScheduledEventEntityMetadata{Location: "Seattle"},
+	}
+
+	e := &Event{Title: "title", Description: "desc", Location: "Seattle", Start: start, End: start}
+	assert.False(t, e.SyncFromGuildScheduledEvent(guildEvent))
+	assert.Equal(t, "desc", e.Description)
+
+	guildEvent.ScheduledStartTime = start.Add(time.Hour)
+	assert.True(t, e.SyncFromGuildScheduledEvent(guildEvent))
+	assert.Equal(t, start.Add(time.Hour), e.Start)
+
+	// A long description cut short by the bot is echoed back unchanged
+	e.Description = strings.Repeat("a", 1200)
+	e.DiscordLink = "https://discord.com/channels/1/2/3"
+	guildEvent.Description = ToGuildScheduledEventParams(e).Description
+	assert.False(t, e.SyncFromGuildScheduledEvent(guildEvent))
+	assert.Len(t, e.Description, 1200)
+
+	guildEvent.Description = "new\n\nhttps://discord.com/channels/1/2/3"
+	assert.True(t, e.SyncFromGuildScheduledEvent(guildEvent))
+	assert.Equal(t, "new", e.Description)
+}
+
+func TestToGuildScheduledEventParams(t *testing.T) {
+	e := &Event{Title: "title", Description: "Bring shoes", DiscordLink: "https://discord.com/channels/1/2/3"}
+	params := ToGuildScheduledEventParams(e)
+	assert.Equal(t, "Bring shoes\n\nhttps://discord.com/channels/1/2/3", params.Description)
+
+	e.Description = strings.Repeat("é", 1200)
+	assert.Equal(t, GuildEventDescriptionLimit, utf8.RuneCountInString(ToGuildScheduledEventParams(e).Description))
+}
@@ -2,19 +2,82 @@ package discord
 
 import (
 	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/mock"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/store"
 	"github.com/bwmarrin/discordgo"
 	"github.com/ewohltman/discordgo-mock/mockchannel"
 	"github.com/ewohltman/discordgo-mock/mockconstants"
+	"strings"
+	"time"
 )
 
 type Options struct {
 	Session           *discordgo.Session
 	InteractionCreate *discordgo.InteractionCreate
 	Channel           *discordgo.Channel
+	Store             *store.Store
+	// LateCancelAlerts is how close to the start organizers are told about members being removed
+	LateCancelAlerts time.Duration
+	// EventThreads attaches a discussion thread to each new event message
+	EventThreads bool
+	// ChannelID is the channel chosen for a new event with the command, if any
+	ChannelID string
+	// EventsChannelID is where new events are posted when no channel was chosen
+	EventsChannelID string
+	// CategoryChannels routes new events with a category to a channel
+	CategoryChannels map[string]string
+	// Categories can be chosen for an event
+	Categories []Category
+	// TextButtons labels the signup buttons of new event posts in words instead of emoji
+	TextButtons bool
+	// Printer renders prompts in the language and format the user prefers
+	Printer *Printer
+	// UserPrinter renders messages to other members in the language and format they prefer
+	UserPrinter PrinterFunc
+	// Timeouts are how long prompts wait for an answer
+	Timeouts Timeouts
+	// LockEvent keeps signups from changing an event message while an edit is saved and returns a func to release it. A
+	// nil LockEvent does not lock.
+	LockEvent func(messageID string) func()
 
 	*CalendarClient
 }
 
+// EventChannel is the channel a new event is posted to. A channel chosen with the command comes first, then the
+// channel for the event's category and the events channel. Otherwise, it is the channel the command was used in.
+func (o *Options) EventChannel(category string) string {
+	if o.ChannelID != "" {
+		return o.ChannelID
+	}
+	if channelID := o.CategoryChannels[strings.ToLower(category)]; channelID != "" {
+		return channelID
+	}
+	if o.EventsChannelID != "" {
+		return o.EventsChannelID
+	}
+	return o.InteractionCreate.Interaction.ChannelID
+}
+
+// Timeouts are how long prompts wait for an answer before the DM sequence stops
+type Timeouts struct {
+	// Default is the least time a prompt waits. Prompts for long text such as descriptions may wait longer.
+	Default time.Duration
+	// States sets the wait of single prompts by state name, which takes precedence over Default
+	States map[string]time.Duration
+	// Reminder is how long before a prompt times out the user is nudged to answer. 0 disables it.
+	Reminder time.Duration
+}
+
+// Wait is how long the prompt of a state waits, given how long the prompt itself asks for
+func (t Timeouts) Wait(state string, wait time.Duration) time.Duration {
+	if d := t.States[state]; d > 0 {
+		return d
+	}
+	if t.Default > wait {
+		return t.Default
+	}
+	return wait
+}
+
 // NewMockOptions returns a mocked Discord user session
 func NewMockOptions() (*Options, error) {
 	session, err := mock.NewSession()
@@ -0,0 +1,88 @@
+package discord
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestOptions_EventChannel(t *testing.T) {
+	cases := []struct {
+		name     string
+		opts     Options
+		category string
+		expected string
+	}{
+		{
+			name:     "chosen channel",
+			opts:     Options{ChannelID: "1", EventsChannelID: "2", CategoryChannels: map[string]string{"workshop": "3"}},
+			category: "workshop",
+			expected: "1",
+		},
+		{
+			name:     "category",
+			opts:     Options{EventsChannelID: "2", CategoryChannels: map[string]string{"workshop": "3"}},
+			category: "Workshop",
+			expected: "3",
+		},
+		{
+			name:     "events channel",
+			opts:     Options{EventsChannelID: "2", CategoryChannels: map[string]string{"workshop": "3"}},
+			category: "social",
+			expected: "2",
+		},
+		{
+			name:     "command channel",
+			expected: "4",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.opts.InteractionCreate = &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{ChannelID: "4"}}
+			assert.Equal(t, tc.expected, tc.opts.EventChannel(tc.category))
+		})
+	}
+}
+
+func TestTimeouts_Wait(t *testing.T) {
+	cases := []struct {
+		name     string
+		timeouts Timeouts
+		state    string
+		wait     time.Duration
+		expected time.Duration
+	}{
+		{
+			name:     "prompt wait",
+			wait:     time.Minute,
+			expected: time.Minute,
+		},
+		{
+			name:     "longer default",
+			timeouts: Timeouts{Default: 2 * time.Minute},
+			wait:     time.Minute,
+			expected: 2 * time.Minute,
+		},
+		{
+			name:     "long prompt",
+			timeouts: Timeouts{Default: 2 * time.Minute},
+			wait:     10 * time.Minute,
+			expected: 10 * time.Minute,
+		},
+		{
+			name:     "state",
+			timeouts: Timeouts{Default: 2 * time.Minute, States: map[string]time.Duration{"addDescription": 30 * time.Second}},
+			state:    "addDescription",
+			wait:     10 * time.Minute,
+			expected: 30 * time.Second,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.timeouts.Wait(tc.state, tc.wait))
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package discord
+
+import (
+	"fmt"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/role"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/i18n"
+	"github.com/bwmarrin/discordgo"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AccessibleTimeFormat spells out a time instead of relying on Discord to render a timestamp
+const AccessibleTimeFormat = "Monday, January 2, 2006 at 3:04 PM MST"
+
+var (
+	menuNumberRegex = regexp.MustCompile(`(?m)^\*\*(\d+)\*\*[⠀ ]*`)
+	blockquoteRegex = regexp.MustCompile(`(?m)^>(>>)? ?`)
+	timestampRegex  = regexp.MustCompile(`<t:(-?\d+)(?::([tTdDfFR]))?>`)
+
+	// symbolReplacer swaps the symbols the bot uses in lists for words a screen reader can say
+	symbolReplacer = strings.NewReplacer(
+		"⠀", " ",
+		"🕔", "",
+		" ⋅ ", ", ",
+		"☑️", "(checked in)",
+		"⬜", "(not checked in)",
+		AcceptedBase, string(role.AcceptedField),
+		DeclinedBase, string(role.DeclinedField),
+		TentativeBase, string(role.TentativeField),
+		role.AcceptedIcon+" ", string(role.AcceptedField)+": ",
+		role.DeclinedIcon+" ", string(role.DeclinedField)+": ",
+		role.TentativeIcon+" ", string(role.TentativeField)+": ",
+	)
+)
+
+// Printer renders messages for one user in their language. In accessibility mode, it also swaps emoji, special
+// spacing and Discord timestamps for plain text. A nil Printer leaves messages as is.
+type Printer struct {
+	Localizer  *i18n.Localizer
+	Accessible bool
+	// Now is used for relative times and defaults to the current time
+	Now func() time.Time
+}
+
+// PrinterFunc gets the printer for a member messaged outside their own interactions, such as about a change someone
+// else made. An empty user ID gets the printer for messages posted in the server. A nil PrinterFunc leaves messages as
+// is.
+type PrinterFunc func(userID string) *Printer
+
+// For gets the printer for a member
+func (f PrinterFunc) For(userID string) *Printer {
+	if f == nil {
+		return nil
+	}
+	return f(userID)
+}
+
+// T translates text
+func (p *Printer) T(text string) string {
+	if p == nil {
+		return text
+	}
+	return p.plain(p.Localizer.T(text))
+}
+
+// Tf translates a format string before formatting it
+func (p *Printer) Tf(format string, a ...any) string {
+	if p == nil {
+		return fmt.Sprintf(format, a...)
+	}
+	return p.plain(p.Localizer.Tf(format, a...))
+}
+
+// Embed returns a copy of an embed ready to be sent to the user
+func (p *Printer) Embed(e *discordgo.MessageEmbed) *discordgo.MessageEmbed {
+	if p == nil {
+		return e
+	}
+	c := p.Localizer.Embed(e)
+	if !p.Accessible {
+		return c
+	}
+	c.Title = p.plain(c.Title)
+	c.Description = p.plain(c.Description)
+	if c.Footer != nil {
+		c.Footer.Text = p.plain(c.Footer.Text)
+	}
+	for _, f := range c.Fields {
+		f.Name = p.plain(f.Name)
+		f.Value = p.plain(f.Value)
+	}
+	return c
+}
+
+// Keyword converts a localized keyword into its English form
+func (p *Printer) Keyword(input string) string {
+	if p == nil {
+		return input
+	}
+	return p.Localizer.Keyword(input)
+}
+
+// plain makes text screen reader friendly in accessibility mode. Menus are numbered as "1." instead of a bold number,
+// quoted lists become plain lines and timestamps are spelled out.
+func (p *Printer) plain(text string) string {
+	if !p.Accessible || text == "" {
+		return text
+	}
+	text = menuNumberRegex.ReplaceAllString(text, "$1. ")
+	text = blockquoteRegex.ReplaceAllString(text, "")
+	text = timestampRegex.ReplaceAllStringFunc(text, p.spellTimestamp)
+	return strings.TrimSpace(symbolReplacer.Replace(text))
+}
+
+// spellTimestamp prints a Discord timestamp such as <t:1700000000:R> in words
+func (p *Printer) spellTimestamp(timestamp string) string {
+	match := timestampRegex.FindStringSubmatch(timestamp)
+	unix, err := strconv.ParseInt(match[1], 10, 64)
+	if err != nil {
+		return timestamp
+	}
+	t := time.Unix(unix, 0).In(time.Local)
+	switch match[2] {
+	case "t":
+		return t.Format(time.Kitchen)
+	case "R":
+		return p.relative(t)
+	default:
+		return t.Format(AccessibleTimeFormat)
+	}
+}
+
+// relative prints how long until or since a time, such as "in 3 hours"
+func (p *Printer) relative(t time.Time) string {
+	now := time.Now()
+	if p.Now != nil {
+		now = p.Now()
+	}
+	d := t.Sub(now)
+	format := "in %s"
+	if d < 0 {
+		d, format = -d, "%s ago"
+	}
+	var amount string
+	switch {
+	case d < time.Minute:
+		return p.Localizer.T("now")
+	case d < time.Hour:
+		amount = p.count(int(d/time.Minute), "%d minute", "%d minutes")
+	case d < 24*time.Hour:
+		amount = p.count(int(d/time.Hour), "%d hour", "%d hours")
+	default:
+		amount = p.count(int(d/(24*time.Hour)), "%d day", "%d days")
+	}
+	return p.Localizer.Tf(format, amount)
+}
+
+func (p *Printer) count(n int, singular, plural string) string {
+	if n == 1 {
+		return p.Localizer.Tf(singular, n)
+	}
+	return p.Localizer.Tf(plural, n)
+}
@@ -0,0 +1,125 @@
+package discord
+
+import (
+	"fmt"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/i18n"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestPrinter_T(t *testing.T) {
+	now := time.Date(2023, 6, 10, 18, 0, 0, 0, time.Local)
+	fixed := func() time.Time { return now }
+	start := now.Add(3 * time.Hour)
+
+	cases := []struct {
+		name     string
+		printer  *Printer
+		text     string
+		expected string
+	}{
+		{
+			name:     "nil",
+			text:     "**1**⠀✅ foo",
+			expected: "**1**⠀✅ foo",
+		},
+		{
+			name:     "not accessible",
+			printer:  &Printer{},
+			text:     "**1** Modify the event",
+			expected: "**1** Modify the event",
+		},
+		{
+			name:     "numbered menu",
+			printer:  &Printer{Accessible: true},
+			text:     "**1** Modify the event\n**2** Remove responses",
+			expected: "1. Modify the event\n2. Remove responses",
+		},
+		{
+			name:     "response list",
+			printer:  &Printer{Accessible: true},
+			text:     "**1**⠀✅ foo\n**2**⠀❔ bar",
+			expected: "1. Accepted: foo\n2. Tentative: bar",
+		},
+		{
+			name:     "signup options",
+			printer:  &Printer{Accessible: true},
+			text:     fmt.Sprintf("**1**⠀%s\n**2**⠀%s", AcceptedBase, DeclinedBase),
+			expected: "1. Accepted\n2. Declined",
+		},
+		{
+			name:     "check ins",
+			printer:  &Printer{Accessible: true},
+			text:     "**1**⠀☑️ foo\n**2**⠀⬜ bar",
+			expected: "1. (checked in) foo\n2. (not checked in) bar",
+		},
+		{
+			name:     "quoted list",
+			printer:  &Printer{Accessible: true},
+			text:     "> foo\n> bar",
+			expected: "foo\nbar",
+		},
+		{
+			name:     "timestamps",
+			printer:  &Printer{Accessible: true, Now: fixed},
+			text:     fmt.Sprintf("<t:%d:F> - <t:%d:t>\n🕔<t:%d:R>", start.Unix(), start.Add(time.Hour).Unix(), start.Unix()),
+			expected: fmt.Sprintf("%s - 10:00PM\nin 3 hours", start.Format(AccessibleTimeFormat)),
+		},
+		{
+			name:     "past",
+			printer:  &Printer{Accessible: true, Now: fixed},
+			text:     fmt.Sprintf("<t:%d:R>", now.Add(-25*time.Hour).Unix()),
+			expected: "1 day ago",
+		},
+		{
+			name:     "translated",
+			printer:  &Printer{Accessible: true, Now: fixed, Localizer: i18n.NewLocalizer(discordgo.SpanishES)},
+			text:     fmt.Sprintf("<t:%d:R>", now.Add(90*time.Minute).Unix()),
+			expected: "dentro de 1 hora",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.printer.T(tc.text))
+		})
+	}
+}
+
+func TestPrinter_Embed(t *testing.T) {
+	original := &discordgo.MessageEmbed{
+		Title:       "What would you like to do?",
+		Description: "**1** Modify the event",
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "1 ⋅ Title", Value: "> foo"},
+		},
+		Footer: &discordgo.MessageEmbedFooter{Text: OptionText},
+	}
+	embed := (&Printer{Accessible: true}).Embed(original)
+
+	assert.Equal(t, "1. Modify the event", embed.Description)
+	assert.Equal(t, "1, Title", embed.Fields[0].Name)
+	assert.Equal(t, "foo", embed.Fields[0].Value)
+	assert.Equal(t, OptionText, embed.Footer.Text)
+
+	assert.Equal(t, "**1** Modify the event", original.Description)
+	assert.Equal(t, "1 ⋅ Title", original.Fields[0].Name)
+	assert.Equal(t, "> foo", original.Fields[0].Value)
+}
+
+func TestPrinterFunc_For(t *testing.T) {
+	var none PrinterFunc
+	assert.Nil(t, none.For("1"))
+
+	es := &Printer{Localizer: i18n.NewLocalizer(discordgo.SpanishES)}
+	printers := PrinterFunc(func(userID string) *Printer {
+		if userID == "1" {
+			return es
+		}
+		return nil
+	})
+	assert.Equal(t, "Se te ha pasado a la lista de espera", printers.For("1").T("You have been moved to the waitlist"))
+	assert.Equal(t, "You have been moved to the waitlist", printers.For("2").T("You have been moved to the waitlist"))
+}
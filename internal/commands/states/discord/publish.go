@@ -0,0 +1,102 @@
+package discord
+
+import (
+	"fmt"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/logging"
+	"github.com/bwmarrin/discordgo"
+	"log/slog"
+)
+
+// Publish posts a new event to a channel, creates its calendar entry and guild scheduled event, and links them
+// together. The organizer is added to the event's thread when threads are turned on. A publish which fails part way is
+// undone, so it can be tried again without leaving a duplicate post or calendar entry behind.
+func (o *Options) Publish(event *Event, channelID, ownerID string) (err error) {
+	if err = o.CreateGoogleEvent(event); err != nil {
+		return err
+	}
+	var msg *discordgo.Message
+	defer func() {
+		if err != nil {
+			o.unpublish(event, channelID, msg)
+		}
+	}()
+
+	embed, err := ConvertEventToMessageEmbed(event)
+	if err != nil {
+		return err
+	}
+	msg, err = o.Session.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+		Embeds:     []*discordgo.MessageEmbed{embed},
+		Components: EventButtons(o.TextButtons),
+	})
+	if err != nil {
+		return err
+	}
+
+	event.DiscordLink = fmt.Sprintf("https://discord.com/channels/%s/%s/%s", event.GuildID, channelID, msg.ID)
+	if o.EventThreads {
+		// The event is still usable without a thread, such as when the bot cannot create threads in the channel
+		if err = StartThread(o.Session, channelID, msg.ID, ownerID, event); err != nil {
+			slog.Warn("cannot start event thread", logging.EventKey, event.DiscordLink, logging.ErrorKey, err)
+		}
+	}
+	if err = o.UpdateEvent(event); err != nil {
+		return err
+	}
+
+	guildEvent := ToGuildScheduledEventParams(event)
+	guildEvent.Status = discordgo.GuildScheduledEventStatusScheduled
+	scheduledEvent, err := o.Session.GuildScheduledEventCreate(event.GuildID, guildEvent)
+	if err != nil {
+		return err
+	}
+
+	// Link the scheduled event so later edits and deletes can find it by ID
+	event.ScheduledEventID = scheduledEvent.ID
+	return EditEventMessage(o.Session, channelID, msg.ID, event)
+}
+
+// unpublish removes what a failed publish left behind: the guild scheduled event, the thread, the post and the calendar
+// entry. Anything which cannot be removed is logged.
+func (o *Options) unpublish(event *Event, channelID string, msg *discordgo.Message) {
+	if event.ScheduledEventID != "" {
+		if err := o.Session.GuildScheduledEventDelete(event.GuildID, event.ScheduledEventID); err != nil {
+			slog.Warn("cannot delete guild event of failed post", logging.EventKey, event.DiscordLink, logging.ErrorKey, err)
+		}
+		event.ScheduledEventID = ""
+	}
+	if event.ThreadID != "" {
+		if _, err := o.Session.ChannelDelete(event.ThreadID); err != nil {
+			slog.Warn("cannot delete thread of failed post", logging.EventKey, event.DiscordLink, logging.ErrorKey, err)
+		}
+		event.ThreadID = ""
+	}
+	if msg != nil {
+		if err := o.Session.ChannelMessageDelete(channelID, msg.ID); err != nil {
+			slog.Warn("cannot delete failed post", logging.EventKey, event.DiscordLink, logging.ErrorKey, err)
+		}
+		event.DiscordLink = ""
+	}
+	if err := o.DeleteEvent(event); err != nil {
+		slog.Warn("cannot delete calendar entry of failed post", logging.EventKey, event.Title, logging.ErrorKey, err)
+	}
+	event.ID = ""
+}
+
+// Move posts an event to another channel as a new message with the buttons of its current message and links the event
+// to it. The current message is left for the caller to delete once everything else points to the new one.
+func (o *Options) Move(event *Event, current *discordgo.Message, guildID, channelID string) (*discordgo.Message, error) {
+	embed, err := ConvertEventToMessageEmbed(event)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := o.Session.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+		Embeds:     []*discordgo.MessageEmbed{embed},
+		Components: current.Components,
+	})
+	if err != nil {
+		return nil, err
+	}
+	event.DiscordLink = fmt.Sprintf("https://discord.com/channels/%s/%s/%s", guildID, channelID, msg.ID)
+	return msg, nil
+}
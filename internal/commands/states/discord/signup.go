@@ -0,0 +1,167 @@
+package discord
+
+import (
+	"fmt"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/role"
+	"github.com/bwmarrin/discordgo"
+	"strconv"
+	"strings"
+)
+
+// Component IDs carrying a payload are formatted as prefix:value:value
+const (
+	UndoPrefix     = "undo"
+	MyEventsPrefix = "my_events"
+	GuestsPrefix   = "guests"
+	idSeparator    = ":"
+)
+
+// ComponentPrefix returns the part of a custom ID used to route an interaction to its handler
+func ComponentPrefix(customID string) string {
+	prefix, _, _ := strings.Cut(customID, idSeparator)
+	return prefix
+}
+
+// RestoreResponse puts a user back to an earlier response and returns the users moved off the waitlist as a result, if
+// any. Users restored to a waitlist rejoin at the end.
+func (e *Event) RestoreResponse(name string, prev role.Response) ([]string, error) {
+	if e.RoleGroup == nil {
+		return nil, fmt.Errorf("missing role group")
+	}
+	cur := e.RoleGroup.GetResponse(name)
+	if cur.Field == prev.Field {
+		return nil, nil
+	}
+	waitlisted := e.RoleGroup.WaitlistUsers(role.AcceptedField)
+	// Toggling the current response clears it and promotes from the waitlist when a spot opens
+	if cur.Field != "" {
+		if err := e.RoleGroup.ToggleRole(cur.Field, name); err != nil {
+			return nil, err
+		}
+	}
+	if prev.Field != "" {
+		if err := e.RoleGroup.ToggleRole(prev.Field, name); err != nil {
+			return nil, err
+		}
+	}
+	return e.promotedFrom(waitlisted), nil
+}
+
+// SignupStatusText describes how a user's response changed after clicking a signup button
+func SignupStatusText(p *Printer, before, after role.Response) string {
+	switch {
+	case after.Waitlist > 0:
+		return p.Tf("You're #%d on the waitlist for %s", after.Waitlist, after.Field)
+	case after.Field != "" && before.Field != "" && before.Waitlist == 0 && before.Field != after.Field:
+		return p.Tf("You've moved from %s to %s", before.Field, after.Field)
+	case after.Field != "":
+		return p.Tf("You've been added to %s", after.Field)
+	case before.Waitlist > 0:
+		return p.Tf("You've been removed from the waitlist for %s", before.Field)
+	case before.Field != "":
+		return p.Tf("You've been removed from %s", before.Field)
+	default:
+		return p.T("Your response has not changed")
+	}
+}
+
+// SignupStatusMessage is the ephemeral reply to a signup click with buttons to undo it or list the user's events.
+// Members who accepted an event allowing guests can also choose how many they are bringing. The reason explains why a
+// signup rule placed the user on the waitlist, if one did.
+func SignupStatusMessage(p *Printer, channelID, messageID string, before, after role.Response, maxGuests int, reason string) *discordgo.WebhookParams {
+	params := &discordgo.WebhookParams{
+		Embeds: []*discordgo.MessageEmbed{
+			{
+				Title:       SignupStatusText(p, before, after),
+				Description: reason,
+				Color:       Purple,
+			},
+		},
+		Components: []discordgo.MessageComponent{
+			discordgo.ActionsRow{
+				Components: []discordgo.MessageComponent{
+					discordgo.Button{
+						Label:    p.T("Undo"),
+						Style:    discordgo.SecondaryButton,
+						CustomID: UndoID(channelID, messageID, before.Field),
+					},
+					discordgo.Button{
+						Label:    p.T("View my events"),
+						Style:    discordgo.SecondaryButton,
+						CustomID: MyEventsPrefix,
+					},
+				},
+			},
+		},
+		Flags: discordgo.MessageFlagsEphemeral,
+	}
+	if after.Field == role.AcceptedField && maxGuests > 0 {
+		params.Components = append([]discordgo.MessageComponent{GuestsMenu(p, channelID, messageID, maxGuests)}, params.Components...)
+	}
+	return params
+}
+
+// GuestsMenu is a select menu for choosing how many guests a member is bringing to an event
+func GuestsMenu(p *Printer, channelID, messageID string, maxGuests int) discordgo.ActionsRow {
+	options := []discordgo.SelectMenuOption{
+		{
+			Label: p.T("Just me"),
+			Value: "0",
+		},
+	}
+	for n := 1; n <= maxGuests; n++ {
+		options = append(options, discordgo.SelectMenuOption{
+			Label: p.Tf("Me +%d", n),
+			Value: strconv.Itoa(n),
+		})
+	}
+	return discordgo.ActionsRow{
+		Components: []discordgo.MessageComponent{
+			discordgo.SelectMenu{
+				CustomID:    GuestsID(channelID, messageID),
+				Placeholder: p.T("Bringing guests?"),
+				Options:     options,
+			},
+		},
+	}
+}
+
+// GuestsID is the custom ID of a select menu choosing the number of guests for an event
+func GuestsID(channelID, messageID string) string {
+	return strings.Join([]string{GuestsPrefix, channelID, messageID}, idSeparator)
+}
+
+// ParseGuestsID gets the event message from a guests select menu
+func ParseGuestsID(customID string) (channelID, messageID string, err error) {
+	parts := strings.Split(customID, idSeparator)
+	if len(parts) != 3 || parts[0] != GuestsPrefix || parts[1] == "" || parts[2] == "" {
+		return "", "", fmt.Errorf("invalid guests ID: %s", customID)
+	}
+	return parts[1], parts[2], nil
+}
+
+// GuestsStatusText describes how many guests a user is bringing
+func GuestsStatusText(p *Printer, guests int) string {
+	switch guests {
+	case 0:
+		return p.T("You're not bringing any guests")
+	case 1:
+		return p.T("You're bringing 1 guest")
+	default:
+		return p.Tf("You're bringing %d guests", guests)
+	}
+}
+
+// UndoID is the custom ID of a button restoring a user's previous response to an event
+func UndoID(channelID, messageID string, prev role.FieldType) string {
+	return strings.Join([]string{UndoPrefix, channelID, messageID, string(prev)}, idSeparator)
+}
+
+// ParseUndoID gets the event message and previous response from an undo button
+func ParseUndoID(customID string) (channelID, messageID string, prev role.FieldType, err error) {
+	parts := strings.Split(customID, idSeparator)
+	if len(parts) != 4 || parts[0] != UndoPrefix || parts[1] == "" || parts[2] == "" {
+		return "", "", "", fmt.Errorf("invalid undo ID: %s", customID)
+	}
+	return parts[1], parts[2], role.FieldType(parts[3]), nil
+}
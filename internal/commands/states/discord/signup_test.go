@@ -0,0 +1,128 @@
+package discord
+
+import (
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/role"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestComponentPrefix(t *testing.T) {
+	assert.Equal(t, "accept", ComponentPrefix("accept"))
+	assert.Equal(t, UndoPrefix, ComponentPrefix(UndoID("1", "2", role.AcceptedField)))
+}
+
+func TestParseUndoID(t *testing.T) {
+	cases := []struct {
+		name      string
+		input     string
+		channelID string
+		messageID string
+		prev      role.FieldType
+		isErr     bool
+	}{
+		{
+			name:      "previous response",
+			input:     UndoID("123", "456", role.DeclinedField),
+			channelID: "123",
+			messageID: "456",
+			prev:      role.DeclinedField,
+		},
+		{
+			name:      "no previous response",
+			input:     UndoID("123", "456", ""),
+			channelID: "123",
+			messageID: "456",
+		},
+		{
+			name:  "missing message",
+			input: "undo:123",
+			isErr: true,
+		},
+		{
+			name:  "wrong prefix",
+			input: "accept:123:456:",
+			isErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			channelID, messageID, prev, err := ParseUndoID(tc.input)
+			if tc.isErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.channelID, channelID)
+			assert.Equal(t, tc.messageID, messageID)
+			assert.Equal(t, tc.prev, prev)
+		})
+	}
+}
+
+func TestSignupStatusText(t *testing.T) {
+	cases := []struct {
+		name     string
+		before   role.Response
+		after    role.Response
+		expected string
+	}{
+		{
+			name:     "added",
+			after:    role.Response{Field: role.AcceptedField},
+			expected: "You've been added to Accepted",
+		},
+		{
+			name:     "waitlisted",
+			after:    role.Response{Field: role.AcceptedField, Waitlist: 3},
+			expected: "You're #3 on the waitlist for Accepted",
+		},
+		{
+			name:     "moved",
+			before:   role.Response{Field: role.DeclinedField},
+			after:    role.Response{Field: role.AcceptedField},
+			expected: "You've moved from Declined to Accepted",
+		},
+		{
+			name:     "removed",
+			before:   role.Response{Field: role.AcceptedField},
+			expected: "You've been removed from Accepted",
+		},
+		{
+			name:     "left waitlist",
+			before:   role.Response{Field: role.AcceptedField, Waitlist: 1},
+			expected: "You've been removed from the waitlist for Accepted",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, SignupStatusText(nil, tc.before, tc.after))
+		})
+	}
+}
+
+func TestEvent_RestoreResponse(t *testing.T) {
+	rg := role.NewDefaultRoleGroup()
+	rg.SetLimit(role.AcceptedField, 1)
+	e := &Event{RoleGroup: rg}
+	assert.NoError(t, rg.ToggleRole(role.DeclinedField, "foo"))
+	assert.NoError(t, rg.ToggleRole(role.AcceptedField, "bar"))
+	before := rg.GetResponse("foo")
+
+	// foo joins the waitlist then undoes it
+	assert.NoError(t, rg.ToggleRole(role.AcceptedField, "foo"))
+	assert.Equal(t, role.Response{Field: role.AcceptedField, Waitlist: 1}, rg.GetResponse("foo"))
+
+	promoted, err := e.RestoreResponse("foo", before)
+	assert.NoError(t, err)
+	assert.Empty(t, promoted)
+	assert.Equal(t, before, rg.GetResponse("foo"))
+
+	// bar's spot goes to the waitlist when bar undoes accepting
+	assert.NoError(t, rg.ToggleRole(role.AcceptedField, "baz"))
+	promoted, err = e.RestoreResponse("bar", role.Response{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"baz"}, promoted)
+	assert.Equal(t, role.Response{}, rg.GetResponse("bar"))
+}
@@ -1,8 +1,9 @@
 package discord
 
 import (
-	"fmt"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/pkg/util"
 	"github.com/bwmarrin/discordgo"
+	"time"
 )
 
 var (
@@ -36,21 +37,100 @@ var (
 		Style:    discordgo.DangerButton,
 		CustomID: "delete",
 	}
+	LockButton = discordgo.Button{
+		Label:    "🔒 Lock / Unlock signups",
+		Style:    discordgo.SecondaryButton,
+		CustomID: "lock",
+	}
+	EventActionsRow = discordgo.ActionsRow{
+		Components: []discordgo.MessageComponent{
+			AcceptButton,
+			DeclineButton,
+			TentativeButton,
+			EditButton,
+			DeleteButton,
+		},
+	}
+	// EventManageRow holds organizer actions which do not fit in EventActionsRow
+	EventManageRow = discordgo.ActionsRow{
+		Components: []discordgo.MessageComponent{
+			LockButton,
+		},
+	}
+	EventComponents = []discordgo.MessageComponent{EventActionsRow, EventManageRow}
 )
 
+// buttonWords name the signup buttons for servers which prefer words over emoji, as screen readers announce them
+// more clearly
+var buttonWords = map[string]string{
+	AcceptButton.CustomID:    "Accept",
+	DeclineButton.CustomID:   "Decline",
+	TentativeButton.CustomID: "Maybe",
+}
+
+// EventButtons returns the buttons of an event post, with the signup buttons labeled in words when textLabels is set
+func EventButtons(textLabels bool) []discordgo.MessageComponent {
+	if !textLabels {
+		return EventComponents
+	}
+	return mapButtons(EventComponents, func(b discordgo.Button) discordgo.Button {
+		if label, ok := buttonWords[b.CustomID]; ok {
+			b.Label = label
+		}
+		return b
+	})
+}
+
+// DisabledEventComponents returns the event buttons greyed out for events which have ended. Edit stays enabled so
+// organizers can take attendance afterwards.
+func DisabledEventComponents(textLabels bool) []discordgo.MessageComponent {
+	return mapButtons(EventButtons(textLabels), func(b discordgo.Button) discordgo.Button {
+		b.Disabled = b.CustomID != EditButton.CustomID
+		return b
+	})
+}
+
+// mapButtons copies rows of buttons with a change applied to each button
+func mapButtons(components []discordgo.MessageComponent, apply func(b discordgo.Button) discordgo.Button) []discordgo.MessageComponent {
+	var rows []discordgo.MessageComponent
+	for _, c := range components {
+		row := c.(discordgo.ActionsRow)
+		buttons := make([]discordgo.MessageComponent, 0, len(row.Components))
+		for _, b := range row.Components {
+			buttons = append(buttons, apply(b.(discordgo.Button)))
+		}
+		rows = append(rows, discordgo.ActionsRow{Components: buttons})
+	}
+	return rows
+}
+
 // Discord Static Responses
 var (
-	CancelText                = "To exit, type 'cancel'"
+	CancelText                = "To go back a step, type 'back'. To exit, type 'cancel'"
+	NothingToGoBackText       = "There's no step to go back to. Answer the question above or type 'cancel' to exit."
+	CannotSkipText            = "This question can't be skipped. Answer it or type 'cancel' to exit."
+	ReminderText              = "Still there? I'll stop waiting for an answer <t:%d:R>."
 	InvalidEventLimitText     = "Entry must be between 1 and 250 (or `None` for no limit). Try again:"
 	InvalidEntryText          = "Invalid entry. Please select a number from the list above."
 	InvalidStartTimeText      = "Invalid start time. Try again:"
 	InvalidEventTimeText      = "Event start time cannot be in the past. Try again:"
 	InvalidDurationText       = "That's not a valid duration. Try again:"
+	InvalidCheckInText        = "Invalid selection. Enter the number(s) of the attendees to check in or out, separated by spaces, or `None`."
+	InvalidDeadlineText       = "The deadline must be after now and before the event starts. Try again:"
+	InvalidPublishTimeText    = "The post must go out after now and before the event starts. Try again:"
+	InvalidGuestsText         = "Entry must be between 0 and 10 (or `None` for no guests). Try again:"
+	InvalidCategoryText       = "Enter the number of a category (or `None` for no category). Try again:"
+	InvalidRecipientsText     = "Invalid selection. Enter the number(s) of who should get the message, separated by spaces."
+	InvalidAnnouncementText   = "Messages must be between 1 and 1500 characters. Try again:"
+	InvalidChannelText        = "Mention a channel I can post in, such as #events. Try again:"
+	InvalidOwnerText          = "We couldn't find a member with that username. Try again:"
 	InvalidRemoveResponseText = "Invalid selection. Enter the number(s) of the desired option(s), separated by spaces. \n\nFor example: `1 3 5`"
 	FoundMultipleText         = "We've found more than one user for the search term. Try something more specific:"
 	// FoundNoneText             = "We couldn't find a user with that name. Try again:"
 	UserSignedUpText = "That user is already signed up for this event."
+	EndedText        = "Event ended"
 	OptionText       = "Enter a number to select an option"
+	CheckInText      = "Enter the number(s) of the attendees to check in or out, separated by spaces. Type None if nobody else showed up"
 
 	EnterTitleMessage = discordgo.MessageEmbed{
 		Title:       "Enter the event title",
@@ -63,7 +143,7 @@ var (
 	EnterDescriptionMessage = discordgo.MessageEmbed{
 		Title:       "Enter the event description",
 		Color:       Purple,
-		Description: "Type `None` for no description. Up to 1600 characters are permitted",
+		Description: "Type `Skip` for no description. Up to 1600 characters are permitted",
 		Footer: &discordgo.MessageEmbedFooter{
 			Text: CancelText,
 		},
@@ -79,11 +159,9 @@ var (
 	}
 
 	EnterDateStartMessage = discordgo.MessageEmbed{
-		Title: "When should the event start",
-		Color: Purple,
-		// TODO: Support various time input formats
-		// Description: "> Friday at 9pm\n> Tomorrow at 18:00\n> Now\n> In 1 hour\n> YYYY-MM-DD 7:00 PM",
-		Description: "> tomorrow at 10:15am\n> now\n> YYYY-MM-DD 7:00 PM",
+		Title:       "When should the event start",
+		Color:       Purple,
+		Description: "Add an end time to skip the duration step.\n> Friday at 9pm\n> Sat 8pm-midnight\n> tomorrow 7 until 10pm\n> in 1 hour\n> now\n> YYYY-MM-DD 7:00 PM",
 		Footer: &discordgo.MessageEmbedFooter{
 			Text: CancelText,
 		},
@@ -92,7 +170,43 @@ var (
 	EnterDurationMessage = discordgo.MessageEmbed{
 		Title:       "What is the duration of this event?",
 		Color:       Purple,
-		Description: "Type `None` for no duration.\n> 2 hours\n> 45 minutes\n> 1 hour and 30 minutes",
+		Description: "Type `None` for no duration.\n> 2 hours\n> 1 hour and 30 minutes\n> until 11pm",
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: CancelText,
+		},
+	}
+
+	EnterDeadlineMessage = discordgo.MessageEmbed{
+		Title:       "When should signups close?",
+		Color:       Purple,
+		Description: "Type `None` to keep signups open.\n> 2 hours before\n> 1 day before\n> friday at 5pm",
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: CancelText,
+		},
+	}
+
+	EnterGuestsMessage = discordgo.MessageEmbed{
+		Title:       "How many guests can each member bring?",
+		Color:       Purple,
+		Description: "Type `None` to not allow guests. Up to 10 guests are permitted. Guests take up spots in the event",
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: CancelText,
+		},
+	}
+
+	EnterRecipientsMessage = discordgo.MessageEmbed{
+		Title:       "Who should get the message?",
+		Color:       Purple,
+		Description: "**1** Accepted\n**2** Tentative\n**3** Waitlist",
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "Enter the number(s) of the desired option(s), separated by spaces\n" + CancelText,
+		},
+	}
+
+	EnterAnnouncementMessage = discordgo.MessageEmbed{
+		Title:       "What would you like to tell them?",
+		Color:       Purple,
+		Description: "Members are sent a DM. Anyone with DMs closed is mentioned in the event channel instead. Up to 1500 characters are permitted",
 		Footer: &discordgo.MessageEmbedFooter{
 			Text: CancelText,
 		},
@@ -106,16 +220,45 @@ var (
 		},
 	}
 
+	EnterChannelMessage = discordgo.MessageEmbed{
+		Title:       "Which channel should the event be posted in?",
+		Color:       Purple,
+		Description: "Mention the channel, such as #events. The post moves there and keeps its signups",
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: CancelText,
+		},
+	}
+
+	EnterOwnerMessage = discordgo.MessageEmbed{
+		Title:       "Who should organize this event?",
+		Color:       Purple,
+		Description: "Enter their exact username. The new organizer can edit the event",
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: CancelText,
+		},
+	}
+
 	CommandInProcessMessage = discordgo.MessageEmbed{
 		Title:       "You have another command in process",
 		Color:       Purple,
 		Description: "Check your direct messages with me",
 	}
 
+	AccessibilityOnMessage = discordgo.MessageEmbed{
+		Title:       "Accessibility mode is on",
+		Color:       Purple,
+		Description: "Menus, lists and times I send you are now written out in plain text for screen readers.",
+	}
+	AccessibilityOffMessage = discordgo.MessageEmbed{
+		Title:       "Accessibility mode is off",
+		Color:       Purple,
+		Description: "Use `/accessibility` again to turn it back on.",
+	}
+
 	EnterEditOptionMessage = discordgo.MessageEmbed{
 		Title:       "What would you like to do?",
 		Color:       Purple,
-		Description: "**1** Modify the event\n**2** Remove responses\n**3** Add a response",
+		Description: "**1** Modify the event\n**2** Remove responses\n**3** Add a response\n**4** Take attendance\n**5** Message attendees",
 		Footer: &discordgo.MessageEmbedFooter{
 			Text: OptionText + "\n" + CancelText,
 		},
@@ -130,12 +273,30 @@ var (
 		},
 	}
 
-	EditConfirmationMessage = discordgo.MessageEmbed{
-		Title:       "Would you like to keep editing?",
-		Description: "**1** No, I'm all done\n**2** Yes, keep editing",
+	ReviewFieldMessage = discordgo.MessageEmbed{
+		Title:       "What would you like to change?",
+		Description: "**1** Title\n**2** Description\n**3** Attendee limit\n**4** Start time\n**5** Duration\n**6** Location\n**7** Signup deadline\n**8** Category",
+		Color:       Purple,
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: OptionText + "\n" + CancelText,
+		},
+	}
+
+	DraftSavedMessage = discordgo.MessageEmbed{
+		Title:       "Your draft has been saved",
+		Description: "Use `/event` or `/drafts` again to finish it.",
 		Color:       Purple,
 	}
 
+	EnterPublishTimeMessage = discordgo.MessageEmbed{
+		Title:       "When should the event be posted?",
+		Description: "The event is posted, added to the calendar and to the server's events at this time. I'll message you when it goes live.",
+		Color:       Purple,
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "Example: Monday at noon\n" + CancelText,
+		},
+	}
+
 	DeleteInsufficientPermissionMessage = &discordgo.MessageEmbed{
 		Title: "You don't have permission to delete that event",
 		Color: Purple,
@@ -148,18 +309,30 @@ var (
 	}
 )
 
-func CreateEventMessage(guildID, channelID string) *discordgo.InteractionResponse {
+func CreateEventMessage(p *Printer, guildID, channelID string) *discordgo.InteractionResponse {
 	return &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
 			Embeds: []*discordgo.MessageEmbed{
 				{
-					Title:       "Let's create an event",
+					Title:       p.T("Let's create an event"),
 					Color:       Purple,
-					Description: fmt.Sprintf("I've sent you a [direct message](https://discordapp.com/channels/%s/%s) with next steps.", guildID, channelID),
+					Description: p.Tf("I've sent you a [direct message](https://discordapp.com/channels/%s/%s) with next steps.", guildID, channelID),
 				},
 			},
 			Flags: discordgo.MessageFlagsEphemeral,
 		},
 	}
 }
+
+// ConfirmDateMessage echoes back how an ambiguous time was read
+func ConfirmDateMessage(start, end time.Time) *discordgo.MessageEmbed {
+	return &discordgo.MessageEmbed{
+		Title:       "Did you mean this time?",
+		Color:       Purple,
+		Description: util.PrintTime(start, end) + "\n\n**1** Yes\n**2** No, I'll enter it again",
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: OptionText + "\n" + CancelText,
+		},
+	}
+}
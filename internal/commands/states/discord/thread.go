@@ -0,0 +1,104 @@
+package discord
+
+import (
+	"fmt"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/role"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/pkg/util"
+	"github.com/bwmarrin/discordgo"
+	"unicode/utf8"
+)
+
+// maxThreadNameLength is the longest name Discord allows for a thread
+const maxThreadNameLength = 100
+
+// threadArchiveMinutes hides a thread from the channel list after a week without messages. Posting to it unhides it.
+const threadArchiveMinutes = 10080
+
+// StartThread attaches a discussion thread to an event message and adds the organizer to it
+func StartThread(s *discordgo.Session, channelID, messageID, ownerID string, e *Event) error {
+	thread, err := s.MessageThreadStartComplex(channelID, messageID, &discordgo.ThreadStart{
+		Name:                ThreadName(e.Title),
+		AutoArchiveDuration: threadArchiveMinutes,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to start thread: %w", err)
+	}
+	e.ThreadID = thread.ID
+	if ownerID == "" {
+		return nil
+	}
+	return s.ThreadMemberAdd(thread.ID, ownerID)
+}
+
+// ThreadName shortens an event title to fit in a thread name
+func ThreadName(title string) string {
+	if utf8.RuneCountInString(title) <= maxThreadNameLength {
+		return title
+	}
+	return string([]rune(title)[:maxThreadNameLength-1]) + "…"
+}
+
+// InThread reports whether a response gives a member a place in the event's thread: a spot on the accepted list
+func InThread(r role.Response) bool {
+	return r.Field == role.AcceptedField && r.Waitlist == 0
+}
+
+// SyncThreadMember adds a member to the event's thread when they get a spot on the accepted list and removes them when
+// they lose it, whether they dropped out, changed their response or were moved to the waitlist
+func (e *Event) SyncThreadMember(s *discordgo.Session, userID string, before, after role.Response) error {
+	if e.ThreadID == "" || InThread(before) == InThread(after) {
+		return nil
+	}
+	if InThread(after) {
+		return s.ThreadMemberAdd(e.ThreadID, userID)
+	}
+	return s.ThreadMemberRemove(e.ThreadID, userID)
+}
+
+// SyncThreadMemberByName is SyncThreadMember for a guild member known by their username. Members who cannot be found
+// are skipped.
+func (e *Event) SyncThreadMemberByName(s *discordgo.Session, guildID, name string, before, after role.Response) error {
+	if e.ThreadID == "" || InThread(before) == InThread(after) {
+		return nil
+	}
+	m, err := FindMember(s, guildID, name)
+	if err != nil || m == nil {
+		return err
+	}
+	return e.SyncThreadMember(s, m.User.ID, before, after)
+}
+
+// PostToThread sends a message to the event's thread, if it has one
+func (e *Event) PostToThread(s *discordgo.Session, msg *discordgo.MessageSend) error {
+	if e.ThreadID == "" {
+		return nil
+	}
+	_, err := s.ChannelMessageSendComplex(e.ThreadID, msg)
+	return err
+}
+
+// ArchiveThread locks and archives the event's thread once the event is over
+func (e *Event) ArchiveThread(s *discordgo.Session) error {
+	if e.ThreadID == "" {
+		return nil
+	}
+	archived, locked := true, true
+	_, err := s.ChannelEditComplex(e.ThreadID, &discordgo.ChannelEdit{
+		Archived: &archived,
+		Locked:   &locked,
+	})
+	return err
+}
+
+// EventUpdatedMessage tells the thread an organizer changed the event details
+func EventUpdatedMessage(p *Printer, e *Event) *discordgo.MessageSend {
+	return &discordgo.MessageSend{
+		Embeds: []*discordgo.MessageEmbed{
+			{
+				Title:       p.Tf("%s was updated", e.Title),
+				Color:       Purple,
+				Description: util.PrintTime(e.Start, e.End) + "\n\n" + p.Tf("[Click here to view the event](%s)", e.DiscordLink),
+			},
+		},
+	}
+}
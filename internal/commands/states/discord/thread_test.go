@@ -0,0 +1,64 @@
+package discord
+
+import (
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/role"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestThreadName(t *testing.T) {
+	assert.Equal(t, "Salsa Social", ThreadName("Salsa Social"))
+
+	name := ThreadName(strings.Repeat("a", 120))
+	assert.Equal(t, maxThreadNameLength, utf8.RuneCountInString(name))
+	assert.True(t, strings.HasSuffix(name, "…"))
+}
+
+func TestEvent_ThreadRoundTrip(t *testing.T) {
+	e := &Event{
+		Title:     "test",
+		Start:     time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC),
+		Owner:     "foo",
+		RoleGroup: role.NewDefaultRoleGroup(),
+		ThreadID:  "1234",
+	}
+	embed, err := ConvertEventToMessageEmbed(e)
+	assert.NoError(t, err)
+
+	got, err := GetEventFromMessage(&discordgo.Message{Embeds: []*discordgo.MessageEmbed{embed}})
+	assert.NoError(t, err)
+	assert.Equal(t, "1234", got.ThreadID)
+}
+
+func TestEvent_SyncThreadMember(t *testing.T) {
+	// Events without a thread never call Discord
+	e := &Event{}
+	accepted := role.Response{Field: role.AcceptedField}
+	assert.NoError(t, e.SyncThreadMember(nil, "1", role.Response{}, accepted))
+	assert.NoError(t, e.SyncThreadMemberByName(nil, "1", "foo", accepted, role.Response{}))
+	assert.NoError(t, e.PostToThread(nil, EventUpdatedMessage(nil, e)))
+	assert.NoError(t, e.ArchiveThread(nil))
+}
+
+func TestInThread(t *testing.T) {
+	cases := []struct {
+		name     string
+		response role.Response
+		expected bool
+	}{
+		{name: "accepted", response: role.Response{Field: role.AcceptedField}, expected: true},
+		{name: "waitlisted", response: role.Response{Field: role.AcceptedField, Waitlist: 1}},
+		{name: "tentative", response: role.Response{Field: role.TentativeField}},
+		{name: "declined", response: role.Response{Field: role.DeclinedField}},
+		{name: "no response"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, InThread(tc.response))
+		})
+	}
+}
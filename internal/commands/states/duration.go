@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/GuessWhoSamFoo/fsm"
 	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/discord"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/pkg/timeparse"
 	"github.com/bwmarrin/discordgo"
 	"github.com/tj/go-naturaldate"
 	"strings"
@@ -15,6 +16,7 @@ type SetDurationState struct {
 	session           *discordgo.Session
 	interactionCreate *discordgo.InteractionCreate
 	channel           *discordgo.Channel
+	printer           *discord.Printer
 
 	inputHandler *InputHandler
 }
@@ -24,18 +26,27 @@ func NewDurationState(o discord.Options) *SetDurationState {
 		session:           o.Session,
 		interactionCreate: o.InteractionCreate,
 		channel:           o.Channel,
+		printer:           o.Printer,
 		inputHandler:      NewInputHandler(&o),
 	}
 }
 
 func (d *SetDurationState) OnState(ctx context.Context, e *fsm.Event) {
-	_, err := d.session.ChannelMessageSendEmbed(d.channel.ID, &discord.EnterDurationMessage)
+	// The end time was already given with the start, as in "Sat 8pm-midnight". It is only asked for again when
+	// changed from the preview.
+	if e.Src == SetLocation.String() {
+		end, _ := Get(e.FSM, discord.Duration)
+		if endTime, ok := end.(time.Time); ok && !endTime.IsZero() {
+			return
+		}
+	}
+	_, err := d.session.ChannelMessageSendEmbed(d.channel.ID, d.printer.Embed(&discord.EnterDurationMessage))
 	if err != nil {
 		e.Err = err
 		return
 	}
 
-	if err = d.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.Duration, 60*time.Second); err != nil {
+	if err = d.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.Duration, promptTimeout); err != nil {
 		e.Err = err
 		return
 	}
@@ -52,6 +63,7 @@ type SetDurationRetryState struct {
 	session           *discordgo.Session
 	interactionCreate *discordgo.InteractionCreate
 	channel           *discordgo.Channel
+	printer           *discord.Printer
 
 	inputHandler *InputHandler
 }
@@ -61,18 +73,19 @@ func NewDurationRetryState(o discord.Options) *SetDurationRetryState {
 		session:           o.Session,
 		interactionCreate: o.InteractionCreate,
 		channel:           o.Channel,
+		printer:           o.Printer,
 		inputHandler:      NewInputHandler(&o),
 	}
 }
 
 func (d *SetDurationRetryState) OnState(ctx context.Context, e *fsm.Event) {
-	_, err := d.session.ChannelMessageSend(d.channel.ID, discord.InvalidDurationText)
+	_, err := d.session.ChannelMessageSend(d.channel.ID, d.printer.T(discord.InvalidDurationText))
 	if err != nil {
 		e.Err = err
 		return
 	}
 
-	if err = d.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.Duration, 60*time.Second); err != nil {
+	if err = d.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.Duration, promptTimeout); err != nil {
 		e.Err = err
 		return
 	}
@@ -102,9 +115,12 @@ func validateDuration(e *fsm.Event, key discord.MetadataKey) error {
 	}
 	startTime := start.(time.Time)
 
-	endTime, err := naturaldate.Parse(input, startTime, naturaldate.WithDirection(naturaldate.Future))
+	endTime, err := timeparse.ParseEnd(input, startTime)
 	if err != nil {
-		return err
+		endTime, err = naturaldate.Parse(input, startTime, naturaldate.WithDirection(naturaldate.Future))
+		if err != nil {
+			return err
+		}
 	}
 
 	if input == "" || endTime.Before(startTime) || endTime.Equal(startTime) {
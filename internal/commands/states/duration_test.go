@@ -112,6 +112,34 @@ func TestSetDurationState_OnState(t *testing.T) {
 	}
 }
 
+func TestSetDurationState_OnState_EndGiven(t *testing.T) {
+	opts, err := discord.NewMockOptions()
+	assert.NoError(t, err)
+	now := time.Now()
+
+	d := NewDurationState(*opts)
+	f := fsm.NewFSM(
+		SetLocation.String(),
+		fsm.Events{
+			{
+				Name: SetDuration.String(),
+				Src:  []string{SetLocation.String()},
+				Dst:  SetDuration.String(),
+			},
+		},
+		fsm.Callbacks{
+			SetDuration.String(): d.OnState,
+		},
+	)
+	f.SetMetadata(discord.StartTime.String(), now)
+	f.SetMetadata(discord.Duration.String(), now.Add(time.Hour))
+
+	assert.NoError(t, f.Event(context.TODO(), SetDuration.String()))
+	got, err := Get(f, discord.Duration)
+	assert.NoError(t, err)
+	assert.Equal(t, now.Add(time.Hour), got)
+}
+
 func TestSetDurationRetryState_OnState(t *testing.T) {
 	opts, err := discord.NewMockOptions()
 	assert.NoError(t, err)
@@ -257,16 +285,14 @@ func Test_validateDuration(t *testing.T) {
 			expected: time.Time{},
 			isErr:    true,
 		},
-		// TODO: Find a more configurable parser
-		//{
-		//	input:    "1h30m",
-		//	expected: now.Add(time.Minute * 90),
-		//},
-		//{
-		//	input:    "1hr 30 minutes",
-		//	expected: time.Time{},
-		//	isErr:    true,
-		//},
+		{
+			input:    "1h30m",
+			expected: now.Add(time.Minute * 90),
+		},
+		{
+			input:    "1hr 30 minutes",
+			expected: now.Add(time.Minute * 90),
+		},
 	}
 
 	for _, tc := range cases {
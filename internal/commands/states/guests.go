@@ -0,0 +1,116 @@
+package states
+
+import (
+	"context"
+	"fmt"
+	"github.com/GuessWhoSamFoo/fsm"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/discord"
+	"github.com/bwmarrin/discordgo"
+	"strconv"
+	"strings"
+)
+
+// maxGuests is the most guests an organizer can allow per member. Each choice is an option in a select menu, which
+// holds up to 25.
+const maxGuests = 10
+
+type SetGuestsState struct {
+	session           *discordgo.Session
+	interactionCreate *discordgo.InteractionCreate
+	channel           *discordgo.Channel
+	printer           *discord.Printer
+
+	inputHandler *InputHandler
+}
+
+func NewGuestsState(o discord.Options) *SetGuestsState {
+	return &SetGuestsState{
+		session:           o.Session,
+		interactionCreate: o.InteractionCreate,
+		channel:           o.Channel,
+		printer:           o.Printer,
+		inputHandler:      NewInputHandler(&o),
+	}
+}
+
+func (g *SetGuestsState) OnState(ctx context.Context, e *fsm.Event) {
+	_, err := g.session.ChannelMessageSendEmbed(g.channel.ID, g.printer.Embed(&discord.EnterGuestsMessage))
+	if err != nil {
+		e.Err = err
+		return
+	}
+
+	if err = g.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.Guests, promptTimeout); err != nil {
+		e.Err = err
+		return
+	}
+	if err = validateGuests(e.FSM, discord.Guests); err != nil {
+		eventErr := e.FSM.Event(ctx, SetGuestsRetry.String())
+		if eventErr != nil {
+			e.Err = fmt.Errorf("%v: %v", err, eventErr)
+			return
+		}
+	}
+}
+
+type SetGuestsRetryState struct {
+	session           *discordgo.Session
+	interactionCreate *discordgo.InteractionCreate
+	channel           *discordgo.Channel
+	printer           *discord.Printer
+
+	inputHandler *InputHandler
+}
+
+func NewGuestsRetryState(o discord.Options) *SetGuestsRetryState {
+	return &SetGuestsRetryState{
+		session:           o.Session,
+		interactionCreate: o.InteractionCreate,
+		channel:           o.Channel,
+		printer:           o.Printer,
+		inputHandler:      NewInputHandler(&o),
+	}
+}
+
+func (g *SetGuestsRetryState) OnState(ctx context.Context, e *fsm.Event) {
+	_, err := g.session.ChannelMessageSend(g.channel.ID, g.printer.T(discord.InvalidGuestsText))
+	if err != nil {
+		e.Err = err
+		return
+	}
+
+	if err = g.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.Guests, promptTimeout); err != nil {
+		e.Err = err
+		return
+	}
+	if err = validateGuests(e.FSM, discord.Guests); err != nil {
+		eventErr := e.FSM.Event(ctx, SelfTransition.String())
+		if eventErr != nil {
+			e.Err = fmt.Errorf("%v: %v", err, eventErr)
+			return
+		}
+	}
+}
+
+// validateGuests parses how many guests each member can bring. `None` does not allow guests.
+func validateGuests(f *fsm.FSM, key discord.MetadataKey) error {
+	val, err := Get(f, key)
+	if err != nil {
+		return err
+	}
+	input := strings.TrimSpace(fmt.Sprintf("%v", val))
+	if strings.EqualFold(input, "none") {
+		f.SetMetadata(key.String(), 0)
+		return nil
+	}
+	n, err := strconv.Atoi(input)
+	if err == nil && !InRange(n, maxGuests, 0) {
+		err = fmt.Errorf("guests out of bounds")
+	}
+	if err != nil {
+		f.SetMetadata(key.String(), 0)
+		return err
+	}
+	f.SetMetadata(key.String(), n)
+	return nil
+}
@@ -0,0 +1,192 @@
+package states
+
+import (
+	"context"
+	"github.com/GuessWhoSamFoo/fsm"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/discord"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+func TestNewGuestsState(t *testing.T) {
+	opts, err := discord.NewMockOptions()
+	assert.NoError(t, err)
+
+	s := NewGuestsState(*opts)
+	assert.NotNil(t, s)
+}
+
+func TestSetGuestsState_OnState(t *testing.T) {
+	opts, err := discord.NewMockOptions()
+	assert.NoError(t, err)
+
+	cases := []struct {
+		name          string
+		input         string
+		expectedState string
+		expected      int
+		isErr         bool
+	}{
+		{
+			name:          "valid",
+			input:         "2",
+			expectedState: SetGuests.String(),
+			expected:      2,
+		},
+		{
+			name:          "out of bounds",
+			input:         "50",
+			expectedState: SetGuestsRetry.String(),
+		},
+		{
+			name:          "none",
+			input:         "none",
+			expectedState: SetGuests.String(),
+		},
+		{
+			name:          "cancel",
+			input:         "cancel",
+			expectedState: Cancel.String(),
+			isErr:         true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := NewGuestsState(*opts)
+			f := fsm.NewFSM(
+				"idle",
+				fsm.Events{
+					{
+						Name: SetGuests.String(),
+						Src:  []string{"idle"},
+						Dst:  SetGuests.String(),
+					},
+					{
+						Name: SetGuestsRetry.String(),
+						Src:  []string{SetGuests.String()},
+						Dst:  SetGuestsRetry.String(),
+					},
+					{
+						Name: Cancel.String(),
+						Src:  []string{SetGuests.String()},
+						Dst:  Cancel.String(),
+					},
+				},
+				fsm.Callbacks{
+					SetGuests.String(): d.OnState,
+				},
+			)
+			d.inputHandler.handlerFunc = func(session *discordgo.Session, create *discordgo.MessageCreate) {
+				d.inputHandler.inputChan <- tc.input
+			}
+			var wg sync.WaitGroup
+			wg.Add(2)
+			go func() {
+				d.inputHandler.handlerFunc(opts.Session, &discordgo.MessageCreate{})
+				wg.Done()
+			}()
+
+			go func() {
+				err = f.Event(context.TODO(), SetGuests.String())
+				if tc.isErr {
+					assert.Error(t, err)
+				} else {
+					assert.NoError(t, err)
+					got, err := Get(f, discord.Guests)
+					assert.NoError(t, err)
+					assert.Equal(t, tc.expected, got)
+				}
+				wg.Done()
+			}()
+			wg.Wait()
+			assert.Equal(t, tc.expectedState, f.Current())
+		})
+	}
+}
+
+func TestSetGuestsRetryState_OnState(t *testing.T) {
+	opts, err := discord.NewMockOptions()
+	assert.NoError(t, err)
+
+	cases := []struct {
+		name          string
+		input         string
+		expectedState string
+		expected      int
+		isErr         bool
+	}{
+		{
+			name:          "valid",
+			input:         "1",
+			expectedState: SetGuestsRetry.String(),
+			expected:      1,
+		},
+		{
+			name:          "invalid",
+			input:         "invalid",
+			expectedState: SelfTransition.String(),
+		},
+		{
+			name:          "cancel",
+			input:         "cancel",
+			expectedState: Cancel.String(),
+			isErr:         true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := NewGuestsRetryState(*opts)
+			f := fsm.NewFSM(
+				"idle",
+				fsm.Events{
+					{
+						Name: SetGuestsRetry.String(),
+						Src:  []string{"idle"},
+						Dst:  SetGuestsRetry.String(),
+					},
+					{
+						Name: SelfTransition.String(),
+						Src:  []string{SetGuestsRetry.String()},
+						Dst:  SelfTransition.String(),
+					},
+					{
+						Name: Cancel.String(),
+						Src:  []string{SetGuestsRetry.String()},
+						Dst:  Cancel.String(),
+					},
+				},
+				fsm.Callbacks{
+					SetGuestsRetry.String(): d.OnState,
+				},
+			)
+			d.inputHandler.handlerFunc = func(session *discordgo.Session, create *discordgo.MessageCreate) {
+				d.inputHandler.inputChan <- tc.input
+			}
+			var wg sync.WaitGroup
+			wg.Add(2)
+			go func() {
+				d.inputHandler.handlerFunc(opts.Session, &discordgo.MessageCreate{})
+				wg.Done()
+			}()
+			go func() {
+				err = f.Event(context.TODO(), SetGuestsRetry.String())
+				if tc.isErr {
+					assert.Error(t, err)
+				} else {
+					assert.NoError(t, err)
+
+					got, err := Get(f, discord.Guests)
+					assert.NoError(t, err)
+					assert.Equal(t, tc.expected, got)
+				}
+				wg.Done()
+			}()
+			wg.Wait()
+			assert.Equal(t, tc.expectedState, f.Current())
+		})
+	}
+}
@@ -32,23 +32,89 @@ func NewInputHandler(o *discord.Options) *InputHandler {
 	}
 }
 
+// Prompts wait for an answer this long unless configured otherwise. Long text such as a description takes longer
+// to write.
+const (
+	promptTimeout     = 60 * time.Second
+	longPromptTimeout = 10 * time.Minute
+)
+
+// skipAnswers are the answers given to optional prompts when the user types "skip"
+var skipAnswers = map[discord.MetadataKey]string{
+	discord.Description:  "",
+	discord.Attendee:     "none",
+	discord.Duration:     "none",
+	discord.Deadline:     "none",
+	discord.Guests:       "none",
+	discord.CategoryName: "none",
+}
+
+// AwaitInputOrTimeout waits for an answer to the prompt of the current state and stores it under key. The user can
+// also cancel, skip an optional prompt or go back to the previous prompt when the sequence allows it. A reminder is
+// sent before the prompt times out.
 func (ih *InputHandler) AwaitInputOrTimeout(ctx context.Context, f *fsm.FSM, key discord.MetadataKey, wait time.Duration) error {
 	cancelFunc := ih.Options.Session.AddHandler(ih.handlerFunc)
 	defer cancelFunc()
+	canGoBack := f.Can(Back.String())
+	if canGoBack {
+		visitPrompt(f)
+	}
+	wait = ih.Options.Timeouts.Wait(Prompt(f.Current()), wait)
 	// NewTimer is used instead because time.After can leak memory if the timer doesn't fire
 	timer := time.NewTimer(wait)
-	select {
-	case result := <-ih.inputChan:
-		if strings.EqualFold(result, "cancel") {
-			err := f.Event(ctx, Cancel.String())
-			return fmt.Errorf("event action canceled: %v", err)
+	defer timer.Stop()
+	var remind <-chan time.Time
+	if reminder := ih.Options.Timeouts.Reminder; reminder > 0 && reminder < wait {
+		reminderTimer := time.NewTimer(wait - reminder)
+		defer reminderTimer.Stop()
+		remind = reminderTimer.C
+	}
+	deadline := time.Now().Add(wait)
+
+	for {
+		select {
+		case result := <-ih.inputChan:
+			result = ih.Options.Printer.Keyword(result)
+			switch {
+			case strings.EqualFold(result, "cancel"):
+				err := f.Event(ctx, Cancel.String())
+				return fmt.Errorf("event action canceled: %v", err)
+			case strings.EqualFold(result, "back"):
+				if !canGoBack || !hasPreviousPrompt(f) {
+					if err := ih.send(ih.Options.Printer.T(discord.NothingToGoBackText)); err != nil {
+						return err
+					}
+					continue
+				}
+				err := f.Event(ctx, Back.String())
+				return fmt.Errorf("event action went back: %v", err)
+			case strings.EqualFold(result, "skip"):
+				answer, ok := skipAnswers[key]
+				if !ok {
+					if err := ih.send(ih.Options.Printer.T(discord.CannotSkipText)); err != nil {
+						return err
+					}
+					continue
+				}
+				result = answer
+			}
+			f.SetMetadata(key.String(), result)
+			return nil
+		case <-remind:
+			if err := ih.send(ih.Options.Printer.Tf(discord.ReminderText, deadline.Unix())); err != nil {
+				return err
+			}
+		case <-timer.C:
+			err := f.Event(ctx, Timeout.String())
+			return fmt.Errorf("event action timed out: %v", err)
 		}
-		f.SetMetadata(key.String(), result)
-	case <-timer.C:
-		err := f.Event(ctx, Timeout.String())
-		return fmt.Errorf("event action timed out: %v", err)
 	}
-	return nil
+}
+
+// send replies to the user in the DM channel of the prompt
+func (ih *InputHandler) send(text string) error {
+	_, err := ih.Options.Session.ChannelMessageSend(ih.Options.Channel.ID, text)
+	return err
 }
 
 func Get(f *fsm.FSM, key discord.MetadataKey) (interface{}, error) {
@@ -30,3 +30,113 @@ func TestAwaitInputOrTimeout(t *testing.T) {
 	err = ts.inputHandler.AwaitInputOrTimeout(ctx, f, "", 10*time.Millisecond)
 	assert.Errorf(t, err, "event creation timed out: %v", nil)
 }
+
+func TestAwaitInputOrTimeout_Keywords(t *testing.T) {
+	cases := []struct {
+		name          string
+		key           discord.MetadataKey
+		history       []string
+		inputs        []string
+		expectedState string
+		expected      interface{}
+		isErr         bool
+	}{
+		{
+			name:          "answer",
+			key:           discord.Title,
+			inputs:        []string{"title"},
+			expectedState: AddTitle.String(),
+			expected:      "title",
+		},
+		{
+			name:          "back",
+			key:           discord.Title,
+			history:       []string{StartCreate.String()},
+			inputs:        []string{"back"},
+			expectedState: Back.String(),
+			isErr:         true,
+		},
+		{
+			name:          "nothing to go back to",
+			key:           discord.Title,
+			inputs:        []string{"back", "title"},
+			expectedState: AddTitle.String(),
+			expected:      "title",
+		},
+		{
+			name:          "skip",
+			key:           discord.Description,
+			inputs:        []string{"Skip"},
+			expectedState: AddTitle.String(),
+			expected:      "",
+		},
+		{
+			name:          "required",
+			key:           discord.Title,
+			inputs:        []string{"skip", "title"},
+			expectedState: AddTitle.String(),
+			expected:      "title",
+		},
+		{
+			name:          "cancel",
+			key:           discord.Title,
+			inputs:        []string{"cancel"},
+			expectedState: Cancel.String(),
+			isErr:         true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			opts, err := discord.NewMockOptions()
+			assert.NoError(t, err)
+			ih := NewInputHandler(opts)
+
+			f := fsm.NewFSM(
+				AddTitle.String(),
+				fsm.Events{
+					{Name: Back.String(), Src: []string{AddTitle.String()}, Dst: Back.String()},
+					{Name: Cancel.String(), Src: []string{AddTitle.String()}, Dst: Cancel.String()},
+				},
+				fsm.Callbacks{},
+			)
+			if tc.history != nil {
+				f.SetMetadata(historyKey, tc.history)
+			}
+			go func() {
+				for _, input := range tc.inputs {
+					ih.inputChan <- input
+				}
+			}()
+			err = ih.AwaitInputOrTimeout(context.TODO(), f, tc.key, time.Second)
+			if tc.isErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				actual, err := Get(f, tc.key)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, actual)
+			}
+			assert.Equal(t, tc.expectedState, f.Current())
+		})
+	}
+}
+
+func TestAwaitInputOrTimeout_Reminder(t *testing.T) {
+	opts, err := discord.NewMockOptions()
+	assert.NoError(t, err)
+	opts.Timeouts = discord.Timeouts{Reminder: 40 * time.Millisecond}
+	ts := NewTimeoutState(*opts)
+
+	f := fsm.NewFSM(
+		"idle",
+		fsm.Events{
+			{Name: Timeout.String(), Src: []string{"idle"}, Dst: Timeout.String()},
+		},
+		fsm.Callbacks{
+			Timeout.String(): ts.OnState,
+		})
+	err = ts.inputHandler.AwaitInputOrTimeout(context.TODO(), f, "", 50*time.Millisecond)
+	assert.Error(t, err)
+	assert.Equal(t, Timeout.String(), f.Current())
+}
@@ -5,13 +5,13 @@ import (
 	"github.com/GuessWhoSamFoo/fsm"
 	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/discord"
 	"github.com/bwmarrin/discordgo"
-	"time"
 )
 
 type SetLocationState struct {
 	session           *discordgo.Session
 	interactionCreate *discordgo.InteractionCreate
 	channel           *discordgo.Channel
+	printer           *discord.Printer
 
 	inputHandler *InputHandler
 }
@@ -21,18 +21,19 @@ func NewSetLocationState(o discord.Options) *SetLocationState {
 		session:           o.Session,
 		interactionCreate: o.InteractionCreate,
 		channel:           o.Channel,
+		printer:           o.Printer,
 		inputHandler:      NewInputHandler(&o),
 	}
 }
 
 func (l *SetLocationState) OnState(ctx context.Context, e *fsm.Event) {
-	_, err := l.session.ChannelMessageSendEmbed(l.channel.ID, &discord.EnterLocationMessage)
+	_, err := l.session.ChannelMessageSendEmbed(l.channel.ID, l.printer.Embed(&discord.EnterLocationMessage))
 	if err != nil {
 		e.Err = err
 		return
 	}
 
-	err = l.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.Location, 60*time.Second)
+	err = l.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.Location, promptTimeout)
 	if err != nil {
 		e.Err = err
 		return
@@ -14,6 +14,9 @@ import (
 	"net/http"
 )
 
+// TextChannel is a text channel of the mock guild the bot can post events in
+const TextChannel = "testTextChannel"
+
 func NewState() (*discordgo.State, error) {
 	role := mockrole.New(
 		mockrole.WithID(mockconstants.TestRole),
@@ -73,6 +76,18 @@ func NewState() (*discordgo.State, error) {
 		}),
 	)
 
+	textChannel := mockchannel.New(
+		mockchannel.WithID(TextChannel),
+		mockchannel.WithGuildID(mockconstants.TestGuild),
+		mockchannel.WithName(TextChannel),
+		mockchannel.WithType(discordgo.ChannelTypeGuildText),
+		mockchannel.WithPermissionOverwrites(&discordgo.PermissionOverwrite{
+			ID:    botMember.User.ID,
+			Type:  discordgo.PermissionOverwriteTypeMember,
+			Allow: discordgo.PermissionSendMessages | discordgo.PermissionEmbedLinks,
+		}),
+	)
+
 	return mockstate.New(
 		mockstate.WithUser(botUser),
 		mockstate.WithGuilds(
@@ -80,7 +95,7 @@ func NewState() (*discordgo.State, error) {
 				mockguild.WithID(mockconstants.TestGuild),
 				mockguild.WithName(mockconstants.TestGuild),
 				mockguild.WithRoles(role),
-				mockguild.WithChannels(channel, privateChannel),
+				mockguild.WithChannels(channel, privateChannel, textChannel),
 				mockguild.WithMembers(botMember, userMember, catMember),
 			),
 		),
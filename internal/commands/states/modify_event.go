@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"github.com/GuessWhoSamFoo/fsm"
 	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/discord"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/role"
 	"github.com/GuessWhoSamFoo/gang-gang-bot/pkg/util"
 	"github.com/bwmarrin/discordgo"
+	"strconv"
 	"time"
 )
 
@@ -14,6 +16,7 @@ type ModifyEventState struct {
 	session           *discordgo.Session
 	interactionCreate *discordgo.InteractionCreate
 	channel           *discordgo.Channel
+	printer           *discord.Printer
 	inputHandler      *InputHandler
 }
 
@@ -22,6 +25,7 @@ func NewModifyEventState(o discord.Options) *ModifyEventState {
 		session:           o.Session,
 		interactionCreate: o.InteractionCreate,
 		channel:           o.Channel,
+		printer:           o.Printer,
 		inputHandler:      NewInputHandler(&o),
 	}
 }
@@ -38,7 +42,7 @@ func (m *ModifyEventState) OnState(ctx context.Context, e *fsm.Event) {
 		return
 	}
 
-	if _, err = m.session.ChannelMessageSendEmbed(m.channel.ID, &discordgo.MessageEmbed{
+	if _, err = m.session.ChannelMessageSendEmbed(m.channel.ID, m.printer.Embed(&discordgo.MessageEmbed{
 		Title: "What would you like to modify?",
 		Color: discord.Purple,
 		Fields: []*discordgo.MessageEmbedField{
@@ -64,16 +68,42 @@ func (m *ModifyEventState) OnState(ctx context.Context, e *fsm.Event) {
 				Name:  "5 ⋅ Location",
 				Value: fmt.Sprintf("```%s```", event.Location),
 			},
+			{
+				Name:  "6 ⋅ Signup Deadline",
+				Value: util.PrintBlockValues(printDeadline(event.Deadline)),
+			},
+			{
+				Name:  "7 ⋅ Guests per Member",
+				Value: util.PrintBlockValues(printGuests(event.MaxGuests)),
+			},
+			{
+				Name:  "8 ⋅ Category and Color",
+				Value: util.PrintBlockValues(event.Category.String()),
+			},
+			{
+				Name:   "9 ⋅ Attendee Limit",
+				Value:  util.PrintBlockValues(printLimit(event.RoleGroup)),
+				Inline: true,
+			},
+			{
+				Name:   "10 ⋅ Organizer",
+				Value:  util.PrintBlockValues(event.Owner),
+				Inline: true,
+			},
+			{
+				Name:  "11 ⋅ Channel",
+				Value: fmt.Sprintf("<#%s>", eventChannel(e.FSM, m.interactionCreate)),
+			},
 		},
 		Footer: &discordgo.MessageEmbedFooter{
 			Text: discord.OptionText + "\n" + discord.CancelText,
 		},
-	}); err != nil {
+	})); err != nil {
 		e.Err = err
 		return
 	}
 
-	if err = m.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.MenuOption, 60*time.Second); err != nil {
+	if err = m.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.MenuOption, promptTimeout); err != nil {
 		e.Err = err
 		return
 	}
@@ -103,41 +133,94 @@ func (m *ModifyEventState) OnState(ctx context.Context, e *fsm.Event) {
 	}
 }
 
+// saveEventChanges applies the answers given so far to the event and records which fields differ from the event as it
+// was before the edit
 func saveEventChanges(e *fsm.Event, event *discord.Event) error {
-	title, found := e.FSM.Metadata(discord.Title.String())
+	if _, found := e.FSM.Metadata(discord.Original.String()); !found {
+		e.FSM.SetMetadata(discord.Original.String(), *event)
+	}
+	applyEventChanges(e.FSM, event)
+	e.FSM.SetMetadata(discord.EventObject.String(), *event)
+
+	original, err := Get(e.FSM, discord.Original)
+	if err != nil {
+		return err
+	}
+	before, ok := original.(discord.Event)
+	if !ok {
+		return fmt.Errorf("cannot get original event")
+	}
+	e.FSM.SetMetadata(discord.Changes.String(), discord.DiffEvents(&before, event))
+	return nil
+}
+
+// applyEventChanges sets the fields of an event which were answered during the edit
+func applyEventChanges(f *fsm.FSM, event *discord.Event) {
+	title, found := f.Metadata(discord.Title.String())
 	if found {
 		event.Title = fmt.Sprintf("%s", title)
 	}
-	description, found := e.FSM.Metadata(discord.Description.String())
+	description, found := f.Metadata(discord.Description.String())
 	if found {
 		event.Description = fmt.Sprintf("%s", description)
 	}
-	date, found := e.FSM.Metadata(discord.StartTime.String())
+	date, found := f.Metadata(discord.StartTime.String())
 	if found {
 		val, ok := date.(time.Time)
 		if ok {
 			event.Start = val
 		}
 	}
-	duration, found := e.FSM.Metadata(discord.Duration.String())
+	duration, found := f.Metadata(discord.Duration.String())
 	if found {
 		val, ok := duration.(time.Time)
 		if ok {
 			event.End = val
 		}
 	}
-	location, found := e.FSM.Metadata(discord.Location.String())
+	location, found := f.Metadata(discord.Location.String())
 	if found {
 		event.Location = fmt.Sprintf("%s", location)
 	}
-	e.FSM.SetMetadata(discord.EventObject.String(), *event)
-	return nil
+	deadline, found := f.Metadata(discord.Deadline.String())
+	if found {
+		val, ok := deadline.(time.Time)
+		if ok {
+			event.Deadline = val
+		}
+	}
+	guests, found := f.Metadata(discord.Guests.String())
+	if found {
+		val, ok := guests.(int)
+		if ok {
+			event.MaxGuests = val
+		}
+	}
+	category, found := f.Metadata(discord.CategoryName.String())
+	if found {
+		val, ok := category.(discord.Category)
+		if ok {
+			event.SetCategory(val)
+		}
+	}
+	attendee, found := f.Metadata(discord.Attendee.String())
+	if found {
+		val, ok := attendee.(*role.RoleGroup)
+		if ok && event.RoleGroup != nil {
+			event.RoleGroup.ChangeLimit(role.AcceptedField, val.GetLimit(role.AcceptedField))
+		}
+	}
+	owner, found := f.Metadata(discord.Owner.String())
+	if found && owner != "" {
+		event.Owner = fmt.Sprintf("%s", owner)
+	}
 }
 
 type ModifyEventRetryState struct {
 	session           *discordgo.Session
 	interactionCreate *discordgo.InteractionCreate
 	channel           *discordgo.Channel
+	printer           *discord.Printer
 	inputHandler      *InputHandler
 }
 
@@ -146,16 +229,17 @@ func NewModifyEventRetryState(o discord.Options) *ModifyEventRetryState {
 		session:           o.Session,
 		interactionCreate: o.InteractionCreate,
 		channel:           o.Channel,
+		printer:           o.Printer,
 		inputHandler:      NewInputHandler(&o),
 	}
 }
 
 func (m *ModifyEventRetryState) OnState(ctx context.Context, e *fsm.Event) {
-	if _, err := m.session.ChannelMessageSend(m.channel.ID, discord.InvalidEntryText); err != nil {
+	if _, err := m.session.ChannelMessageSend(m.channel.ID, m.printer.T(discord.InvalidEntryText)); err != nil {
 		e.Err = err
 		return
 	}
-	if err := m.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.MenuOption, 60*time.Second); err != nil {
+	if err := m.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.MenuOption, promptTimeout); err != nil {
 		e.Err = err
 		return
 	}
@@ -201,11 +285,17 @@ func EditFieldSelect(e *fsm.Event) (string, error) {
 	}
 
 	opts := map[string]chatState{
-		"1": AddTitle,
-		"2": AddDescription,
-		"3": SetDate,
-		"4": SetDuration,
-		"5": SetLocation,
+		"1":  AddTitle,
+		"2":  AddDescription,
+		"3":  SetDate,
+		"4":  SetDuration,
+		"5":  SetLocation,
+		"6":  SetDeadline,
+		"7":  SetGuests,
+		"8":  SetCategory,
+		"9":  SetAttendeeLimit,
+		"10": SetOwner,
+		"11": SetChannel,
 	}
 	option, ok := opts[val.(string)]
 	if !ok {
@@ -213,3 +303,32 @@ func EditFieldSelect(e *fsm.Event) (string, error) {
 	}
 	return option.String(), nil
 }
+
+func printDeadline(deadline time.Time) string {
+	if deadline.IsZero() {
+		return ""
+	}
+	return deadline.In(time.Local).Format(util.HumanTimeFormat)
+}
+
+func printLimit(rg *role.RoleGroup) string {
+	if rg == nil || rg.GetLimit(role.AcceptedField) == 0 {
+		return ""
+	}
+	return strconv.Itoa(rg.GetLimit(role.AcceptedField))
+}
+
+// eventChannel is the channel the event is moved to when one was chosen, or the channel it is in
+func eventChannel(f *fsm.FSM, i *discordgo.InteractionCreate) string {
+	if val, found := f.Metadata(discord.ChannelID.String()); found && val != "" {
+		return fmt.Sprintf("%s", val)
+	}
+	return i.ChannelID
+}
+
+func printGuests(guests int) string {
+	if guests == 0 {
+		return ""
+	}
+	return strconv.Itoa(guests)
+}
@@ -285,3 +285,62 @@ func TestNewModifyEventRetryState(t *testing.T) {
 		})
 	}
 }
+
+func TestEditFieldSelect(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected string
+		isErr    bool
+	}{
+		{input: "8", expected: SetCategory.String()},
+		{input: "9", expected: SetAttendeeLimit.String()},
+		{input: "10", expected: SetOwner.String()},
+		{input: "11", expected: SetChannel.String()},
+		{input: "12", isErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.input, func(t *testing.T) {
+			f := fsm.NewFSM("idle", fsm.Events{}, fsm.Callbacks{})
+			f.SetMetadata(discord.MenuOption.String(), tc.input)
+			actual, err := EditFieldSelect(&fsm.Event{FSM: f})
+			if tc.isErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func Test_saveEventChanges(t *testing.T) {
+	rg := role.NewDefaultRoleGroup()
+	rg.SetLimit(role.AcceptedField, 2)
+	for _, user := range []string{"foo", "bar", "baz"} {
+		assert.NoError(t, rg.ToggleRole(role.AcceptedField, user))
+	}
+	event := discord.Event{Title: "event", Owner: "foo", RoleGroup: rg}
+
+	limit := role.NewDefaultRoleGroup()
+	limit.SetLimit(role.AcceptedField, 1)
+	f := fsm.NewFSM("idle", fsm.Events{}, fsm.Callbacks{})
+	f.SetMetadata(discord.Attendee.String(), limit)
+	f.SetMetadata(discord.Owner.String(), "")
+	assert.NoError(t, saveEventChanges(&fsm.Event{FSM: f}, &event))
+
+	assert.Equal(t, "foo", event.Owner)
+	assert.Equal(t, 1, event.RoleGroup.GetLimit(role.AcceptedField))
+	assert.Equal(t, []string{"foo"}, event.RoleGroup.GetUsers(role.AcceptedField))
+	assert.Equal(t, []string{"bar", "baz"}, event.RoleGroup.WaitlistUsers(role.AcceptedField))
+
+	f.SetMetadata(discord.Owner.String(), "bar")
+	f.SetMetadata(discord.Location.String(), "Seattle")
+	assert.NoError(t, saveEventChanges(&fsm.Event{FSM: f}, &event))
+	assert.Equal(t, "bar", event.Owner)
+	changes, _ := f.Metadata(discord.Changes.String())
+	assert.Equal(t, []discord.Change{
+		{Field: discord.LocationField, After: "Seattle"},
+		{Field: discord.OwnerField, Before: "foo", After: "bar"},
+	}, changes)
+}
@@ -0,0 +1,108 @@
+package states
+
+import (
+	"context"
+	"fmt"
+	"github.com/GuessWhoSamFoo/fsm"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/discord"
+	"github.com/bwmarrin/discordgo"
+	"strings"
+)
+
+type SetOwnerState struct {
+	session           *discordgo.Session
+	interactionCreate *discordgo.InteractionCreate
+	channel           *discordgo.Channel
+	printer           *discord.Printer
+
+	inputHandler *InputHandler
+}
+
+func NewSetOwnerState(o discord.Options) *SetOwnerState {
+	return &SetOwnerState{
+		session:           o.Session,
+		interactionCreate: o.InteractionCreate,
+		channel:           o.Channel,
+		printer:           o.Printer,
+		inputHandler:      NewInputHandler(&o),
+	}
+}
+
+func (o *SetOwnerState) OnState(ctx context.Context, e *fsm.Event) {
+	_, err := o.session.ChannelMessageSendEmbed(o.channel.ID, o.printer.Embed(&discord.EnterOwnerMessage))
+	if err != nil {
+		e.Err = err
+		return
+	}
+
+	if err = o.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.Owner, promptTimeout); err != nil {
+		e.Err = err
+		return
+	}
+	if err = validateOwner(o.session, e.FSM, o.interactionCreate.GuildID); err != nil {
+		eventErr := e.FSM.Event(ctx, SetOwnerRetry.String())
+		if eventErr != nil {
+			e.Err = fmt.Errorf("%v: %v", err, eventErr)
+			return
+		}
+	}
+}
+
+type SetOwnerRetryState struct {
+	session           *discordgo.Session
+	interactionCreate *discordgo.InteractionCreate
+	channel           *discordgo.Channel
+	printer           *discord.Printer
+
+	inputHandler *InputHandler
+}
+
+func NewSetOwnerRetryState(o discord.Options) *SetOwnerRetryState {
+	return &SetOwnerRetryState{
+		session:           o.Session,
+		interactionCreate: o.InteractionCreate,
+		channel:           o.Channel,
+		printer:           o.Printer,
+		inputHandler:      NewInputHandler(&o),
+	}
+}
+
+func (o *SetOwnerRetryState) OnState(ctx context.Context, e *fsm.Event) {
+	_, err := o.session.ChannelMessageSend(o.channel.ID, o.printer.T(discord.InvalidOwnerText))
+	if err != nil {
+		e.Err = err
+		return
+	}
+
+	if err = o.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.Owner, promptTimeout); err != nil {
+		e.Err = err
+		return
+	}
+	if err = validateOwner(o.session, e.FSM, o.interactionCreate.GuildID); err != nil {
+		eventErr := e.FSM.Event(ctx, SelfTransition.String())
+		if eventErr != nil {
+			e.Err = fmt.Errorf("%v: %v", err, eventErr)
+			return
+		}
+	}
+}
+
+// validateOwner gets the username of the guild member who takes over the event. An unknown member is cleared so the
+// organizer stays the same.
+func validateOwner(s *discordgo.Session, f *fsm.FSM, guildID string) error {
+	val, err := Get(f, discord.Owner)
+	if err != nil {
+		return err
+	}
+	f.SetMetadata(discord.Owner.String(), "")
+	name := strings.TrimPrefix(strings.TrimSpace(fmt.Sprintf("%v", val)), "@")
+	m, err := discord.FindMember(s, guildID, name)
+	if err != nil {
+		return err
+	}
+	if m == nil || m.User.Bot {
+		return fmt.Errorf("unknown member: %s", name)
+	}
+	f.SetMetadata(discord.Owner.String(), m.User.Username)
+	return nil
+}
@@ -0,0 +1,23 @@
+package states
+
+import (
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/discord"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewSetOwnerState(t *testing.T) {
+	opts, err := discord.NewMockOptions()
+	assert.NoError(t, err)
+
+	s := NewSetOwnerState(*opts)
+	assert.NotNil(t, s)
+}
+
+func TestNewSetOwnerRetryState(t *testing.T) {
+	opts, err := discord.NewMockOptions()
+	assert.NoError(t, err)
+
+	s := NewSetOwnerRetryState(*opts)
+	assert.NotNil(t, s)
+}
@@ -5,7 +5,15 @@ import (
 	"fmt"
 	"github.com/GuessWhoSamFoo/fsm"
 	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/discord"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/role"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/logging"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/metrics"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/pkg/util"
 	"github.com/bwmarrin/discordgo"
+	"golang.org/x/exp/slices"
+	"log/slog"
+	"strings"
+	"time"
 )
 
 type ProcessEditState struct {
@@ -19,36 +27,242 @@ func NewProcessEditState(o discord.Options) *ProcessEditState {
 }
 
 func (p *ProcessEditState) OnState(_ context.Context, e *fsm.Event) {
-	obj, err := Get(e.FSM, discord.EventObject)
+	guildID := p.Options.InteractionCreate.GuildID
+	before, event, err := p.saveEdit(e.FSM)
 	if err != nil {
 		e.Err = err
 		return
 	}
-	event, ok := obj.(discord.Event)
-	if !ok {
-		e.Err = fmt.Errorf("cannot get event")
-		return
-	}
-	embed, err := discord.ConvertEventToMessageEmbed(&event)
-	if err != nil {
+	if err = p.Options.UpdateEvent(event); err != nil {
 		e.Err = err
 		return
 	}
-	if _, err = p.Options.Session.ChannelMessageEditEmbed(p.Options.InteractionCreate.Interaction.ChannelID, p.Options.InteractionCreate.Interaction.Message.ID, embed); err != nil {
-		e.Err = err
-		return
+	if event.ScheduledEventID != "" {
+		if _, err = p.Options.Session.GuildScheduledEventEdit(guildID, event.ScheduledEventID, discord.ToGuildScheduledEventParams(event)); err != nil {
+			e.Err = fmt.Errorf("failed to update guild event: %v", err)
+			return
+		}
 	}
-	if err = p.Options.UpdateEvent(&event); err != nil {
-		e.Err = err
+	if err = event.PostToThread(p.Options.Session, discord.EventUpdatedMessage(p.Options.UserPrinter.For(""), event)); err != nil {
+		e.Err = fmt.Errorf("failed to post to thread: %v", err)
 		return
 	}
-	msg := p.Options.InteractionCreate.Interaction.Message
+	p.notifyChanges(before, event)
+	p.alertLateCancellation(e.FSM, before, event)
 	if _, err = p.Options.Session.ChannelMessageSendEmbed(p.Options.Channel.ID, &discordgo.MessageEmbed{
-		Title:       "Event has been updated!",
+		Title:       p.Options.Printer.T("Event has been updated!"),
 		Color:       discord.Purple,
-		Description: fmt.Sprintf("[Click here to view the event](https://discord.com/channels/%s/%s/%s)", p.Options.InteractionCreate.GuildID, p.Options.InteractionCreate.Interaction.ChannelID, msg.ID),
+		Description: p.Options.Printer.Tf("[Click here to view the event](%s)", event.DiscordLink),
 	}); err != nil {
 		e.Err = fmt.Errorf("failed to send message: %v", err)
 		return
 	}
+	// Messages are spaced out, so attendees are told after the organizer knows the edit was saved
+	p.notifyAttendees(e.FSM, event)
+	metrics.RecordFSMOutcome(EditAction, metrics.Completed)
+}
+
+// saveEdit makes the changes of the edit to the latest version of the event message, so signups made while the edit
+// was in progress are kept. Signups wait until the message is saved. It returns the event before and after the edit.
+func (p *ProcessEditState) saveEdit(f *fsm.FSM) (*discord.Event, *discord.Event, error) {
+	guildID := p.Options.InteractionCreate.GuildID
+	channelID, messageID := p.Options.InteractionCreate.Interaction.ChannelID, p.Options.InteractionCreate.Interaction.Message.ID
+	if p.Options.LockEvent != nil {
+		defer p.Options.LockEvent(messageID)()
+	}
+	latest, err := p.Options.Session.ChannelMessage(channelID, messageID)
+	if err != nil {
+		return nil, nil, err
+	}
+	before, err := discord.GetEventFromMessage(latest)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Parsed again so the changes made to the roles are not shared with the event before the edit
+	event, err := discord.GetEventFromMessage(latest)
+	if err != nil {
+		return nil, nil, err
+	}
+	before.DiscordLink = fmt.Sprintf("https://discord.com/channels/%s/%s/%s", guildID, channelID, messageID)
+	event.DiscordLink = before.DiscordLink
+	if err = applyEdit(f, event); err != nil {
+		return nil, nil, err
+	}
+
+	moveTo := eventChannel(f, p.Options.InteractionCreate)
+	if moveTo == channelID {
+		return before, event, discord.EditEventMessage(p.Options.Session, channelID, messageID, event)
+	}
+	msg, err := p.Options.Move(event, latest, guildID, moveTo)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to move event: %v", err)
+	}
+	p.removeMovedMessage(channelID, messageID, msg.ID, moveTo)
+	return before, event, nil
+}
+
+// applyEdit makes the changes of an edit to an event: the fields answered, the responses removed and the response
+// added. Responses are changed by name, so those made by others in the meantime stay as they are.
+func applyEdit(f *fsm.FSM, event *discord.Event) error {
+	applyEventChanges(f, event)
+	if val, found := f.Metadata(discord.Removed.String()); found {
+		names, _ := val.([]string)
+		for _, name := range names {
+			if err := event.RoleGroup.RemoveFromAllLists(name); err != nil {
+				return err
+			}
+		}
+		if _, err := event.PromoteFromWaitlists(); err != nil {
+			return err
+		}
+	}
+	if val, found := f.Metadata(discord.Response.String()); found {
+		field, _ := val.(role.FieldType)
+		user, err := Get(f, discord.Username)
+		if err != nil {
+			return err
+		}
+		name := fmt.Sprintf("%v", user)
+		// Toggling the role the user already has would remove them instead
+		if event.RoleGroup.GetResponse(name).Field == field {
+			return nil
+		}
+		if _, err = event.ToggleResponse(field, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeMovedMessage deletes the message of an event which was posted to another channel and moves its records to
+// the new message
+func (p *ProcessEditState) removeMovedMessage(channelID, messageID, newMessageID, newChannelID string) {
+	if err := p.Options.Session.ChannelMessageDelete(channelID, messageID); err != nil {
+		slog.Warn("cannot delete moved event message", logging.EventKey, logging.EventPath(channelID, messageID), logging.ErrorKey, err)
+	}
+	if p.Options.Store == nil {
+		return
+	}
+	if err := p.Options.Store.MoveEvent(messageID, newChannelID, newMessageID); err != nil {
+		slog.Warn("cannot move event records", logging.EventKey, logging.EventPath(newChannelID, newMessageID), logging.ErrorKey, err)
+	}
+}
+
+// notifyChanges messages the members affected by an edit: the new organizer and the users moved on or off the
+// waitlist. The event thread is kept in step with the accepted list. Members who cannot be messaged do not fail the
+// edit.
+func (p *ProcessEditState) notifyChanges(before, after *discord.Event) {
+	s, guildID := p.Options.Session, p.Options.InteractionCreate.GuildID
+	if after.Owner != before.Owner && after.Owner != p.Options.InteractionCreate.Member.User.Username {
+		if err := discord.NotifyNewOwner(s, p.Options.UserPrinter, guildID, after); err != nil {
+			slog.Warn("cannot tell new organizer", logging.EventKey, after.DiscordLink, logging.ErrorKey, err)
+		}
+	}
+	for _, name := range responders(before, after) {
+		was, is := before.RoleGroup.GetResponse(name), after.RoleGroup.GetResponse(name)
+		var err error
+		switch {
+		case was.Field == role.AcceptedField && was.Waitlist > 0 && discord.InThread(is):
+			err = after.NotifyUserOffWaitlist(s, p.Options.UserPrinter, guildID, name)
+		case discord.InThread(was) && is.Field == role.AcceptedField && is.Waitlist > 0:
+			err = after.NotifyUserOnWaitlist(s, p.Options.UserPrinter, guildID, name)
+		default:
+			err = after.SyncThreadMemberByName(s, guildID, name, was, is)
+		}
+		if err != nil {
+			slog.Warn("cannot tell user about response change", logging.UserKey, name, logging.EventKey, after.DiscordLink, logging.ErrorKey, err)
+		}
+	}
+}
+
+// alertLateCancellation tells the organizer when someone else removed accepted members shortly before the start
+func (p *ProcessEditState) alertLateCancellation(f *fsm.FSM, before, after *discord.Event) {
+	val, _ := f.Metadata(discord.Removed.String())
+	names, _ := val.([]string)
+	var dropped []string
+	for _, name := range names {
+		if discord.InThread(before.RoleGroup.GetResponse(name)) {
+			dropped = append(dropped, name)
+		}
+	}
+	i := p.Options.InteractionCreate
+	if len(dropped) == 0 || i.Member.User.Username == after.Owner || !after.StartsWithin(time.Now(), p.Options.LateCancelAlerts) {
+		return
+	}
+	var promoted []string
+	for _, name := range after.RoleGroup.GetUsers(role.AcceptedField) {
+		if before.RoleGroup.GetResponse(name).Waitlist > 0 {
+			promoted = append(promoted, name)
+		}
+	}
+	if err := discord.NotifyLateCancellation(p.Options.Session, p.Options.Store, p.Options.UserPrinter, i.GuildID, discord.LateCancellation{
+		Event:     after,
+		ChannelID: i.ChannelID,
+		MessageID: i.Message.ID,
+		Dropped:   dropped,
+		Promoted:  promoted,
+	}); err != nil {
+		slog.Warn("cannot alert organizer about late cancellation", logging.EventKey, after.DiscordLink, logging.ErrorKey, err)
+	}
+}
+
+// responders are the users with a response or waitlist spot in either version of an event
+func responders(events ...*discord.Event) []string {
+	var names []string
+	add := func(users []string) {
+		for _, name := range users {
+			if !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	for _, e := range events {
+		for _, r := range e.RoleGroup.Roles {
+			add(r.Users)
+		}
+		for _, wl := range e.RoleGroup.Waitlist {
+			add(wl.Users)
+		}
+	}
+	return names
+}
+
+// notifyAttendees messages the accepted and tentative members about a new time or location, unless the organizer chose
+// not to, and reports who was reached. The member making the edit is not messaged.
+func (p *ProcessEditState) notifyAttendees(f *fsm.FSM, event *discord.Event) {
+	changes := discord.AttendeeChanges(editChanges(f))
+	if notify, found := f.Metadata(discord.Notify.String()); len(changes) == 0 || (found && notify == false) {
+		return
+	}
+	_, channelID, messageID, err := util.GetIDsFromDiscordLink(event.DiscordLink)
+	if err != nil {
+		slog.Warn("cannot tell attendees about changes", logging.EventKey, event.DiscordLink, logging.ErrorKey, err)
+		return
+	}
+	text := make([]string, 0, len(changes))
+	for _, c := range changes {
+		text = append(text, c.String())
+	}
+	editor := p.Options.InteractionCreate.Member.User.Username
+	report, err := discord.Announce(p.Options.Session, p.Options.Store, p.Options.UserPrinter, p.Options.InteractionCreate.GuildID, discord.Announcement{
+		Event:     event,
+		ChannelID: channelID,
+		MessageID: messageID,
+		Author:    editor,
+		Text:      strings.Join(text, "\n"),
+		Fields:    []role.FieldType{role.AcceptedField, role.TentativeField},
+		Except:    editor,
+		Embed: func(printer *discord.Printer) *discordgo.MessageEmbed {
+			return discord.EventChangedEmbed(printer, event, changes)
+		},
+	})
+	if err != nil {
+		slog.Warn("cannot tell attendees about changes", logging.EventKey, event.DiscordLink, logging.ErrorKey, err)
+	}
+	if len(report.Delivered)+len(report.Mentioned)+len(report.Failed) == 0 {
+		return
+	}
+	if _, err = p.Options.Session.ChannelMessageSendEmbed(p.Options.Channel.ID, discord.DeliveryReportEmbed(p.Options.Printer, report)); err != nil {
+		slog.Warn("cannot send delivery report", logging.EventKey, event.DiscordLink, logging.ErrorKey, err)
+	}
 }
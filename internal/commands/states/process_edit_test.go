@@ -1,7 +1,9 @@
 package states
 
 import (
+	"github.com/GuessWhoSamFoo/fsm"
 	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/discord"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/role"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -12,3 +14,69 @@ func TestNewProcessEditState(t *testing.T) {
 	s := NewProcessEditState(*opts)
 	assert.NotNil(t, s)
 }
+
+func Test_applyEdit(t *testing.T) {
+	cases := []struct {
+		name      string
+		metadata  map[discord.MetadataKey]interface{}
+		title     string
+		accepted  []string
+		tentative []string
+		waitlist  []string
+	}{
+		{
+			name:     "fields keep signups made during the edit",
+			metadata: map[discord.MetadataKey]interface{}{discord.Title: "new title"},
+			title:    "new title",
+			accepted: []string{"leo", "mia", "sam"},
+			waitlist: []string{"zoe"},
+		},
+		{
+			name:     "removed response is filled from the waitlist",
+			metadata: map[discord.MetadataKey]interface{}{discord.Removed: []string{"leo"}},
+			accepted: []string{"mia", "sam", "zoe"},
+		},
+		{
+			name: "added response",
+			metadata: map[discord.MetadataKey]interface{}{
+				discord.Username: "ana",
+				discord.Response: role.TentativeField,
+			},
+			accepted:  []string{"leo", "mia", "sam"},
+			tentative: []string{"ana"},
+			waitlist:  []string{"zoe"},
+		},
+		{
+			name: "added response the user already has",
+			metadata: map[discord.MetadataKey]interface{}{
+				discord.Username: "mia",
+				discord.Response: role.AcceptedField,
+			},
+			accepted: []string{"leo", "mia", "sam"},
+			waitlist: []string{"zoe"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rg := role.NewDefaultRoleGroup()
+			rg.SetLimit(role.AcceptedField, 3)
+			for _, name := range []string{"leo", "mia", "sam", "zoe"} {
+				assert.NoError(t, rg.ToggleRole(role.AcceptedField, name))
+			}
+			event := &discord.Event{Title: "event", RoleGroup: rg}
+			f := fsm.NewFSM("idle", fsm.Events{}, fsm.Callbacks{})
+			for k, v := range tc.metadata {
+				f.SetMetadata(k.String(), v)
+			}
+
+			assert.NoError(t, applyEdit(f, event))
+			if tc.title == "" {
+				tc.title = "event"
+			}
+			assert.Equal(t, tc.title, event.Title)
+			assert.ElementsMatch(t, tc.accepted, event.RoleGroup.GetUsers(role.AcceptedField))
+			assert.ElementsMatch(t, tc.tentative, event.RoleGroup.GetUsers(role.TentativeField))
+			assert.ElementsMatch(t, tc.waitlist, event.RoleGroup.WaitlistUsers(role.AcceptedField))
+		})
+	}
+}
@@ -10,13 +10,13 @@ import (
 	"github.com/bwmarrin/discordgo"
 	"strconv"
 	"strings"
-	"time"
 )
 
 type RemoveResponseState struct {
 	session           *discordgo.Session
 	interactionCreate *discordgo.InteractionCreate
 	channel           *discordgo.Channel
+	printer           *discord.Printer
 
 	inputHandler *InputHandler
 }
@@ -26,6 +26,7 @@ func NewRemoveResponseState(o discord.Options) *RemoveResponseState {
 		session:           o.Session,
 		interactionCreate: o.InteractionCreate,
 		channel:           o.Channel,
+		printer:           o.Printer,
 		inputHandler:      NewInputHandler(&o),
 	}
 }
@@ -47,9 +48,9 @@ func (r *RemoveResponseState) OnState(ctx context.Context, e *fsm.Event) {
 		count += len(rol.Users)
 	}
 	if count == 0 {
-		_, err = r.session.ChannelMessageSendEmbed(r.channel.ID, &discordgo.MessageEmbed{
+		_, err = r.session.ChannelMessageSendEmbed(r.channel.ID, r.printer.Embed(&discordgo.MessageEmbed{
 			Title: "Event doesn't have any responses",
-		})
+		}))
 		if err != nil {
 			e.Err = err
 			return
@@ -73,14 +74,14 @@ func (r *RemoveResponseState) OnState(ctx context.Context, e *fsm.Event) {
 			desc = desc + fmt.Sprintf("**%d**⠀%s %s\n", counter, r.Icon, n)
 		}
 	}
-	if _, err = r.session.ChannelMessageSendEmbed(r.channel.ID, &discordgo.MessageEmbed{
+	if _, err = r.session.ChannelMessageSendEmbed(r.channel.ID, r.printer.Embed(&discordgo.MessageEmbed{
 		Title:       "Which responses would you like to remove?",
 		Description: desc,
 		Color:       discord.Purple,
 		Footer: &discordgo.MessageEmbedFooter{
 			Text: "Enter the number(s) of the desired option(s), separated by spaces\n" + discord.CancelText,
 		},
-	}); err != nil {
+	})); err != nil {
 		e.Err = err
 		return
 	}
@@ -95,7 +96,7 @@ func (r *RemoveResponseState) OnState(ctx context.Context, e *fsm.Event) {
 		nameMap[index+1] = name
 	}
 
-	if err = r.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.MenuOption, 60*time.Second); err != nil {
+	if err = r.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.MenuOption, promptTimeout); err != nil {
 		e.Err = err
 		return
 	}
@@ -110,31 +111,15 @@ func (r *RemoveResponseState) OnState(ctx context.Context, e *fsm.Event) {
 		return
 	}
 
-	for _, n := range names {
-		if err = event.RemoveFromAllLists(r.session, r.interactionCreate, n); err != nil {
-			e.Err = err
-			return
-		}
-	}
-
-	offWaitlistUsers, err := event.PromoteFromWaitlists()
-	if err != nil {
-		e.Err = err
-		return
-	}
-
-	for _, u := range offWaitlistUsers {
-		if err = event.NotifyUserOffWaitlist(r.session, r.interactionCreate.Interaction, u); err != nil {
-			e.Err = err
-			return
-		}
-	}
+	// The responses are removed from the latest version of the event once the edit is processed
+	e.FSM.SetMetadata(discord.Removed.String(), names)
 }
 
 type RemoveResponseRetryState struct {
 	session           *discordgo.Session
 	interactionCreate *discordgo.InteractionCreate
 	channel           *discordgo.Channel
+	printer           *discord.Printer
 
 	inputHandler *InputHandler
 }
@@ -144,13 +129,14 @@ func NewRemoveResponseRetryState(o discord.Options) *RemoveResponseRetryState {
 		session:           o.Session,
 		interactionCreate: o.InteractionCreate,
 		channel:           o.Channel,
+		printer:           o.Printer,
 
 		inputHandler: NewInputHandler(&o),
 	}
 }
 
 func (r *RemoveResponseRetryState) OnState(ctx context.Context, e *fsm.Event) {
-	if _, err := r.session.ChannelMessageSend(r.channel.ID, discord.InvalidRemoveResponseText); err != nil {
+	if _, err := r.session.ChannelMessageSend(r.channel.ID, r.printer.T(discord.InvalidRemoveResponseText)); err != nil {
 		e.Err = err
 		return
 	}
@@ -183,7 +169,7 @@ func (r *RemoveResponseRetryState) OnState(ctx context.Context, e *fsm.Event) {
 		nameMap[index+1] = name
 	}
 
-	if err = r.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.MenuOption, 60*time.Second); err != nil {
+	if err = r.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.MenuOption, promptTimeout); err != nil {
 		e.Err = err
 		return
 	}
@@ -198,25 +184,8 @@ func (r *RemoveResponseRetryState) OnState(ctx context.Context, e *fsm.Event) {
 		return
 	}
 
-	for _, n := range names {
-		if err = event.RemoveFromAllLists(r.session, r.interactionCreate, n); err != nil {
-			e.Err = err
-			return
-		}
-	}
-
-	offWaitlistUsers, err := event.PromoteFromWaitlists()
-	if err != nil {
-		e.Err = err
-		return
-	}
-
-	for _, u := range offWaitlistUsers {
-		if err = event.NotifyUserOffWaitlist(r.session, r.interactionCreate.Interaction, u); err != nil {
-			e.Err = err
-			return
-		}
-	}
+	// The responses are removed from the latest version of the event once the edit is processed
+	e.FSM.SetMetadata(discord.Removed.String(), names)
 }
 
 func selectMultiple(e *fsm.Event, nameMap map[int]string) ([]string, error) {
@@ -0,0 +1,128 @@
+package states
+
+import (
+	"context"
+	"fmt"
+	"github.com/GuessWhoSamFoo/fsm"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/discord"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/store"
+	"github.com/bwmarrin/discordgo"
+	"strconv"
+	"strings"
+)
+
+// ResumeDraftState offers to finish a saved draft instead of starting a new event
+type ResumeDraftState struct {
+	session           *discordgo.Session
+	interactionCreate *discordgo.InteractionCreate
+	channel           *discordgo.Channel
+	printer           *discord.Printer
+	store             *store.Store
+	categories        []discord.Category
+
+	inputHandler *InputHandler
+}
+
+func NewResumeDraftState(o discord.Options) *ResumeDraftState {
+	return &ResumeDraftState{
+		session:           o.Session,
+		interactionCreate: o.InteractionCreate,
+		channel:           o.Channel,
+		printer:           o.Printer,
+		store:             o.Store,
+		categories:        o.Categories,
+		inputHandler:      NewInputHandler(&o),
+	}
+}
+
+func (r *ResumeDraftState) OnState(ctx context.Context, e *fsm.Event) {
+	drafts := savedDrafts(r.store.Drafts(r.interactionCreate.GuildID, r.interactionCreate.Member.User.ID))
+	if len(drafts) == 0 {
+		return
+	}
+	if _, err := r.session.ChannelMessageSendEmbed(r.channel.ID, r.printer.Embed(discord.ResumeDraftMessage(drafts))); err != nil {
+		e.Err = err
+		return
+	}
+
+	if err := r.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.MenuOption, promptTimeout); err != nil {
+		e.Err = err
+		return
+	}
+	if err := resumeDraft(e.FSM, drafts, r.categories); err != nil {
+		eventErr := e.FSM.Event(ctx, ResumeDraftRetry.String())
+		if eventErr != nil {
+			e.Err = fmt.Errorf("%v: %v", err, eventErr)
+			return
+		}
+	}
+}
+
+type ResumeDraftRetryState struct {
+	session           *discordgo.Session
+	interactionCreate *discordgo.InteractionCreate
+	channel           *discordgo.Channel
+	printer           *discord.Printer
+	store             *store.Store
+	categories        []discord.Category
+
+	inputHandler *InputHandler
+}
+
+func NewResumeDraftRetryState(o discord.Options) *ResumeDraftRetryState {
+	return &ResumeDraftRetryState{
+		session:           o.Session,
+		interactionCreate: o.InteractionCreate,
+		channel:           o.Channel,
+		printer:           o.Printer,
+		store:             o.Store,
+		categories:        o.Categories,
+		inputHandler:      NewInputHandler(&o),
+	}
+}
+
+func (r *ResumeDraftRetryState) OnState(ctx context.Context, e *fsm.Event) {
+	if _, err := r.session.ChannelMessageSend(r.channel.ID, r.printer.T(discord.InvalidEntryText)); err != nil {
+		e.Err = err
+		return
+	}
+	if err := r.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.MenuOption, promptTimeout); err != nil {
+		e.Err = err
+		return
+	}
+	drafts := savedDrafts(r.store.Drafts(r.interactionCreate.GuildID, r.interactionCreate.Member.User.ID))
+	if err := resumeDraft(e.FSM, drafts, r.categories); err != nil {
+		eventErr := e.FSM.Event(ctx, SelfTransition.String())
+		if eventErr != nil {
+			e.Err = fmt.Errorf("%v: %v", err, eventErr)
+			return
+		}
+	}
+}
+
+// savedDrafts are the drafts waiting for the organizer to finish them. Scheduled posts are managed with /drafts.
+func savedDrafts(drafts []store.Draft) []store.Draft {
+	var result []store.Draft
+	for _, d := range drafts {
+		if !d.Scheduled() {
+			result = append(result, d)
+		}
+	}
+	return result
+}
+
+// resumeDraft loads the chosen draft. The first option starts a new event and loads nothing.
+func resumeDraft(f *fsm.FSM, drafts []store.Draft, categories []discord.Category) error {
+	val, err := Get(f, discord.MenuOption)
+	if err != nil {
+		return err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(fmt.Sprintf("%v", val)))
+	if err != nil || !InRange(n, len(drafts)+1, 1) {
+		return fmt.Errorf("invalid draft: %v", val)
+	}
+	if n > 1 {
+		discord.LoadDraft(f, drafts[n-2], categories)
+	}
+	return nil
+}
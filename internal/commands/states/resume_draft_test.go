@@ -0,0 +1,122 @@
+package states
+
+import (
+	"context"
+	"github.com/GuessWhoSamFoo/fsm"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/discord"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/store"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewResumeDraftState(t *testing.T) {
+	opts, err := discord.NewMockOptions()
+	assert.NoError(t, err)
+
+	s := NewResumeDraftState(*opts)
+	assert.NotNil(t, s)
+}
+
+func TestResumeDraftState_OnState(t *testing.T) {
+	opts, err := discord.NewMockOptions()
+	assert.NoError(t, err)
+	opts.InteractionCreate.Interaction.Member.User.ID = "user"
+	opts.Categories = discord.DefaultCategories
+	start := time.Now().Add(time.Hour)
+
+	cases := []struct {
+		name          string
+		drafts        []store.Draft
+		input         string
+		expectedState string
+		expectedTitle string
+	}{
+		{
+			name:          "no drafts",
+			expectedState: ResumeDraft.String(),
+		},
+		{
+			name:          "new event",
+			drafts:        []store.Draft{{ID: "1", GuildID: opts.InteractionCreate.GuildID, OwnerID: "user", Title: "draft", Start: start}},
+			input:         "1",
+			expectedState: ResumeDraft.String(),
+		},
+		{
+			name:          "resume",
+			drafts:        []store.Draft{{ID: "1", GuildID: opts.InteractionCreate.GuildID, OwnerID: "user", Title: "draft", Start: start, Category: "social"}},
+			input:         "2",
+			expectedState: ResumeDraft.String(),
+			expectedTitle: "draft",
+		},
+		{
+			name:          "scheduled posts are not offered",
+			drafts:        []store.Draft{{ID: "1", GuildID: opts.InteractionCreate.GuildID, OwnerID: "user", Title: "draft", Start: start, PublishAt: start.Add(-time.Minute)}},
+			expectedState: ResumeDraft.String(),
+		},
+		{
+			name:          "invalid",
+			drafts:        []store.Draft{{ID: "1", GuildID: opts.InteractionCreate.GuildID, OwnerID: "user", Title: "draft", Start: start}},
+			input:         "3",
+			expectedState: ResumeDraftRetry.String(),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			opts.Store, err = store.Open(filepath.Join(t.TempDir(), "store.json"))
+			assert.NoError(t, err)
+			for _, d := range tc.drafts {
+				assert.NoError(t, opts.Store.SaveDraft(d))
+			}
+
+			r := NewResumeDraftState(*opts)
+			f := fsm.NewFSM(
+				"idle",
+				fsm.Events{
+					{
+						Name: ResumeDraft.String(),
+						Src:  []string{"idle"},
+						Dst:  ResumeDraft.String(),
+					},
+					{
+						Name: ResumeDraftRetry.String(),
+						Src:  []string{ResumeDraft.String()},
+						Dst:  ResumeDraftRetry.String(),
+					},
+				},
+				fsm.Callbacks{
+					ResumeDraft.String(): r.OnState,
+				},
+			)
+			r.inputHandler.handlerFunc = func(session *discordgo.Session, create *discordgo.MessageCreate) {
+				r.inputHandler.inputChan <- tc.input
+			}
+			var wg sync.WaitGroup
+			if tc.input != "" {
+				wg.Add(1)
+				go func() {
+					r.inputHandler.handlerFunc(opts.Session, &discordgo.MessageCreate{})
+					wg.Done()
+				}()
+			}
+			assert.NoError(t, f.Event(context.TODO(), ResumeDraft.String()))
+			wg.Wait()
+			assert.Equal(t, tc.expectedState, f.Current())
+
+			_, resumed := f.Metadata(discord.DraftID.String())
+			assert.Equal(t, tc.expectedTitle != "", resumed)
+			if !resumed {
+				return
+			}
+			event, err := discord.FromFSMToEvent(f)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedTitle, event.Title)
+			assert.Equal(t, "Social", event.Category.Name)
+			assert.True(t, start.Equal(event.Start))
+		})
+	}
+}
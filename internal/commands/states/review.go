@@ -0,0 +1,402 @@
+package states
+
+import (
+	"context"
+	"fmt"
+	"github.com/GuessWhoSamFoo/fsm"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/discord"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/logging"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/metrics"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/store"
+	"github.com/bwmarrin/discordgo"
+	"log/slog"
+	"time"
+)
+
+// ReviewEventState previews the event post before it is published
+type ReviewEventState struct {
+	session           *discordgo.Session
+	interactionCreate *discordgo.InteractionCreate
+	channel           *discordgo.Channel
+	printer           *discord.Printer
+
+	inputHandler *InputHandler
+}
+
+func NewReviewEventState(o discord.Options) *ReviewEventState {
+	return &ReviewEventState{
+		session:           o.Session,
+		interactionCreate: o.InteractionCreate,
+		channel:           o.Channel,
+		printer:           o.Printer,
+		inputHandler:      NewInputHandler(&o),
+	}
+}
+
+func (r *ReviewEventState) OnState(ctx context.Context, e *fsm.Event) {
+	event, err := discord.FromFSMToEvent(e.FSM)
+	if err != nil {
+		e.Err = err
+		return
+	}
+	embed, err := discord.ConvertEventToMessageEmbed(event)
+	if err != nil {
+		e.Err = err
+		return
+	}
+	// Kept so a start time the member rejects falls back to the end shown here
+	e.FSM.SetMetadata(discord.EventObject.String(), *event)
+
+	if _, err = r.session.ChannelMessageSendComplex(r.channel.ID, &discordgo.MessageSend{
+		Embeds: []*discordgo.MessageEmbed{r.printer.Embed(embed), r.printer.Embed(discord.ReviewEventMessage(publishTime(e.FSM)))},
+	}); err != nil {
+		e.Err = err
+		return
+	}
+
+	if err = r.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.MenuOption, promptTimeout); err != nil {
+		e.Err = err
+		return
+	}
+	state, err := ReviewSelect(e)
+	if err != nil {
+		eventErr := e.FSM.Event(ctx, ReviewEventRetry.String())
+		if eventErr != nil {
+			e.Err = fmt.Errorf("%v: %v", err, eventErr)
+			return
+		}
+		return
+	}
+	if err = leaveReview(ctx, e, r.session, r.channel, r.printer, state); err != nil {
+		e.Err = err
+		return
+	}
+}
+
+type ReviewEventRetryState struct {
+	session           *discordgo.Session
+	interactionCreate *discordgo.InteractionCreate
+	channel           *discordgo.Channel
+	printer           *discord.Printer
+
+	inputHandler *InputHandler
+}
+
+func NewReviewEventRetryState(o discord.Options) *ReviewEventRetryState {
+	return &ReviewEventRetryState{
+		session:           o.Session,
+		interactionCreate: o.InteractionCreate,
+		channel:           o.Channel,
+		printer:           o.Printer,
+		inputHandler:      NewInputHandler(&o),
+	}
+}
+
+func (r *ReviewEventRetryState) OnState(ctx context.Context, e *fsm.Event) {
+	if _, err := r.session.ChannelMessageSend(r.channel.ID, r.printer.T(discord.InvalidEntryText)); err != nil {
+		e.Err = err
+		return
+	}
+	if err := r.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.MenuOption, promptTimeout); err != nil {
+		e.Err = err
+		return
+	}
+	state, err := ReviewSelect(e)
+	if err != nil {
+		eventErr := e.FSM.Event(ctx, SelfTransition.String())
+		if eventErr != nil {
+			e.Err = fmt.Errorf("%v: %v", err, eventErr)
+			return
+		}
+		return
+	}
+	if err = leaveReview(ctx, e, r.session, r.channel, r.printer, state); err != nil {
+		e.Err = err
+		return
+	}
+}
+
+func ReviewSelect(e *fsm.Event) (string, error) {
+	val, err := Get(e.FSM, discord.MenuOption)
+	if err != nil {
+		return "", err
+	}
+
+	opts := map[string]chatState{
+		"1": CreateEvent,
+		"2": ReviewField,
+		"3": SaveDraft,
+		"4": SchedulePublish,
+	}
+	option, ok := opts[val.(string)]
+	if !ok {
+		return "", fmt.Errorf("cannot find %s response", e.FSM.Current())
+	}
+	return option.String(), nil
+}
+
+// leaveReview moves on from the preview. A start time which passed while the event was a draft is asked for again
+// before publishing or scheduling.
+func leaveReview(ctx context.Context, e *fsm.Event, s *discordgo.Session, c *discordgo.Channel, p *discord.Printer, state string) error {
+	if state == CreateEvent.String() || state == SchedulePublish.String() {
+		start, _ := Get(e.FSM, discord.StartTime)
+		if startTime, ok := start.(time.Time); ok && startTime.Before(time.Now()) {
+			if _, err := s.ChannelMessageSend(c.ID, p.T(discord.InvalidEventTimeText)); err != nil {
+				return err
+			}
+			return changeField(ctx, e, SetDate.String())
+		}
+	}
+	return e.FSM.Event(ctx, state)
+}
+
+// ReviewFieldState goes back to a step of the creation DMs before showing the preview again
+type ReviewFieldState struct {
+	session           *discordgo.Session
+	interactionCreate *discordgo.InteractionCreate
+	channel           *discordgo.Channel
+	printer           *discord.Printer
+
+	inputHandler *InputHandler
+}
+
+func NewReviewFieldState(o discord.Options) *ReviewFieldState {
+	return &ReviewFieldState{
+		session:           o.Session,
+		interactionCreate: o.InteractionCreate,
+		channel:           o.Channel,
+		printer:           o.Printer,
+		inputHandler:      NewInputHandler(&o),
+	}
+}
+
+func (r *ReviewFieldState) OnState(ctx context.Context, e *fsm.Event) {
+	if _, err := r.session.ChannelMessageSendEmbed(r.channel.ID, r.printer.Embed(&discord.ReviewFieldMessage)); err != nil {
+		e.Err = err
+		return
+	}
+	if err := r.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.MenuOption, promptTimeout); err != nil {
+		e.Err = err
+		return
+	}
+	state, err := ReviewFieldSelect(e)
+	if err != nil {
+		eventErr := e.FSM.Event(ctx, ReviewFieldRetry.String())
+		if eventErr != nil {
+			e.Err = fmt.Errorf("%v: %v", err, eventErr)
+			return
+		}
+		return
+	}
+	if err = changeField(ctx, e, state); err != nil {
+		e.Err = err
+		return
+	}
+}
+
+type ReviewFieldRetryState struct {
+	session           *discordgo.Session
+	interactionCreate *discordgo.InteractionCreate
+	channel           *discordgo.Channel
+	printer           *discord.Printer
+
+	inputHandler *InputHandler
+}
+
+func NewReviewFieldRetryState(o discord.Options) *ReviewFieldRetryState {
+	return &ReviewFieldRetryState{
+		session:           o.Session,
+		interactionCreate: o.InteractionCreate,
+		channel:           o.Channel,
+		printer:           o.Printer,
+		inputHandler:      NewInputHandler(&o),
+	}
+}
+
+func (r *ReviewFieldRetryState) OnState(ctx context.Context, e *fsm.Event) {
+	if _, err := r.session.ChannelMessageSend(r.channel.ID, r.printer.T(discord.InvalidEntryText)); err != nil {
+		e.Err = err
+		return
+	}
+	if err := r.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.MenuOption, promptTimeout); err != nil {
+		e.Err = err
+		return
+	}
+	state, err := ReviewFieldSelect(e)
+	if err != nil {
+		eventErr := e.FSM.Event(ctx, SelfTransition.String())
+		if eventErr != nil {
+			e.Err = fmt.Errorf("%v: %v", err, eventErr)
+			return
+		}
+		return
+	}
+	if err = changeField(ctx, e, state); err != nil {
+		e.Err = err
+		return
+	}
+}
+
+func ReviewFieldSelect(e *fsm.Event) (string, error) {
+	val, err := Get(e.FSM, discord.MenuOption)
+	if err != nil {
+		return "", err
+	}
+
+	opts := map[string]chatState{
+		"1": AddTitle,
+		"2": AddDescription,
+		"3": SetAttendeeLimit,
+		"4": SetDate,
+		"5": SetDuration,
+		"6": SetLocation,
+		"7": SetDeadline,
+		"8": SetCategory,
+	}
+	option, ok := opts[val.(string)]
+	if !ok {
+		return "", fmt.Errorf("cannot find %s response", e.FSM.Current())
+	}
+	return option.String(), nil
+}
+
+// changeField asks for a field again then shows the updated preview
+func changeField(ctx context.Context, e *fsm.Event, state string) error {
+	obj, err := Get(e.FSM, discord.EventObject)
+	if err != nil {
+		return err
+	}
+	before, ok := obj.(discord.Event)
+	if !ok {
+		return fmt.Errorf("cannot get event")
+	}
+	if err = e.FSM.Event(ctx, state); err != nil {
+		return err
+	}
+	if state == SetDate.String() {
+		keepDuration(e.FSM, before)
+	}
+	return e.FSM.Event(ctx, ReviewEvent.String())
+}
+
+// keepDuration moves the end time along with a new start time, unless a new end time was given with it
+func keepDuration(f *fsm.FSM, before discord.Event) {
+	if before.End.IsZero() {
+		return
+	}
+	start, _ := Get(f, discord.StartTime)
+	end, _ := Get(f, discord.Duration)
+	startTime, ok := start.(time.Time)
+	if !ok || startTime.Equal(before.Start) {
+		return
+	}
+	if endTime, ok := end.(time.Time); ok && !endTime.Equal(before.End) {
+		return
+	}
+	f.SetMetadata(discord.Duration.String(), startTime.Add(before.End.Sub(before.Start)))
+}
+
+// SaveDraftState keeps the event being created in the store so the organizer can finish it later
+type SaveDraftState struct {
+	*discord.Options
+}
+
+func NewSaveDraftState(o discord.Options) *SaveDraftState {
+	return &SaveDraftState{
+		&o,
+	}
+}
+
+func (s *SaveDraftState) OnState(_ context.Context, e *fsm.Event) {
+	// Saving a scheduled post keeps its schedule
+	d, err := saveDraft(s.Options, e.FSM, publishTime(e.FSM))
+	if err != nil {
+		e.Err = err
+		return
+	}
+
+	msg, outcome := &discord.DraftSavedMessage, metrics.Drafted
+	if d.Scheduled() {
+		msg, outcome = discord.PostScheduledMessage(d.PublishAt), metrics.Scheduled
+	}
+	if _, err = s.Session.ChannelMessageSendEmbed(s.Channel.ID, s.Printer.Embed(msg)); err != nil {
+		e.Err = err
+		return
+	}
+	metrics.RecordFSMOutcome(CreateAction, outcome)
+	slog.Info("Saved event draft", logging.EventKey, d.Title, logging.UserKey, d.OwnerID, "scheduled", d.Scheduled())
+}
+
+// saveDraft keeps the event being created in the store, replacing the draft it was resumed from. A draft with a
+// publish time is posted by the scheduler to the channel the event would be posted to now.
+func saveDraft(o *discord.Options, f *fsm.FSM, publishAt time.Time) (store.Draft, error) {
+	if o.Store == nil {
+		return store.Draft{}, fmt.Errorf("draft store is not configured")
+	}
+	event, err := discord.FromFSMToEvent(f)
+	if err != nil {
+		return store.Draft{}, err
+	}
+
+	d := discord.ToDraft(event)
+	d.ID = o.InteractionCreate.ID
+	if id, found := f.Metadata(discord.DraftID.String()); found {
+		d.ID = fmt.Sprint(id)
+	}
+	d.OwnerID = o.InteractionCreate.Member.User.ID
+	d.ChannelID = chosenChannel(o, f)
+	d.Saved = time.Now()
+	if !publishAt.IsZero() {
+		o.ChannelID = d.ChannelID
+		d.PublishAt = publishAt
+		d.PostChannelID = o.EventChannel(event.Category.Name)
+		if err = discord.CheckCanPost(o.Session, d.PostChannelID); err != nil {
+			keepAsDraft(o, f)
+			return store.Draft{}, err
+		}
+		if o.Printer != nil && o.Printer.Localizer != nil {
+			d.Locale = string(o.Printer.Localizer.Locale)
+		}
+	}
+	if err = o.Store.SaveDraft(d); err != nil {
+		return store.Draft{}, fmt.Errorf("cannot save draft: %w", err)
+	}
+	return d, nil
+}
+
+// keepAsDraft saves the event being created without a schedule when it cannot be posted, such as to a channel the bot
+// cannot post in, so the organizer can fix it later without answering every question again
+func keepAsDraft(o *discord.Options, f *fsm.FSM) {
+	if o.Store == nil {
+		return
+	}
+	d, err := saveDraft(o, f, time.Time{})
+	if err != nil {
+		slog.Warn("cannot keep event as draft", logging.ErrorKey, err)
+		return
+	}
+	if _, err = o.Session.ChannelMessageSendEmbed(o.Channel.ID, o.Printer.Embed(&discord.DraftSavedMessage)); err != nil {
+		slog.Warn("cannot confirm draft", logging.EventKey, d.Title, logging.ErrorKey, err)
+	}
+}
+
+// publishTime is when a scheduled post being edited goes live, or zero for other events
+func publishTime(f *fsm.FSM) time.Time {
+	if val, found := f.Metadata(discord.PublishAt.String()); found {
+		if t, ok := val.(time.Time); ok {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// chosenChannel is the channel picked with the command, or with the command which first saved the draft
+func chosenChannel(o *discord.Options, f *fsm.FSM) string {
+	if o.ChannelID != "" {
+		return o.ChannelID
+	}
+	if id, found := f.Metadata(discord.ChannelID.String()); found {
+		return fmt.Sprint(id)
+	}
+	return ""
+}
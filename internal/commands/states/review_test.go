@@ -0,0 +1,315 @@
+package states
+
+import (
+	"context"
+	"github.com/GuessWhoSamFoo/fsm"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/discord"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/mock"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/role"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/store"
+	"github.com/bwmarrin/discordgo"
+	"github.com/ewohltman/discordgo-mock/mockconstants"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewReviewEventState(t *testing.T) {
+	opts, err := discord.NewMockOptions()
+	assert.NoError(t, err)
+
+	s := NewReviewEventState(*opts)
+	assert.NotNil(t, s)
+}
+
+func TestReviewEventState_OnState(t *testing.T) {
+	opts, err := discord.NewMockOptions()
+	assert.NoError(t, err)
+	start := time.Now().Add(time.Hour)
+
+	cases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "publish",
+			input:    "1",
+			expected: CreateEvent.String(),
+		},
+		{
+			name:     "change a field",
+			input:    "2",
+			expected: ReviewField.String(),
+		},
+		{
+			name:     "save draft",
+			input:    "3",
+			expected: SaveDraft.String(),
+		},
+		{
+			name:     "schedule",
+			input:    "4",
+			expected: SchedulePublish.String(),
+		},
+		{
+			name:     "invalid",
+			input:    "invalid",
+			expected: ReviewEventRetry.String(),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := NewReviewEventState(*opts)
+			f := fsm.NewFSM(
+				"idle",
+				fsm.Events{
+					{
+						Name: ReviewEvent.String(),
+						Src:  []string{"idle"},
+						Dst:  ReviewEvent.String(),
+					},
+					{
+						Name: ReviewEventRetry.String(),
+						Src:  []string{ReviewEvent.String()},
+						Dst:  ReviewEventRetry.String(),
+					},
+					{
+						Name: ReviewField.String(),
+						Src:  []string{ReviewEvent.String()},
+						Dst:  ReviewField.String(),
+					},
+					{
+						Name: SaveDraft.String(),
+						Src:  []string{ReviewEvent.String()},
+						Dst:  SaveDraft.String(),
+					},
+					{
+						Name: SchedulePublish.String(),
+						Src:  []string{ReviewEvent.String()},
+						Dst:  SchedulePublish.String(),
+					},
+					{
+						Name: CreateEvent.String(),
+						Src:  []string{ReviewEvent.String()},
+						Dst:  CreateEvent.String(),
+					},
+				},
+				fsm.Callbacks{
+					ReviewEvent.String(): r.OnState,
+				},
+			)
+			f.SetMetadata(discord.Title.String(), "event")
+			f.SetMetadata(discord.StartTime.String(), start)
+			f.SetMetadata(discord.Attendee.String(), role.NewDefaultRoleGroup())
+			r.inputHandler.handlerFunc = func(session *discordgo.Session, create *discordgo.MessageCreate) {
+				r.inputHandler.inputChan <- tc.input
+			}
+			var wg sync.WaitGroup
+			wg.Add(2)
+			go func() {
+				r.inputHandler.handlerFunc(opts.Session, &discordgo.MessageCreate{})
+				wg.Done()
+			}()
+			go func() {
+				err = f.Event(context.TODO(), ReviewEvent.String())
+				assert.NoError(t, err)
+				wg.Done()
+			}()
+			wg.Wait()
+			assert.Equal(t, tc.expected, f.Current())
+
+			obj, err := Get(f, discord.EventObject)
+			assert.NoError(t, err)
+			assert.Equal(t, "event", obj.(discord.Event).Title)
+		})
+	}
+}
+
+func TestReviewFieldSelect(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected string
+		isErr    bool
+	}{
+		{
+			input:    "3",
+			expected: SetAttendeeLimit.String(),
+		},
+		{
+			input:    "8",
+			expected: SetCategory.String(),
+		},
+		{
+			input: "9",
+			isErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.input, func(t *testing.T) {
+			f := fsm.NewFSM("idle", fsm.Events{}, fsm.Callbacks{})
+			f.SetMetadata(discord.MenuOption.String(), tc.input)
+			actual, err := ReviewFieldSelect(&fsm.Event{FSM: f})
+			if tc.isErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func Test_keepDuration(t *testing.T) {
+	start := time.Now().Add(time.Hour)
+	before := discord.Event{Start: start, End: start.Add(2 * time.Hour)}
+
+	cases := []struct {
+		name     string
+		before   discord.Event
+		start    time.Time
+		end      time.Time
+		expected time.Time
+	}{
+		{
+			name:     "moved",
+			before:   before,
+			start:    start.Add(24 * time.Hour),
+			end:      before.End,
+			expected: start.Add(26 * time.Hour),
+		},
+		{
+			name:     "new end given",
+			before:   before,
+			start:    start.Add(24 * time.Hour),
+			end:      start.Add(25 * time.Hour),
+			expected: start.Add(25 * time.Hour),
+		},
+		{
+			name:     "same start",
+			before:   before,
+			start:    start,
+			end:      before.End,
+			expected: before.End,
+		},
+		{
+			name:     "no end",
+			before:   discord.Event{Start: start},
+			start:    start.Add(24 * time.Hour),
+			end:      time.Time{},
+			expected: time.Time{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := fsm.NewFSM("idle", fsm.Events{}, fsm.Callbacks{})
+			f.SetMetadata(discord.StartTime.String(), tc.start)
+			f.SetMetadata(discord.Duration.String(), tc.end)
+			keepDuration(f, tc.before)
+
+			actual, err := Get(f, discord.Duration)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestSaveDraftState_OnState(t *testing.T) {
+	opts, err := discord.NewMockOptions()
+	assert.NoError(t, err)
+	opts.InteractionCreate.Interaction.ID = "interaction"
+	opts.InteractionCreate.Interaction.Member.User.ID = "user"
+	opts.ChannelID = mock.TextChannel
+	opts.Store, err = store.Open(filepath.Join(t.TempDir(), "store.json"))
+	assert.NoError(t, err)
+	start := time.Now().Add(time.Hour)
+
+	save := func(title string, draftID string, publishAt time.Time) {
+		s := NewSaveDraftState(*opts)
+		f := fsm.NewFSM(
+			"idle",
+			fsm.Events{
+				{
+					Name: SaveDraft.String(),
+					Src:  []string{"idle"},
+					Dst:  SaveDraft.String(),
+				},
+			},
+			fsm.Callbacks{
+				SaveDraft.String(): s.OnState,
+			},
+		)
+		rg := role.NewDefaultRoleGroup()
+		rg.SetLimit(role.AcceptedField, 10)
+		f.SetMetadata(discord.GuildID.String(), opts.InteractionCreate.GuildID)
+		f.SetMetadata(discord.Title.String(), title)
+		f.SetMetadata(discord.StartTime.String(), start)
+		f.SetMetadata(discord.Attendee.String(), rg)
+		if draftID != "" {
+			f.SetMetadata(discord.DraftID.String(), draftID)
+		}
+		if !publishAt.IsZero() {
+			f.SetMetadata(discord.PublishAt.String(), publishAt)
+		}
+		assert.NoError(t, f.Event(context.TODO(), SaveDraft.String()))
+	}
+
+	save("event", "", time.Time{})
+	drafts := opts.Store.Drafts(opts.InteractionCreate.GuildID, "user")
+	assert.Len(t, drafts, 1)
+	assert.Equal(t, "interaction", drafts[0].ID)
+	assert.Equal(t, "event", drafts[0].Title)
+	assert.Equal(t, mock.TextChannel, drafts[0].ChannelID)
+	assert.Equal(t, 10, drafts[0].Limit)
+	assert.True(t, start.Equal(drafts[0].Start))
+
+	assert.False(t, drafts[0].Scheduled())
+
+	save("renamed", "interaction", time.Time{})
+	drafts = opts.Store.Drafts(opts.InteractionCreate.GuildID, "user")
+	assert.Len(t, drafts, 1)
+	assert.Equal(t, "renamed", drafts[0].Title)
+
+	// Saving a scheduled post keeps the schedule
+	publishAt := start.Add(-30 * time.Minute)
+	save("scheduled", "interaction", publishAt)
+	drafts = opts.Store.Drafts(opts.InteractionCreate.GuildID, "user")
+	assert.Len(t, drafts, 1)
+	assert.True(t, publishAt.Equal(drafts[0].PublishAt))
+	assert.Equal(t, mock.TextChannel, drafts[0].PostChannelID)
+}
+
+func Test_saveDraft(t *testing.T) {
+	opts, err := discord.NewMockOptions()
+	assert.NoError(t, err)
+	opts.InteractionCreate.Interaction.ID = "interaction"
+	opts.InteractionCreate.Interaction.Member.User.ID = "user"
+	opts.Store, err = store.Open(filepath.Join(t.TempDir(), "store.json"))
+	assert.NoError(t, err)
+	start := time.Now().Add(time.Hour)
+
+	f := fsm.NewFSM("idle", fsm.Events{}, fsm.Callbacks{})
+	f.SetMetadata(discord.GuildID.String(), opts.InteractionCreate.GuildID)
+	f.SetMetadata(discord.Title.String(), "event")
+	f.SetMetadata(discord.StartTime.String(), start)
+	f.SetMetadata(discord.Attendee.String(), role.NewDefaultRoleGroup())
+
+	// A post which cannot go out is kept as a draft without its schedule
+	opts.ChannelID = mockconstants.TestPrivateChannel
+	_, err = saveDraft(opts, f, start.Add(-30*time.Minute))
+	assert.Error(t, err)
+	drafts := opts.Store.Drafts(opts.InteractionCreate.GuildID, "user")
+	assert.Len(t, drafts, 1)
+	assert.False(t, drafts[0].Scheduled())
+
+	opts.ChannelID = mock.TextChannel
+	d, err := saveDraft(opts, f, start.Add(-30*time.Minute))
+	assert.NoError(t, err)
+	assert.True(t, d.Scheduled())
+	assert.Equal(t, mock.TextChannel, d.PostChannelID)
+}
@@ -1,6 +1,7 @@
 package role
 
 import (
+	"errors"
 	"fmt"
 	"github.com/GuessWhoSamFoo/gang-gang-bot/pkg/util"
 	"golang.org/x/exp/slices"
@@ -36,9 +37,25 @@ func NewRole(icon string, fieldName FieldType) *Role {
 	}
 }
 
+// ErrEventFull is returned when a role is full and has no waitlist
+var ErrEventFull = errors.New("event full; cannot add to waitlist")
+
+// ErrNoRoomForGuests is returned when a role does not have enough spots left for a user's guests
+var ErrNoRoomForGuests = errors.New("not enough spots for guests")
+
+// Response is where a user is in a role group. A zero value means the user has not responded.
+type Response struct {
+	Field    FieldType // role the user responded to or is waitlisted for
+	Waitlist int       // position on the waitlist starting from 1, or 0 if not waitlisted
+}
+
 type RoleGroup struct {
 	Roles    []*Role
 	Waitlist map[FieldType]*Role
+	// Guests is how many guests each user is bringing. Guests take up spots in the role or waitlist of their user.
+	Guests map[string]int
+	// Rules are applied to users joining a role with a waitlist. Organizers adding users bypass them.
+	Rules *Rules
 }
 
 // NewDefaultRoleGroup returns the default Accepted, Declined, and Tentative fields
@@ -68,46 +85,91 @@ func (rg *RoleGroup) AddWaitlistForRole(icon string, field FieldType) {
 	}
 }
 
+// Party is the number of spots a user takes up including their guests
+func (rg *RoleGroup) Party(user string) int {
+	return 1 + rg.Guests[user]
+}
+
+// size is the number of spots taken up by a list of users and their guests
+func (rg *RoleGroup) size(users []string) int {
+	var n int
+	for _, u := range users {
+		n += rg.Party(u)
+	}
+	return n
+}
+
+// fits checks if a user and their guests can join a role without going over its limit
+func (rg *RoleGroup) fits(r *Role, user string) bool {
+	return r.Limit == 0 || r.Count+rg.Party(user) <= r.Limit
+}
+
+// promote moves users from the front of a waitlist into a role while their party fits
+func (rg *RoleGroup) promote(r *Role, wl *Role) []string {
+	var promoted []string
+	for len(wl.Users) > 0 && rg.fits(r, wl.Users[0]) {
+		name := wl.Users[0]
+		wl.Users = wl.Users[1:]
+		wl.Count -= rg.Party(name)
+		r.Users = append(r.Users, name)
+		r.Count += rg.Party(name)
+		promoted = append(promoted, name)
+	}
+	return promoted
+}
+
 func (rg *RoleGroup) ToggleRole(fieldName FieldType, user string) error {
 	for _, r := range rg.Roles {
 		hasUser := slices.Contains(r.Users, user)
-		isFull := r.Limit > 0 && r.Count == r.Limit
+		isFull := !rg.fits(r, user)
 		wl, hasWaitlist := rg.Waitlist[r.FieldName]
 		switch {
 		case hasUser && hasWaitlist:
-			r.Count--
+			r.Count -= rg.Party(user)
 			r.Users = util.RemoveUser(r.Users, user)
-			if wl.Count > 0 {
-				wl.Count--
-				name := wl.Users[0]
-				wl.Users = wl.Users[1:]
-				r.Count++
-				r.Users = append(r.Users, name)
-			}
+			delete(rg.Guests, user)
+			rg.promote(r, wl)
 		case hasUser && !hasWaitlist:
-			r.Count--
+			r.Count -= rg.Party(user)
 			r.Users = util.RemoveUser(r.Users, user)
+			delete(rg.Guests, user)
 		case !hasUser && hasWaitlist:
 			if slices.Contains(wl.Users, user) {
-				wl.Count--
+				wl.Count -= rg.Party(user)
 				wl.Users = util.RemoveUser(wl.Users, user)
+				delete(rg.Guests, user)
 				continue
 			}
-			if isFull && r.FieldName == fieldName {
-				wl.Count++
-				wl.Users = append(wl.Users, user)
+			if r.FieldName != fieldName {
 				continue
 			}
-			if r.FieldName == fieldName {
-				r.Count++
+			m, verdict, err := rg.Rules.Evaluate(user)
+			if err != nil {
+				return err
+			}
+			// Unlimited roles never promote from the waitlist
+			if verdict.Outcome == Waitlist && r.Limit == 0 {
+				verdict.Outcome = Allow
+			}
+			switch {
+			case verdict.Outcome == Reject:
+				return &RuleError{Reason: verdict.Reason, Args: verdict.Args}
+			case isFull || verdict.Outcome == Waitlist:
+				if verdict.Outcome == Waitlist {
+					rg.Rules.Waitlisted = verdict
+				}
+				wl.Count += rg.Party(user)
+				wl.Users = rg.Rules.joinWaitlist(wl.Users, m)
+			default:
+				r.Count += rg.Party(user)
 				r.Users = append(r.Users, user)
 			}
 		case !hasUser && !hasWaitlist:
 			if isFull {
-				return fmt.Errorf("event full; cannot add to waitlist")
+				return ErrEventFull
 			}
 			if r.FieldName == fieldName {
-				r.Count++
+				r.Count += rg.Party(user)
 				r.Users = append(r.Users, user)
 			}
 		}
@@ -117,15 +179,71 @@ func (rg *RoleGroup) ToggleRole(fieldName FieldType, user string) error {
 
 // RemoveFromAllLists removes a username from all role groups including waitlists
 func (rg *RoleGroup) RemoveFromAllLists(name string) error {
+	delete(rg.Guests, name)
 	for _, r := range rg.Roles {
 		r.Users = util.RemoveUser(r.Users, name)
-		r.Count = len(r.Users)
+		r.Count = rg.size(r.Users)
 		wl, ok := rg.Waitlist[r.FieldName]
 		if ok {
 			wl.Users = util.RemoveUser(wl.Users, name)
-			wl.Count = len(wl.Users)
+			wl.Count = rg.size(wl.Users)
+		}
+	}
+	return nil
+}
+
+// PromoteFromWaitlists fills open spots in every role from its waitlist and returns the users who were moved
+func (rg *RoleGroup) PromoteFromWaitlists() []string {
+	promoted := make([]string, 0)
+	for _, r := range rg.Roles {
+		if wl, ok := rg.Waitlist[r.FieldName]; ok {
+			promoted = append(promoted, rg.promote(r, wl)...)
+		}
+	}
+	return promoted
+}
+
+// SetGuests changes how many guests a user is bringing and returns the users moved off the waitlist as a result. A
+// user in a full role can only bring more guests if there are spots left for them.
+func (rg *RoleGroup) SetGuests(user string, guests int) ([]string, error) {
+	for _, r := range rg.Roles {
+		if slices.Contains(r.Users, user) {
+			change := guests - rg.Guests[user]
+			if change > 0 && r.Limit > 0 && r.Count+change > r.Limit {
+				return nil, ErrNoRoomForGuests
+			}
+			r.Count += change
+			rg.setGuests(user, guests)
+			if wl, ok := rg.Waitlist[r.FieldName]; ok {
+				return rg.promote(r, wl), nil
+			}
+			return nil, nil
+		}
+		if wl, ok := rg.Waitlist[r.FieldName]; ok && slices.Contains(wl.Users, user) {
+			wl.Count += guests - rg.Guests[user]
+			rg.setGuests(user, guests)
+			return nil, nil
 		}
 	}
+	return nil, fmt.Errorf("%s has not responded", user)
+}
+
+func (rg *RoleGroup) setGuests(user string, guests int) {
+	if guests == 0 {
+		delete(rg.Guests, user)
+		return
+	}
+	if rg.Guests == nil {
+		rg.Guests = map[string]int{}
+	}
+	rg.Guests[user] = guests
+}
+
+// WaitlistUsers returns a copy of the users waiting for a role
+func (rg *RoleGroup) WaitlistUsers(field FieldType) []string {
+	if wl, ok := rg.Waitlist[field]; ok {
+		return slices.Clone(wl.Users)
+	}
 	return nil
 }
 
@@ -148,6 +266,58 @@ func (rg *RoleGroup) HasUser(name string, field FieldType) bool {
 	return false
 }
 
+// HasResponse checks if a user has responded to any role or is on a waitlist
+func (rg *RoleGroup) HasResponse(name string) bool {
+	for _, r := range rg.Roles {
+		if slices.Contains(r.Users, name) {
+			return true
+		}
+	}
+	for _, wl := range rg.Waitlist {
+		if slices.Contains(wl.Users, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetResponse finds which role a user responded to, including their position on a waitlist
+func (rg *RoleGroup) GetResponse(name string) Response {
+	for _, r := range rg.Roles {
+		if slices.Contains(r.Users, name) {
+			return Response{Field: r.FieldName}
+		}
+	}
+	for _, r := range rg.Roles {
+		if wl, ok := rg.Waitlist[r.FieldName]; ok {
+			if i := slices.Index(wl.Users, name); i >= 0 {
+				return Response{Field: r.FieldName, Waitlist: i + 1}
+			}
+		}
+	}
+	return Response{}
+}
+
+// GetUsers returns the users who responded to a role
+func (rg *RoleGroup) GetUsers(field FieldType) []string {
+	for _, r := range rg.Roles {
+		if r.FieldName == field {
+			return r.Users
+		}
+	}
+	return nil
+}
+
+// GetLimit returns how many users a role allows, or 0 for no limit
+func (rg *RoleGroup) GetLimit(field FieldType) int {
+	for _, r := range rg.Roles {
+		if r.FieldName == field {
+			return r.Limit
+		}
+	}
+	return 0
+}
+
 func (rg *RoleGroup) SetLimit(field FieldType, limit int) {
 	for _, r := range rg.Roles {
 		if r.FieldName == field {
@@ -156,3 +326,32 @@ func (rg *RoleGroup) SetLimit(field FieldType, limit int) {
 		}
 	}
 }
+
+// ChangeLimit sets how many users a role allows after it already has responses. Users who no longer fit move to the
+// front of the waitlist, latest to respond first, and open spots are filled from the waitlist. It returns the users
+// promoted off the waitlist.
+func (rg *RoleGroup) ChangeLimit(field FieldType, limit int) []string {
+	for _, r := range rg.Roles {
+		if r.FieldName != field {
+			continue
+		}
+		r.Limit = limit
+		wl, ok := rg.Waitlist[field]
+		if !ok {
+			return nil
+		}
+		if limit > 0 && r.Count > limit {
+			var moved []string
+			for len(r.Users) > 0 && r.Count > limit {
+				name := r.Users[len(r.Users)-1]
+				r.Users = r.Users[:len(r.Users)-1]
+				r.Count -= rg.Party(name)
+				wl.Count += rg.Party(name)
+				moved = append([]string{name}, moved...)
+			}
+			wl.Users = append(moved, wl.Users...)
+		}
+		return rg.promote(r, wl)
+	}
+	return nil
+}
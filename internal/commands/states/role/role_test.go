@@ -621,6 +621,107 @@ func TestRoleGroup_HasUser(t *testing.T) {
 	}
 }
 
+func TestRoleGroup_HasResponse(t *testing.T) {
+	cases := []struct {
+		name      string
+		user      string
+		roleGroup *RoleGroup
+		expected  bool
+	}{
+		{
+			name: "has tentative response",
+			user: "foo",
+			roleGroup: &RoleGroup{
+				Roles: []*Role{
+					{
+						FieldName: AcceptedField,
+						Users:     []string{},
+					},
+					{
+						FieldName: TentativeField,
+						Users:     []string{"foo"},
+					},
+				},
+			},
+			expected: true,
+		},
+		{
+			name: "user in waitlist",
+			user: "foo",
+			roleGroup: &RoleGroup{
+				Roles: []*Role{
+					{
+						FieldName: AcceptedField,
+						Users:     []string{"baz"},
+					},
+				},
+				Waitlist: map[FieldType]*Role{
+					AcceptedField: {
+						FieldName: WaitlistField,
+						Users:     []string{"foo"},
+					},
+				},
+			},
+			expected: true,
+		},
+		{
+			name:      "no response",
+			user:      "foo",
+			roleGroup: NewDefaultRoleGroup(),
+			expected:  false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.roleGroup.HasResponse(tc.user)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestRoleGroup_GetResponse(t *testing.T) {
+	rg := NewDefaultRoleGroup()
+	rg.SetLimit(AcceptedField, 1)
+	assert.NoError(t, rg.ToggleRole(AcceptedField, "a"))
+	assert.NoError(t, rg.ToggleRole(AcceptedField, "b"))
+	assert.NoError(t, rg.ToggleRole(AcceptedField, "c"))
+	assert.NoError(t, rg.ToggleRole(DeclinedField, "d"))
+
+	cases := []struct {
+		name     string
+		user     string
+		expected Response
+	}{
+		{
+			name:     "accepted",
+			user:     "a",
+			expected: Response{Field: AcceptedField},
+		},
+		{
+			name:     "second on waitlist",
+			user:     "c",
+			expected: Response{Field: AcceptedField, Waitlist: 2},
+		},
+		{
+			name:     "declined",
+			user:     "d",
+			expected: Response{Field: DeclinedField},
+		},
+		{
+			name:     "no response",
+			user:     "e",
+			expected: Response{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, rg.GetResponse(tc.user))
+		})
+	}
+}
+
 func TestRoleGroup_PeekWaitlist(t *testing.T) {
 	rg := NewDefaultRoleGroup()
 	rg.SetLimit(AcceptedField, 1)
@@ -636,3 +737,119 @@ func TestRoleGroup_SetLimit(t *testing.T) {
 	rg.SetLimit(AcceptedField, 2)
 	assert.Equal(t, 2, rg.Roles[0].Limit)
 }
+
+func TestRoleGroup_ChangeLimit(t *testing.T) {
+	cases := []struct {
+		name             string
+		initial          int
+		limit            int
+		guests           map[string]int
+		expectedAccepted []string
+		expectedWaitlist []string
+		expectedPromoted []string
+	}{
+		{
+			name:             "lower",
+			initial:          2,
+			limit:            1,
+			expectedAccepted: []string{"a"},
+			expectedWaitlist: []string{"b", "d"},
+		},
+		{
+			name:             "raise",
+			initial:          2,
+			limit:            3,
+			expectedAccepted: []string{"a", "b", "d"},
+			expectedWaitlist: []string{},
+			expectedPromoted: []string{"d"},
+		},
+		{
+			name:             "unlimited",
+			initial:          2,
+			limit:            0,
+			expectedAccepted: []string{"a", "b", "d"},
+			expectedWaitlist: []string{},
+			expectedPromoted: []string{"d"},
+		},
+		{
+			name:             "lower with guests",
+			initial:          4,
+			limit:            2,
+			guests:           map[string]int{"b": 1},
+			expectedAccepted: []string{"a"},
+			expectedWaitlist: []string{"b", "d"},
+		},
+		{
+			name:             "raise with guests",
+			initial:          2,
+			limit:            4,
+			guests:           map[string]int{"d": 2},
+			expectedAccepted: []string{"a", "b"},
+			expectedWaitlist: []string{"d"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rg := NewDefaultRoleGroup()
+			rg.SetLimit(AcceptedField, tc.initial)
+			for _, user := range []string{"a", "b", "d"} {
+				assert.NoError(t, rg.ToggleRole(AcceptedField, user))
+			}
+			for user, guests := range tc.guests {
+				_, err := rg.SetGuests(user, guests)
+				assert.NoError(t, err)
+			}
+
+			promoted := rg.ChangeLimit(AcceptedField, tc.limit)
+			assert.Equal(t, tc.expectedPromoted, promoted)
+			assert.Equal(t, tc.limit, rg.GetLimit(AcceptedField))
+			assert.Equal(t, tc.expectedAccepted, rg.GetUsers(AcceptedField))
+			assert.Equal(t, tc.expectedWaitlist, rg.WaitlistUsers(AcceptedField))
+			assert.Equal(t, rg.size(tc.expectedAccepted), rg.Roles[0].Count)
+			assert.Equal(t, rg.size(tc.expectedWaitlist), rg.Waitlist[AcceptedField].Count)
+		})
+	}
+}
+
+func TestRoleGroup_GetUsers(t *testing.T) {
+	rg := NewDefaultRoleGroup()
+	assert.NoError(t, rg.ToggleRole(AcceptedField, "a"))
+	assert.NoError(t, rg.ToggleRole(TentativeField, "b"))
+	assert.Equal(t, []string{"a"}, rg.GetUsers(AcceptedField))
+	assert.Empty(t, rg.GetUsers(DeclinedField))
+	assert.Nil(t, rg.GetUsers(WaitlistField))
+}
+
+func TestRoleGroup_SetGuests(t *testing.T) {
+	rg := NewDefaultRoleGroup()
+	rg.SetLimit(AcceptedField, 3)
+	assert.NoError(t, rg.ToggleRole(AcceptedField, "foo"))
+	assert.NoError(t, rg.ToggleRole(AcceptedField, "bar"))
+	assert.NoError(t, rg.ToggleRole(AcceptedField, "baz"))
+
+	// Waitlisted guests take up waitlist spots
+	assert.NoError(t, rg.ToggleRole(AcceptedField, "qux"))
+	promoted, err := rg.SetGuests("qux", 1)
+	assert.NoError(t, err)
+	assert.Empty(t, promoted)
+	assert.Equal(t, 2, rg.Waitlist[AcceptedField].Count)
+
+	_, err = rg.SetGuests("foo", 1)
+	assert.ErrorIs(t, err, ErrNoRoomForGuests)
+
+	// qux and their guest only fit once two spots are open
+	assert.NoError(t, rg.ToggleRole(AcceptedField, "bar"))
+	assert.Equal(t, "qux", rg.PeekWaitlist(AcceptedField))
+	assert.NoError(t, rg.ToggleRole(AcceptedField, "baz"))
+	assert.Equal(t, []string{"foo", "qux"}, rg.GetUsers(AcceptedField))
+	assert.Equal(t, 3, rg.Roles[0].Count)
+
+	// Dropping out clears guests
+	assert.NoError(t, rg.ToggleRole(AcceptedField, "qux"))
+	assert.Equal(t, 1, rg.Party("qux"))
+	assert.Equal(t, 1, rg.Roles[0].Count)
+
+	_, err = rg.SetGuests("nobody", 1)
+	assert.Error(t, err)
+}
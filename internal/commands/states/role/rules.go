@@ -0,0 +1,126 @@
+package role
+
+import (
+	"fmt"
+	"golang.org/x/exp/slices"
+)
+
+// Member is what signup rules know about a user joining a role
+type Member struct {
+	Name              string
+	Priority          bool // has a role which is placed ahead of others on the waitlist
+	LateCancellations int  // recent late cancellations
+	AcceptedThisWeek  int  // accepted spots in other events the same week
+}
+
+// Outcome is where a rule places a user joining a role
+type Outcome int
+
+const (
+	// Allow adds the user to the role if there is room
+	Allow Outcome = iota
+	// Waitlist adds the user to the waitlist even if there is room
+	Waitlist
+	// Reject refuses the response
+	Reject
+)
+
+// Verdict is the result of a rule. The reason is shown to the user, formatted from the args so it can be translated
+// first.
+type Verdict struct {
+	Outcome Outcome
+	Reason  string
+	Args    []any
+}
+
+// Rule decides where a user joining a role is placed
+type Rule interface {
+	Evaluate(m Member) Verdict
+}
+
+// LateCancelRule waitlists members who cancelled late too many times
+type LateCancelRule struct {
+	Limit int
+}
+
+func (r LateCancelRule) Evaluate(m Member) Verdict {
+	if r.Limit > 0 && m.LateCancellations >= r.Limit {
+		return Verdict{
+			Outcome: Waitlist,
+			Reason:  "You've cancelled late %d times recently, so you've been added to the waitlist",
+			Args:    []any{m.LateCancellations},
+		}
+	}
+	return Verdict{}
+}
+
+// WeeklyLimitRule limits how many events a member can accept in a week
+type WeeklyLimitRule struct {
+	Limit int
+}
+
+func (r WeeklyLimitRule) Evaluate(m Member) Verdict {
+	if r.Limit > 0 && m.AcceptedThisWeek >= r.Limit {
+		return Verdict{
+			Outcome: Reject,
+			Reason:  "You can accept at most %d events per week. Drop out of another event this week to join.",
+			Args:    []any{r.Limit},
+		}
+	}
+	return Verdict{}
+}
+
+// RuleError is returned when a rule rejects a response
+type RuleError struct {
+	Reason string
+	Args   []any
+}
+
+func (e *RuleError) Error() string {
+	return fmt.Sprintf(e.Reason, e.Args...)
+}
+
+// Rules are evaluated when a user joins a role with a waitlist
+type Rules struct {
+	Rules []Rule
+	// Member describes the user joining
+	Member func(name string) (Member, error)
+	// IsPriority reports whether a waitlisted user has priority. Priority users joining the waitlist are placed ahead of
+	// waitlisted users without it.
+	IsPriority func(name string) bool
+	// Waitlisted is the verdict of the rule which placed the user on the waitlist, if one did, so its reason can be shown
+	// to them
+	Waitlisted Verdict
+}
+
+// Evaluate runs every rule for a user and returns the most restrictive verdict
+func (r *Rules) Evaluate(name string) (Member, Verdict, error) {
+	if r == nil || r.Member == nil {
+		return Member{Name: name}, Verdict{}, nil
+	}
+	m, err := r.Member(name)
+	if err != nil {
+		return Member{}, Verdict{}, err
+	}
+	m.Name = name
+	var result Verdict
+	for _, rule := range r.Rules {
+		if v := rule.Evaluate(m); v.Outcome > result.Outcome {
+			result = v
+		}
+	}
+	return m, result, nil
+}
+
+// joinWaitlist adds a member to a waitlist, ahead of users without priority if the member has it
+func (r *Rules) joinWaitlist(users []string, m Member) []string {
+	if r == nil || r.IsPriority == nil || !m.Priority {
+		return append(users, m.Name)
+	}
+	for i, u := range users {
+		if !r.IsPriority(u) {
+			return slices.Insert(users, i, m.Name)
+		}
+	}
+	return append(users, m.Name)
+}
@@ -0,0 +1,122 @@
+package role
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRules_Evaluate(t *testing.T) {
+	rules := []Rule{LateCancelRule{Limit: 2}, WeeklyLimitRule{Limit: 1}}
+	cases := []struct {
+		name     string
+		member   Member
+		expected Outcome
+	}{
+		{
+			name:     "allowed",
+			member:   Member{LateCancellations: 1},
+			expected: Allow,
+		},
+		{
+			name:     "late cancellations",
+			member:   Member{LateCancellations: 2},
+			expected: Waitlist,
+		},
+		{
+			name:     "reject wins over waitlist",
+			member:   Member{LateCancellations: 2, AcceptedThisWeek: 1},
+			expected: Reject,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &Rules{
+				Rules: rules,
+				Member: func(name string) (Member, error) {
+					return tc.member, nil
+				},
+			}
+			m, v, err := r.Evaluate("foo")
+			assert.NoError(t, err)
+			assert.Equal(t, "foo", m.Name)
+			assert.Equal(t, tc.expected, v.Outcome)
+			if tc.expected != Allow {
+				assert.NotEmpty(t, v.Reason)
+			}
+		})
+	}
+}
+
+func TestRoleGroup_ToggleRoleWithRules(t *testing.T) {
+	priority := map[string]bool{"vip": true, "vip2": true}
+	members := map[string]Member{
+		"late":  {LateCancellations: 3},
+		"busy":  {AcceptedThisWeek: 2},
+		"vip":   {Priority: true},
+		"vip2":  {Priority: true},
+		"guest": {},
+	}
+	newGroup := func(limit int) *RoleGroup {
+		rg := NewDefaultRoleGroup()
+		rg.SetLimit(AcceptedField, limit)
+		rg.Rules = &Rules{
+			Rules: []Rule{LateCancelRule{Limit: 2}, WeeklyLimitRule{Limit: 2}},
+			Member: func(name string) (Member, error) {
+				return members[name], nil
+			},
+			IsPriority: func(name string) bool {
+				return priority[name]
+			},
+		}
+		return rg
+	}
+
+	t.Run("late cancellations are waitlisted with room left", func(t *testing.T) {
+		rg := newGroup(5)
+		assert.NoError(t, rg.ToggleRole(AcceptedField, "late"))
+		assert.Equal(t, "late", rg.PeekWaitlist(AcceptedField))
+		assert.False(t, rg.HasUser("late", AcceptedField))
+		assert.Equal(t, Waitlist, rg.Rules.Waitlisted.Outcome)
+		assert.Equal(t, []any{3}, rg.Rules.Waitlisted.Args)
+	})
+
+	t.Run("full events waitlist without a reason", func(t *testing.T) {
+		rg := newGroup(1)
+		assert.NoError(t, rg.ToggleRole(AcceptedField, "guest"))
+		assert.NoError(t, rg.ToggleRole(AcceptedField, "vip"))
+		assert.Equal(t, "vip", rg.PeekWaitlist(AcceptedField))
+		assert.Empty(t, rg.Rules.Waitlisted.Reason)
+	})
+
+	t.Run("unlimited events ignore the waitlist rule", func(t *testing.T) {
+		rg := newGroup(0)
+		assert.NoError(t, rg.ToggleRole(AcceptedField, "late"))
+		assert.True(t, rg.HasUser("late", AcceptedField))
+	})
+
+	t.Run("weekly limit rejects", func(t *testing.T) {
+		rg := newGroup(5)
+		err := rg.ToggleRole(AcceptedField, "busy")
+		var ruleErr *RuleError
+		assert.True(t, errors.As(err, &ruleErr))
+		assert.Equal(t, "You can accept at most 2 events per week. Drop out of another event this week to join.", ruleErr.Error())
+		assert.False(t, rg.HasResponse("busy"))
+	})
+
+	t.Run("rules do not apply to other roles", func(t *testing.T) {
+		rg := newGroup(5)
+		assert.NoError(t, rg.ToggleRole(TentativeField, "busy"))
+		assert.True(t, rg.HasUser("busy", TentativeField))
+	})
+
+	t.Run("priority members are waitlisted ahead of others", func(t *testing.T) {
+		rg := newGroup(1)
+		assert.NoError(t, rg.ToggleRole(AcceptedField, "guest"))
+		assert.NoError(t, rg.ToggleRole(AcceptedField, "late"))
+		assert.NoError(t, rg.ToggleRole(AcceptedField, "vip"))
+		assert.NoError(t, rg.ToggleRole(AcceptedField, "vip2"))
+		assert.Equal(t, []string{"vip", "vip2", "late"}, rg.Waitlist[AcceptedField].Users)
+	})
+}
@@ -0,0 +1,136 @@
+package states
+
+import (
+	"context"
+	"fmt"
+	"github.com/GuessWhoSamFoo/fsm"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/discord"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/logging"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/metrics"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/pkg/timeparse"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/pkg/util"
+	"github.com/tj/go-naturaldate"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// SchedulePublishState saves the event as a draft which is posted automatically at a later time
+type SchedulePublishState struct {
+	*discord.Options
+
+	inputHandler *InputHandler
+}
+
+func NewSchedulePublishState(o discord.Options) *SchedulePublishState {
+	return &SchedulePublishState{
+		Options:      &o,
+		inputHandler: NewInputHandler(&o),
+	}
+}
+
+func (s *SchedulePublishState) OnState(ctx context.Context, e *fsm.Event) {
+	if _, err := s.Session.ChannelMessageSendEmbed(s.Channel.ID, s.Printer.Embed(&discord.EnterPublishTimeMessage)); err != nil {
+		e.Err = err
+		return
+	}
+	if err := s.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.PublishAt, promptTimeout); err != nil {
+		e.Err = err
+		return
+	}
+	if err := validatePublishTime(e, discord.PublishAt, time.Now()); err != nil {
+		eventErr := e.FSM.Event(ctx, SchedulePublishRetry.String())
+		if eventErr != nil {
+			e.Err = fmt.Errorf("%v: %v", err, eventErr)
+		}
+		return
+	}
+	if err := schedulePost(s.Options, e.FSM); err != nil {
+		e.Err = err
+		return
+	}
+}
+
+type SchedulePublishRetryState struct {
+	*discord.Options
+
+	inputHandler *InputHandler
+}
+
+func NewSchedulePublishRetryState(o discord.Options) *SchedulePublishRetryState {
+	return &SchedulePublishRetryState{
+		Options:      &o,
+		inputHandler: NewInputHandler(&o),
+	}
+}
+
+func (s *SchedulePublishRetryState) OnState(ctx context.Context, e *fsm.Event) {
+	if _, err := s.Session.ChannelMessageSend(s.Channel.ID, s.Printer.T(discord.InvalidPublishTimeText)); err != nil {
+		e.Err = err
+		return
+	}
+	if err := s.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.PublishAt, promptTimeout); err != nil {
+		e.Err = err
+		return
+	}
+	if err := validatePublishTime(e, discord.PublishAt, time.Now()); err != nil {
+		eventErr := e.FSM.Event(ctx, SelfTransition.String())
+		if eventErr != nil {
+			e.Err = fmt.Errorf("%v: %v", err, eventErr)
+		}
+		return
+	}
+	if err := schedulePost(s.Options, e.FSM); err != nil {
+		e.Err = err
+		return
+	}
+}
+
+// validatePublishTime parses when a scheduled post goes live, which must fall between now and the start of the event.
+// It can be relative to the start such as "2 days before" or a time such as "monday at noon".
+func validatePublishTime(e *fsm.Event, key discord.MetadataKey, now time.Time) error {
+	val, err := Get(e.FSM, key)
+	if err != nil {
+		return err
+	}
+	input := strings.TrimSpace(fmt.Sprintf("%v", val))
+	e.FSM.SetMetadata(key.String(), time.Time{})
+
+	startTime, err := eventStartTime(e.FSM)
+	if err != nil {
+		return err
+	}
+
+	publishAt, ok := util.ParseOffsetBefore(input, startTime)
+	if !ok {
+		r, err := timeparse.Parse(input, now)
+		publishAt = r.Start
+		if err != nil {
+			publishAt, err = naturaldate.Parse(input, now, naturaldate.WithDirection(naturaldate.Future))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// Unrecognized input is parsed as the reference time
+	if input == "" || !publishAt.After(now) || !publishAt.Before(startTime) {
+		return fmt.Errorf("invalid publish time")
+	}
+	e.FSM.SetMetadata(key.String(), publishAt)
+	return nil
+}
+
+// schedulePost saves the event as a draft with the publish time and confirms when it goes live
+func schedulePost(o *discord.Options, f *fsm.FSM) error {
+	d, err := saveDraft(o, f, publishTime(f))
+	if err != nil {
+		return err
+	}
+	if _, err = o.Session.ChannelMessageSendEmbed(o.Channel.ID, o.Printer.Embed(discord.PostScheduledMessage(d.PublishAt))); err != nil {
+		return err
+	}
+	metrics.RecordFSMOutcome(CreateAction, metrics.Scheduled)
+	slog.Info("Scheduled event post", logging.EventKey, d.Title, logging.UserKey, d.OwnerID, "publishAt", d.PublishAt)
+	return nil
+}
@@ -0,0 +1,190 @@
+package states
+
+import (
+	"context"
+	"github.com/GuessWhoSamFoo/fsm"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/discord"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/mock"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/role"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/store"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewSchedulePublishState(t *testing.T) {
+	opts, err := discord.NewMockOptions()
+	assert.NoError(t, err)
+
+	s := NewSchedulePublishState(*opts)
+	assert.NotNil(t, s)
+}
+
+func TestNewSchedulePublishRetryState(t *testing.T) {
+	opts, err := discord.NewMockOptions()
+	assert.NoError(t, err)
+
+	s := NewSchedulePublishRetryState(*opts)
+	assert.NotNil(t, s)
+}
+
+func TestSchedulePublishState_OnState(t *testing.T) {
+	opts, err := discord.NewMockOptions()
+	assert.NoError(t, err)
+	opts.InteractionCreate.Interaction.ID = "interaction"
+	opts.InteractionCreate.Interaction.Member.User.ID = "user"
+	opts.EventsChannelID = mock.TextChannel
+	start := time.Now().Add(72 * time.Hour)
+
+	cases := []struct {
+		name          string
+		input         string
+		expectedState string
+		expected      time.Time
+	}{
+		{
+			name:          "scheduled",
+			input:         "1 day before",
+			expectedState: SchedulePublish.String(),
+			expected:      start.Add(-24 * time.Hour),
+		},
+		{
+			name:          "after start",
+			input:         "1 week",
+			expectedState: SchedulePublishRetry.String(),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			opts.Store, err = store.Open(filepath.Join(t.TempDir(), "store.json"))
+			assert.NoError(t, err)
+
+			s := NewSchedulePublishState(*opts)
+			f := fsm.NewFSM(
+				"idle",
+				fsm.Events{
+					{
+						Name: SchedulePublish.String(),
+						Src:  []string{"idle"},
+						Dst:  SchedulePublish.String(),
+					},
+					{
+						Name: SchedulePublishRetry.String(),
+						Src:  []string{SchedulePublish.String()},
+						Dst:  SchedulePublishRetry.String(),
+					},
+				},
+				fsm.Callbacks{
+					SchedulePublish.String(): s.OnState,
+				},
+			)
+			f.SetMetadata(discord.GuildID.String(), opts.InteractionCreate.GuildID)
+			f.SetMetadata(discord.Title.String(), "event")
+			f.SetMetadata(discord.StartTime.String(), start)
+			f.SetMetadata(discord.Attendee.String(), role.NewDefaultRoleGroup())
+			s.inputHandler.handlerFunc = func(session *discordgo.Session, create *discordgo.MessageCreate) {
+				s.inputHandler.inputChan <- tc.input
+			}
+			var wg sync.WaitGroup
+			wg.Add(2)
+			go func() {
+				s.inputHandler.handlerFunc(opts.Session, &discordgo.MessageCreate{})
+				wg.Done()
+			}()
+			go func() {
+				err = f.Event(context.TODO(), SchedulePublish.String())
+				assert.NoError(t, err)
+				wg.Done()
+			}()
+			wg.Wait()
+			assert.Equal(t, tc.expectedState, f.Current())
+
+			drafts := opts.Store.Drafts(opts.InteractionCreate.GuildID, "user")
+			if tc.expected.IsZero() {
+				assert.Empty(t, drafts)
+				return
+			}
+			assert.Len(t, drafts, 1)
+			assert.True(t, tc.expected.Equal(drafts[0].PublishAt))
+			assert.Equal(t, mock.TextChannel, drafts[0].PostChannelID)
+			assert.Equal(t, "event", drafts[0].Title)
+		})
+	}
+}
+
+func Test_validatePublishTime(t *testing.T) {
+	now := time.Date(2023, 6, 7, 12, 0, 0, 0, time.Local)
+	start := now.Add(72 * time.Hour)
+
+	cases := []struct {
+		name     string
+		input    string
+		metadata map[discord.MetadataKey]interface{}
+		expected time.Time
+		isErr    bool
+	}{
+		{
+			name:     "before start",
+			input:    "2 days before",
+			metadata: map[discord.MetadataKey]interface{}{discord.StartTime: start},
+			expected: start.Add(-48 * time.Hour),
+		},
+		{
+			name:     "day and time",
+			input:    "friday at 9am",
+			metadata: map[discord.MetadataKey]interface{}{discord.StartTime: start},
+			expected: time.Date(2023, 6, 9, 9, 0, 0, 0, time.Local),
+		},
+		{
+			name:     "relative",
+			input:    "in 1 day",
+			metadata: map[discord.MetadataKey]interface{}{discord.StartTime: start},
+			expected: now.Add(24 * time.Hour),
+		},
+		{
+			name:     "after start",
+			input:    "1 week",
+			metadata: map[discord.MetadataKey]interface{}{discord.StartTime: start},
+			expected: time.Time{},
+			isErr:    true,
+		},
+		{
+			name:     "now",
+			input:    "now",
+			metadata: map[discord.MetadataKey]interface{}{discord.StartTime: start},
+			expected: time.Time{},
+			isErr:    true,
+		},
+		{
+			name:     "invalid",
+			input:    "invalid",
+			metadata: map[discord.MetadataKey]interface{}{discord.StartTime: start},
+			expected: time.Time{},
+			isErr:    true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := fsm.NewFSM("idle", fsm.Events{}, fsm.Callbacks{})
+			for k, v := range tc.metadata {
+				f.SetMetadata(k.String(), v)
+			}
+			f.SetMetadata(discord.PublishAt.String(), tc.input)
+			err := validatePublishTime(&fsm.Event{FSM: f}, discord.PublishAt, now)
+			if tc.isErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			actual, exists := f.Metadata(discord.PublishAt.String())
+			assert.True(t, exists)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
@@ -5,14 +5,15 @@ import (
 	"fmt"
 	"github.com/GuessWhoSamFoo/fsm"
 	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/discord"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/role"
 	"github.com/bwmarrin/discordgo"
-	"time"
 )
 
 type SignUpState struct {
 	session           *discordgo.Session
 	interactionCreate *discordgo.InteractionCreate
 	channel           *discordgo.Channel
+	printer           *discord.Printer
 
 	inputHandler *InputHandler
 }
@@ -22,44 +23,33 @@ func NewSignUpState(o discord.Options) *SignUpState {
 		session:           o.Session,
 		interactionCreate: o.InteractionCreate,
 		channel:           o.Channel,
+		printer:           o.Printer,
 		inputHandler:      NewInputHandler(&o),
 	}
 }
 
 func (s *SignUpState) OnState(ctx context.Context, e *fsm.Event) {
-	if _, err := s.session.ChannelMessageSendEmbed(s.channel.ID, &discordgo.MessageEmbed{
+	if _, err := s.session.ChannelMessageSendEmbed(s.channel.ID, s.printer.Embed(&discordgo.MessageEmbed{
 		Title:       "Which signup option should we add the user to?",
 		Description: fmt.Sprintf("**1**⠀%s\n**2**⠀%s\n**3**⠀%s", discord.AcceptedBase, discord.DeclinedBase, discord.TentativeBase),
 		Color:       discord.Purple,
 		Footer: &discordgo.MessageEmbedFooter{
 			Text: discord.CancelText,
 		},
-	}); err != nil {
+	})); err != nil {
 		e.Err = err
 		return
 	}
-	user, err := Get(e.FSM, discord.Username)
-	if err != nil {
-		e.Err = err
-		return
-	}
-	obj, err := Get(e.FSM, discord.EventObject)
-	if err != nil {
+	if _, err := Get(e.FSM, discord.Username); err != nil {
 		e.Err = err
 		return
 	}
-	event, ok := obj.(discord.Event)
-	if !ok {
-		e.Err = fmt.Errorf("cannot get event")
-		return
-	}
 
-	if err = s.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.MenuOption, 60*time.Second); err != nil {
+	if err := s.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.MenuOption, promptTimeout); err != nil {
 		e.Err = err
 		return
 	}
-	err = SelectRole(e, s.session, s.interactionCreate, &event, fmt.Sprintf("%v", user))
-	if err != nil {
+	if err := SelectRole(e); err != nil {
 		eventErr := e.FSM.Event(ctx, SignUpRetry.String())
 		if eventErr != nil {
 			e.Err = eventErr
@@ -72,6 +62,7 @@ type SignUpRetryState struct {
 	session           *discordgo.Session
 	interactionCreate *discordgo.InteractionCreate
 	channel           *discordgo.Channel
+	printer           *discord.Printer
 
 	inputHandler *InputHandler
 }
@@ -81,37 +72,26 @@ func NewSignUpRetryState(o discord.Options) *SignUpRetryState {
 		session:           o.Session,
 		interactionCreate: o.InteractionCreate,
 		channel:           o.Channel,
+		printer:           o.Printer,
 		inputHandler:      NewInputHandler(&o),
 	}
 }
 
 func (r *SignUpRetryState) OnState(ctx context.Context, e *fsm.Event) {
-	if _, err := r.session.ChannelMessageSend(r.channel.ID, discord.InvalidEntryText); err != nil {
+	if _, err := r.session.ChannelMessageSend(r.channel.ID, r.printer.T(discord.InvalidEntryText)); err != nil {
 		e.Err = err
 		return
 	}
-	user, err := Get(e.FSM, discord.Username)
-	if err != nil {
-		e.Err = err
-		return
-	}
-	obj, err := Get(e.FSM, discord.EventObject)
-	if err != nil {
+	if _, err := Get(e.FSM, discord.Username); err != nil {
 		e.Err = err
 		return
 	}
-	event, ok := obj.(discord.Event)
-	if !ok {
-		e.Err = fmt.Errorf("cannot get event")
-		return
-	}
 
-	if err = r.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.MenuOption, 60*time.Second); err != nil {
+	if err := r.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.MenuOption, promptTimeout); err != nil {
 		e.Err = err
 		return
 	}
-	err = SelectRole(e, r.session, r.interactionCreate, &event, fmt.Sprintf("%v", user))
-	if err != nil {
+	if err := SelectRole(e); err != nil {
 		eventErr := e.FSM.Event(ctx, SelfTransition.String())
 		if eventErr != nil {
 			e.Err = fmt.Errorf("%v: %v", err, eventErr)
@@ -120,20 +100,23 @@ func (r *SignUpRetryState) OnState(ctx context.Context, e *fsm.Event) {
 	}
 }
 
-func SelectRole(e *fsm.Event, s *discordgo.Session, ic *discordgo.InteractionCreate, event *discord.Event, name string) error {
+// SelectRole records the signup option chosen for the user. The response is added to the latest version of the event
+// once the edit is processed.
+func SelectRole(e *fsm.Event) error {
 	val, err := Get(e.FSM, discord.MenuOption)
 	if err != nil {
 		return err
 	}
 
-	opts := map[string]func(s *discordgo.Session, ic *discordgo.InteractionCreate, name string) error{
-		"1": event.ToggleAccept,
-		"2": event.ToggleDecline,
-		"3": event.ToggleTentative,
+	opts := map[string]role.FieldType{
+		"1": role.AcceptedField,
+		"2": role.DeclinedField,
+		"3": role.TentativeField,
 	}
-	option, ok := opts[val.(string)]
+	field, ok := opts[val.(string)]
 	if !ok {
 		return fmt.Errorf("cannot find %s response", e.FSM.Current())
 	}
-	return option(s, ic, name)
+	e.FSM.SetMetadata(discord.Response.String(), field)
+	return nil
 }
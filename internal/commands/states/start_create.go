@@ -13,6 +13,7 @@ type StartCreateState struct {
 	session           *discordgo.Session
 	interactionCreate *discordgo.InteractionCreate
 	channel           *discordgo.Channel
+	printer           *discord.Printer
 
 	responseFunc func(*discordgo.Interaction, *discordgo.InteractionResponse) error
 }
@@ -22,13 +23,14 @@ func NewStartCreateState(o discord.Options) *StartCreateState {
 		session:           o.Session,
 		interactionCreate: o.InteractionCreate,
 		channel:           o.Channel,
+		printer:           o.Printer,
 
 		responseFunc: o.Session.InteractionRespond,
 	}
 }
 
 func (s *StartCreateState) OnState(_ context.Context, e *fsm.Event) {
-	err := s.responseFunc(s.interactionCreate.Interaction, discord.CreateEventMessage(s.interactionCreate.Interaction.GuildID, s.channel.ID))
+	err := s.responseFunc(s.interactionCreate.Interaction, discord.CreateEventMessage(s.printer, s.interactionCreate.Interaction.GuildID, s.channel.ID))
 	if err != nil {
 		e.Err = err
 		return
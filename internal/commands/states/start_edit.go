@@ -6,7 +6,6 @@ import (
 	"github.com/GuessWhoSamFoo/fsm"
 	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/discord"
 	"github.com/bwmarrin/discordgo"
-	"time"
 )
 
 const EditAction = "modification"
@@ -15,6 +14,7 @@ type StartEditState struct {
 	session           *discordgo.Session
 	interactionCreate *discordgo.InteractionCreate
 	channel           *discordgo.Channel
+	printer           *discord.Printer
 
 	inputHandler *InputHandler
 }
@@ -24,6 +24,7 @@ func NewStartEditState(o discord.Options) *StartEditState {
 		session:           o.Session,
 		interactionCreate: o.InteractionCreate,
 		channel:           o.Channel,
+		printer:           o.Printer,
 		inputHandler:      NewInputHandler(&o),
 	}
 }
@@ -35,7 +36,7 @@ func (s *StartEditState) OnState(ctx context.Context, e *fsm.Event) {
 		return
 	}
 	if s.interactionCreate.Interaction.Member.User.Username != event.Owner && s.interactionCreate.Interaction.Member.Permissions&discordgo.PermissionManageEvents == 0 {
-		if _, err := s.session.ChannelMessageSendEmbed(s.channel.ID, discord.EditInsufficientPermissionMessage); err != nil {
+		if _, err := s.session.ChannelMessageSendEmbed(s.channel.ID, s.printer.Embed(discord.EditInsufficientPermissionMessage)); err != nil {
 			e.Err = fmt.Errorf("failed to send message: %v", err)
 			return
 		}
@@ -44,13 +45,21 @@ func (s *StartEditState) OnState(ctx context.Context, e *fsm.Event) {
 	}
 	e.FSM.SetMetadata(discord.Action.String(), EditAction)
 	event.DiscordLink = fmt.Sprintf("https://discord.com/channels/%s/%s/%s", s.interactionCreate.GuildID, s.interactionCreate.Interaction.ChannelID, s.interactionCreate.Interaction.Message.ID)
+	// Ended events can only have attendance taken
+	if event.Archived {
+		e.FSM.SetMetadata(discord.EventObject.String(), *event)
+		if err = e.FSM.Event(ctx, TakeAttendance.String()); err != nil {
+			e.Err = err
+		}
+		return
+	}
 
-	if _, err := s.session.ChannelMessageSendEmbed(s.channel.ID, &discord.EnterEditOptionMessage); err != nil {
+	if _, err := s.session.ChannelMessageSendEmbed(s.channel.ID, s.printer.Embed(&discord.EnterEditOptionMessage)); err != nil {
 		e.Err = fmt.Errorf("failed to send message: %v", err)
 		return
 	}
 
-	if err = s.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.MenuOption, 60*time.Second); err != nil {
+	if err = s.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.MenuOption, promptTimeout); err != nil {
 		e.Err = err
 		return
 	}
@@ -74,6 +83,7 @@ type StartEditRetryState struct {
 	session           *discordgo.Session
 	interactionCreate *discordgo.InteractionCreate
 	channel           *discordgo.Channel
+	printer           *discord.Printer
 
 	inputHandler *InputHandler
 }
@@ -83,12 +93,13 @@ func NewStartEditRetryState(o discord.Options) *StartEditRetryState {
 		session:           o.Session,
 		interactionCreate: o.InteractionCreate,
 		channel:           o.Channel,
+		printer:           o.Printer,
 		inputHandler:      NewInputHandler(&o),
 	}
 }
 
 func (r *StartEditRetryState) OnState(ctx context.Context, e *fsm.Event) {
-	if _, err := r.session.ChannelMessageSend(r.channel.ID, discord.InvalidEntryText); err != nil {
+	if _, err := r.session.ChannelMessageSend(r.channel.ID, r.printer.T(discord.InvalidEntryText)); err != nil {
 		e.Err = err
 		return
 	}
@@ -98,7 +109,7 @@ func (r *StartEditRetryState) OnState(ctx context.Context, e *fsm.Event) {
 		return
 	}
 
-	if err := r.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.MenuOption, 60*time.Second); err != nil {
+	if err := r.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.MenuOption, promptTimeout); err != nil {
 		e.Err = err
 		return
 	}
@@ -129,6 +140,8 @@ func EditTypeSelect(e *fsm.Event) (string, error) {
 		"1": ModifyEvent,
 		"2": RemoveResponse,
 		"3": AddResponse,
+		"4": TakeAttendance,
+		"5": MessageAttendees,
 	}
 	option, ok := opts[val.(string)]
 	if !ok {
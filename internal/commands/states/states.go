@@ -3,17 +3,34 @@ package states
 type chatState string
 
 const (
-	StartCreate      chatState = "startCreate"
-	AddTitle         chatState = "addTitle"
-	AddDescription   chatState = "addDescription"
-	SetAttendeeLimit chatState = "setAttendeeLimit"
-	SetAttendeeRetry chatState = "setAttendeeRetry"
-	SetDate          chatState = "setDate"
-	SetDateRetry     chatState = "setDateRetry"
-	SetLocation      chatState = "setLocation"
-	SetDuration      chatState = "setDuration"
-	SetDurationRetry chatState = "setDurationRetry"
-	CreateEvent      chatState = "createEvent"
+	StartCreate          chatState = "startCreate"
+	AddTitle             chatState = "addTitle"
+	AddDescription       chatState = "addDescription"
+	SetAttendeeLimit     chatState = "setAttendeeLimit"
+	SetAttendeeRetry     chatState = "setAttendeeRetry"
+	SetDate              chatState = "setDate"
+	SetDateRetry         chatState = "setDateRetry"
+	ConfirmDate          chatState = "confirmDate"
+	ConfirmDateRetry     chatState = "confirmDateRetry"
+	SetLocation          chatState = "setLocation"
+	SetDuration          chatState = "setDuration"
+	SetDurationRetry     chatState = "setDurationRetry"
+	SetDeadline          chatState = "setDeadline"
+	SetDeadlineRetry     chatState = "setDeadlineRetry"
+	SetGuests            chatState = "setGuests"
+	SetGuestsRetry       chatState = "setGuestsRetry"
+	SetCategory          chatState = "setCategory"
+	SetCategoryRetry     chatState = "setCategoryRetry"
+	ResumeDraft          chatState = "resumeDraft"
+	ResumeDraftRetry     chatState = "resumeDraftRetry"
+	ReviewEvent          chatState = "reviewEvent"
+	ReviewEventRetry     chatState = "reviewEventRetry"
+	ReviewField          chatState = "reviewField"
+	ReviewFieldRetry     chatState = "reviewFieldRetry"
+	SaveDraft            chatState = "saveDraft"
+	SchedulePublish      chatState = "schedulePublish"
+	SchedulePublishRetry chatState = "schedulePublishRetry"
+	CreateEvent          chatState = "createEvent"
 
 	StartEdit           chatState = "startEdit"
 	StartEditRetry      chatState = "startEditRetry"
@@ -24,6 +41,17 @@ const (
 	ContinueEdit        chatState = "continueEdit"
 	ContinueEditRetry   chatState = "continueEditRetry"
 	ProcessEdit         chatState = "processEdit"
+	TakeAttendance      chatState = "takeAttendance"
+	TakeAttendanceRetry chatState = "takeAttendanceRetry"
+	SetChannel          chatState = "setChannel"
+	SetChannelRetry     chatState = "setChannelRetry"
+	SetOwner            chatState = "setOwner"
+	SetOwnerRetry       chatState = "setOwnerRetry"
+
+	MessageAttendees       chatState = "messageAttendees"
+	MessageAttendeesRetry  chatState = "messageAttendeesRetry"
+	EnterAnnouncement      chatState = "enterAnnouncement"
+	EnterAnnouncementRetry chatState = "enterAnnouncementRetry"
 
 	AddResponse      chatState = "addResponse"
 	UnknownUser      chatState = "unknownUser"
@@ -34,6 +62,7 @@ const (
 	Idle    chatState = "idle"
 	Cancel  chatState = "cancel"
 	Timeout chatState = "timeout"
+	Back    chatState = "back"
 
 	SelfTransition chatState = "selfTransition"
 )
@@ -2,8 +2,10 @@ package states
 
 import (
 	"context"
+	"fmt"
 	"github.com/GuessWhoSamFoo/fsm"
 	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/discord"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/metrics"
 )
 
 type TimeoutState struct {
@@ -19,7 +21,10 @@ func NewTimeoutState(o discord.Options) *TimeoutState {
 }
 
 func (t *TimeoutState) OnState(_ context.Context, e *fsm.Event) {
-	_, err := t.Session.ChannelMessageSend(t.Channel.ID, "I'm not sure where you went. We can try this again later.")
+	if action, err := Get(e.FSM, discord.Action); err == nil {
+		metrics.RecordFSMOutcome(fmt.Sprint(action), metrics.TimedOut)
+	}
+	_, err := t.Session.ChannelMessageSend(t.Channel.ID, t.Printer.T("I'm not sure where you went. We can try this again later."))
 	if err != nil {
 		e.Err = err
 	}
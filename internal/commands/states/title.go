@@ -5,13 +5,13 @@ import (
 	"github.com/GuessWhoSamFoo/fsm"
 	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/discord"
 	"github.com/bwmarrin/discordgo"
-	"time"
 )
 
 type AddTitleState struct {
 	session           *discordgo.Session
 	interactionCreate *discordgo.InteractionCreate
 	channel           *discordgo.Channel
+	printer           *discord.Printer
 
 	inputHandler *InputHandler
 }
@@ -21,18 +21,19 @@ func NewAddTitleState(o discord.Options) *AddTitleState {
 		session:           o.Session,
 		interactionCreate: o.InteractionCreate,
 		channel:           o.Channel,
+		printer:           o.Printer,
 		inputHandler:      NewInputHandler(&o),
 	}
 }
 
 func (a *AddTitleState) OnState(ctx context.Context, e *fsm.Event) {
-	_, err := a.session.ChannelMessageSendEmbed(a.channel.ID, &discord.EnterTitleMessage)
+	_, err := a.session.ChannelMessageSendEmbed(a.channel.ID, a.printer.Embed(&discord.EnterTitleMessage))
 	if err != nil {
 		e.Err = err
 		return
 	}
 
-	err = a.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.Title, 60*time.Second)
+	err = a.inputHandler.AwaitInputOrTimeout(ctx, e.FSM, discord.Title, promptTimeout)
 	if err != nil {
 		e.Err = err
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/GuessWhoSamFoo/fsm"
 	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states"
+	"slices"
 )
 
 type FSMState interface {
@@ -24,3 +25,18 @@ func (a ActionType) String() string {
 func InitState() string {
 	return states.Idle.String()
 }
+
+// withBack lets the prompts of a sequence go back to the prompt answered before. Going back passes through the back
+// state, from which any of the prompts can be asked again.
+func withBack(events fsm.Events, prompts []string) fsm.Events {
+	for i, event := range events {
+		if slices.Contains(prompts, event.Name) {
+			events[i].Src = append(event.Src, states.Back.String())
+		}
+	}
+	return append(events, fsm.EventDesc{
+		Name: states.Back.String(),
+		Src:  prompts,
+		Dst:  states.Back.String(),
+	})
+}
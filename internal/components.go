@@ -2,193 +2,260 @@ package internal
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"github.com/GuessWhoSamFoo/fsm"
 	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands"
 	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states"
 	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/discord"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/role"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/errs"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/logging"
 	"github.com/GuessWhoSamFoo/gang-gang-bot/pkg/util"
 	"github.com/bwmarrin/discordgo"
-	"log"
+	"strconv"
+	"time"
 )
 
-func (sm *StateManager) AcceptHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseUpdateMessage,
-	}); err != nil {
-		log.Println(err)
-	}
-	e, err := discord.GetEventFromMessage(i.Message)
-	if err != nil {
-		log.Printf("failed to get event: %v", err)
-		return
-	}
-
-	if err := e.ToggleAccept(s, i, i.Member.User.Username); err != nil {
-		log.Printf("toggle accept: %v", err)
-		return
+func (sm *StateManager) AcceptHandler(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	if err := sm.toggleResponse(s, i, role.AcceptedField); err != nil {
+		return fmt.Errorf("toggle accept: %w", err)
 	}
+	logging.FromInteraction(i).Info("User accepted event")
+	return nil
+}
 
-	embed, err := discord.ConvertEventToMessageEmbed(e)
-	if err != nil {
-		log.Printf("failed to convert event: %v", err)
-		return
+func (sm *StateManager) DeclineHandler(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	if err := sm.toggleResponse(s, i, role.DeclinedField); err != nil {
+		return fmt.Errorf("toggle decline: %w", err)
 	}
+	logging.FromInteraction(i).Info("User declined event")
+	return nil
+}
 
-	if _, err := s.ChannelMessageEditEmbed(i.ChannelID, i.Message.ID, embed); err != nil {
-		log.Printf("failed to edit embed: %v", err)
-		return
+func (sm *StateManager) TentativeHandler(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	if err := sm.toggleResponse(s, i, role.TentativeField); err != nil {
+		return fmt.Errorf("toggle tentative: %w", err)
 	}
-	log.Printf("User: %s accepted event %s", i.Member.User.Username, fmt.Sprintf("%s/%s", i.ChannelID, i.Message.ID))
-	return
+	logging.FromInteraction(i).Info("User marked tentative")
+	return nil
 }
 
-func (sm *StateManager) DeclineHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseUpdateMessage,
-	}); err != nil {
-		log.Println(err)
-	}
-	e, err := discord.GetEventFromMessage(i.Message)
+// UndoHandler restores the response a user had before clicking a signup button
+func (sm *StateManager) UndoHandler(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	channelID, messageID, prev, err := discord.ParseUndoID(i.MessageComponentData().CustomID)
 	if err != nil {
-		log.Printf("failed to get event: %v", err)
-		return
+		return err
 	}
-	if err := e.ToggleDecline(s, i, i.Member.User.Username); err != nil {
-		log.Printf("toggle decline: %v", err)
-		return
+	name := i.Member.User.Username
+	var promoted []string
+	event, err := sm.updateEvent(s, i.GuildID, channelID, messageID, func(e *discord.Event) (err error) {
+		if err = checkSignupsOpen(e, e.RoleGroup.GetResponse(name), prev); err != nil {
+			return err
+		}
+		promoted, err = e.RestoreResponse(name, role.Response{Field: prev})
+		return err
+	})
+	if errors.Is(err, role.ErrEventFull) {
+		return errs.NewValidationf("%s is full", prev)
 	}
-
-	embed, err := discord.ConvertEventToMessageEmbed(e)
 	if err != nil {
-		log.Printf("failed to convert event: %v", err)
-		return
+		return err
 	}
-
-	if _, err := s.ChannelMessageEditEmbed(i.ChannelID, i.Message.ID, embed); err != nil {
-		log.Printf("cannot decline: %v", err)
-		return
+	if prev == role.AcceptedField && sm.Store != nil {
+		if err = sm.Store.ClearLateCancellation(name, messageID); err != nil {
+			logging.FromInteraction(i).Error("cannot clear late cancellation", logging.ErrorKey, err)
+		}
+	}
+	if err = notifyPromoted(s, sm.memberPrinter, i.GuildID, event, promoted); err != nil {
+		return err
+	}
+	p := sm.printer(i)
+	title := p.T("Undone. You haven't responded to this event")
+	if cur := event.RoleGroup.GetResponse(name); cur.Field != "" {
+		title = p.Tf("Undone. %s", discord.SignupStatusText(p, role.Response{}, cur))
+	}
+	if _, err = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Embeds: &[]*discordgo.MessageEmbed{
+			{
+				Title: title,
+				Color: discord.Purple,
+			},
+		},
+		Components: &[]discordgo.MessageComponent{},
+	}); err != nil {
+		return fmt.Errorf("failed to edit response: %w", err)
 	}
-	log.Printf("User: %s declined event %s", i.Member.User.Username, fmt.Sprintf("%s/%s", i.ChannelID, i.Message.ID))
-	return
+	logging.FromInteraction(i).Info("User undid response")
+	return nil
 }
 
-func (sm *StateManager) TentativeHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseUpdateMessage,
-	}); err != nil {
-		log.Println(err)
+// GuestsHandler sets how many guests a member is bringing from the select menu sent after accepting an event
+func (sm *StateManager) GuestsHandler(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	channelID, messageID, err := discord.ParseGuestsID(i.MessageComponentData().CustomID)
+	if err != nil {
+		return err
 	}
-	e, err := discord.GetEventFromMessage(i.Message)
+	values := i.MessageComponentData().Values
+	if len(values) != 1 {
+		return fmt.Errorf("expected 1 guests value: got %d", len(values))
+	}
+	guests, err := strconv.Atoi(values[0])
 	if err != nil {
-		log.Printf("failed to get event: %v", err)
-		return
+		return fmt.Errorf("invalid guests value: %w", err)
 	}
-
-	if err := e.ToggleTentative(s, i, i.Member.User.Username); err != nil {
-		log.Printf("toggle tentative: %v", err)
-		return
+	if err = sm.setGuests(s, i, channelID, messageID, guests); err != nil {
+		return err
+	}
+	if _, err = s.FollowupMessageCreate(i.Interaction, false, &discordgo.WebhookParams{
+		Embeds: []*discordgo.MessageEmbed{
+			{
+				Title: discord.GuestsStatusText(sm.printer(i), guests),
+				Color: discord.Purple,
+			},
+		},
+		Flags: discordgo.MessageFlagsEphemeral,
+	}); err != nil {
+		return fmt.Errorf("failed to send guests status: %w", err)
 	}
+	logging.FromInteraction(i).Info("User set guests", "guests", guests)
+	return nil
+}
 
-	embed, err := discord.ConvertEventToMessageEmbed(e)
+// MuteAlertsHandler turns off late cancellation alerts for the event a DM was sent about
+func (sm *StateManager) MuteAlertsHandler(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	channelID, messageID, err := discord.ParseMuteAlertsID(i.MessageComponentData().CustomID)
 	if err != nil {
-		log.Printf("failed to convert event: %v", err)
-		return
+		return err
 	}
-
-	if _, err := s.ChannelMessageEditEmbed(i.ChannelID, i.Message.ID, embed); err != nil {
-		log.Printf("cannot set tenative: %v", err)
-		return
+	if sm.Store == nil {
+		return fmt.Errorf("store is nil")
+	}
+	if err = sm.Store.MuteAlerts(messageID, true); err != nil {
+		return fmt.Errorf("cannot mute alerts: %w", err)
+	}
+	if _, err = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Components: &[]discordgo.MessageComponent{},
+	}); err != nil {
+		return fmt.Errorf("failed to edit response: %w", err)
+	}
+	if _, err = s.FollowupMessageCreate(i.Interaction, false, &discordgo.WebhookParams{
+		Embeds: []*discordgo.MessageEmbed{
+			{
+				Title: sm.printer(i).T("Alerts muted for this event"),
+				Color: discord.Purple,
+			},
+		},
+	}); err != nil {
+		return fmt.Errorf("failed to send mute status: %w", err)
 	}
-	log.Printf("User: %s marked tentative %s", i.Member.User.Username, fmt.Sprintf("%s/%s", i.ChannelID, i.Message.ID))
-	return
+	logging.FromInteraction(i).Info("User muted alerts", logging.EventKey, logging.EventPath(channelID, messageID))
+	return nil
 }
 
-func (sm *StateManager) EditHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	// TODO: Check server permissions before edit
-	if sm.CalendarClient == nil {
-		log.Println("calendar client is nil")
-		return
+// LockHandler locks or unlocks signups for an event. Members can still drop out of a locked event.
+func (sm *StateManager) LockHandler(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	event, err := sm.updateEvent(s, i.GuildID, i.ChannelID, i.Message.ID, func(e *discord.Event) error {
+		e.Locked = !e.Locked
+		return nil
+	})
+	if err != nil {
+		return err
 	}
-
-	if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseUpdateMessage,
+	p := sm.printer(i)
+	title := p.T("Signups unlocked")
+	if event.Locked {
+		title = p.T("Signups locked. Members can still drop out.")
+	}
+	if _, err = s.FollowupMessageCreate(i.Interaction, false, &discordgo.WebhookParams{
+		Embeds: []*discordgo.MessageEmbed{
+			{
+				Title: title,
+				Color: discord.Purple,
+			},
+		},
+		Flags: discordgo.MessageFlagsEphemeral,
 	}); err != nil {
-		log.Println(err)
+		return fmt.Errorf("failed to send lock status: %w", err)
 	}
+	logging.FromInteraction(i).Info("User toggled signup lock", "locked", event.Locked)
+	return nil
+}
 
+func (sm *StateManager) EditHandler(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	logger := logging.FromInteraction(i)
 	c, err := s.UserChannelCreate(i.Member.User.ID)
 	if err != nil {
-		log.Printf("cannot create channel: %v", err)
-		return
+		return fmt.Errorf("cannot create channel: %w", err)
 	}
 
-	if sm.ActiveMap.HasUser(i.Member.User.ID) {
-		discord.NotifyCommandInProgress(s, i)
-		return
-	}
-	sm.AddUser(i.Member.User.ID)
-	defer sm.RemoveUser(i.Member.User.ID)
 	ctx := context.Background()
 	opts := discord.Options{
 		Session:           s,
 		InteractionCreate: i,
 		Channel:           c,
+		Store:             sm.Store,
+		LateCancelAlerts:  time.Duration(sm.Config.Alerts.LateCancelHours) * time.Hour,
+		Categories:        sm.Categories,
+		Printer:           sm.printer(i),
+		UserPrinter:       sm.memberPrinter,
+		Timeouts:          sm.Config.PromptTimeouts(),
+		LockEvent:         sm.LockEvent,
 		CalendarClient:    sm.CalendarClient,
 	}
 
 	f, err := NewDefaultStateFactory(opts).Factory(commands.EditType)
 	if err != nil {
-		return
+		return err
 	}
 
-	if err = f.Event(ctx, states.StartEdit.String()); err != nil {
-		log.Println(err)
-		return
+	if err = runSequence(ctx, f, states.StartEdit.String(), nil); err != nil {
+		return sequenceError(logger, f, err)
+	}
+	// Messaging attendees and taking attendance do not change the event
+	if announced(f) {
+		logger.Info("User messaged attendees")
+		return nil
+	}
+	if tookAttendance(f) {
+		logger.Info("User took attendance")
+		return nil
 	}
 	if err = f.Event(ctx, states.ProcessEdit.String()); err != nil {
-		log.Println(err)
-		return
+		return sequenceError(logger, f, err)
 	}
-	log.Printf("User: %s edited event %s", i.Member.User.Username, fmt.Sprintf("%s/%s", i.ChannelID, i.Message.ID))
+	logger.Info("User edited event")
+	return nil
 }
 
-func (sm *StateManager) DeleteHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	// TODO: Check server permissions before delete
-	if sm == nil || sm.CalendarClient == nil {
-		log.Printf("cannot find commands manager email client")
-		return
-	}
-
-	if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseUpdateMessage,
-	}); err != nil {
-		log.Println(err)
-	}
+// announced reports whether an edit sequence ended after messaging attendees
+func announced(f *fsm.FSM) bool {
+	current := f.Current()
+	return current == states.EnterAnnouncement.String() || current == states.EnterAnnouncementRetry.String()
+}
 
-	c, err := s.UserChannelCreate(i.Member.User.ID)
-	if err != nil {
-		log.Printf("failed to get channel: %v", err)
-		return
-	}
+// tookAttendance reports whether an edit sequence ended after taking attendance
+func tookAttendance(f *fsm.FSM) bool {
+	current := f.Current()
+	return current == states.TakeAttendance.String() || current == states.TakeAttendanceRetry.String()
+}
 
+func (sm *StateManager) DeleteHandler(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	logger := logging.FromInteraction(i)
 	e, err := discord.GetEventFromMessage(i.Message)
 	if err != nil {
-		log.Printf("failed to get event: %v", err)
-		return
+		return fmt.Errorf("failed to get event: %w", err)
 	}
-	if i.Member.User.Username != e.Owner && i.Interaction.Member.Permissions&discordgo.PermissionManageEvents == 0 {
-		if _, err := s.ChannelMessageSendEmbed(c.ID, discord.DeleteInsufficientPermissionMessage); err != nil {
-			log.Printf("failed to send message: %v", err)
-			return
-		}
-		log.Printf("insufficient permissions to delete %s", i.Interaction.Message.ID)
-		return
+	c, err := s.UserChannelCreate(i.Member.User.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get channel: %w", err)
 	}
 
+	p := sm.printer(i)
 	if _, err := s.ChannelMessageSendComplex(c.ID, &discordgo.MessageSend{
 		Embeds: []*discordgo.MessageEmbed{
 			{
-				Title:       "Confirm event deletion",
+				Title:       p.T("Confirm event deletion"),
 				Description: fmt.Sprintf("[%s](https://discord.com/channels/%s/%s/%s)", e.Title, i.GuildID, i.ChannelID, i.Message.ID),
 				Color:       discord.Purple,
 			},
@@ -197,7 +264,7 @@ func (sm *StateManager) DeleteHandler(s *discordgo.Session, i *discordgo.Interac
 			discordgo.ActionsRow{
 				Components: []discordgo.MessageComponent{
 					discordgo.Button{
-						Label:    "Delete this event",
+						Label:    p.T("Delete this event"),
 						Style:    discordgo.DangerButton,
 						CustomID: "confirmDelete",
 					},
@@ -205,65 +272,50 @@ func (sm *StateManager) DeleteHandler(s *discordgo.Session, i *discordgo.Interac
 			},
 		},
 	}); err != nil {
-		log.Printf("failed to send message: %v", err)
-		return
+		return fmt.Errorf("failed to send message: %w", err)
 	}
-	log.Printf("User: %s deleted event %s", i.Member.User.Username, fmt.Sprintf("%s/%s", i.ChannelID, i.Message.ID))
+	logger.Info("User deleted event")
+	return nil
 }
 
-func (sm *StateManager) ConfirmDeleteHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	if sm == nil || sm.CalendarClient == nil {
-		log.Printf("cannot find commands manager email client")
-		return
-	}
-
-	if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseUpdateMessage,
-	}); err != nil {
-		log.Println(err)
-	}
-
+func (sm *StateManager) ConfirmDeleteHandler(s *discordgo.Session, i *discordgo.InteractionCreate) error {
 	e, err := discord.GetEventFromMessage(i.Message)
 	if err != nil {
-		log.Printf("failed to parse event from interaction: %v", err)
-		return
+		return fmt.Errorf("failed to parse event from interaction: %w", err)
 	}
 
 	url, err := util.GetLinkFromDeleteDescription(e.Description)
 	if err != nil {
-		log.Printf("failed to parse link from description: %v", err)
-		return
+		return fmt.Errorf("failed to parse link from description: %w", err)
 	}
 
-	_, channelID, messageID, err := util.GetIDsFromDiscordLink(url)
+	// The confirmation is sent in DMs, so the guild comes from the link to the event rather than the interaction
+	guildID, channelID, messageID, err := util.GetIDsFromDiscordLink(url)
 	if err != nil {
-		log.Printf("failed to get ID from link: %v", err)
-		return
+		return fmt.Errorf("failed to get ID from link: %w", err)
 	}
 
 	msg, err := s.ChannelMessage(channelID, messageID)
 	if err != nil {
-		return
+		return fmt.Errorf("failed to get message: %w", err)
 	}
 	cEvent, err := discord.GetEventFromMessage(msg)
 	if err != nil {
-		return
+		return fmt.Errorf("failed to get event: %w", err)
 	}
 
 	if err := sm.CalendarClient.DeleteEvent(cEvent); err != nil {
-		log.Printf("cannot delete google event: %v", err)
-		return
+		return fmt.Errorf("cannot delete google event: %w", err)
 	}
 
 	if err = s.ChannelMessageDelete(channelID, messageID); err != nil {
-		log.Printf("failed to delete message: %v", err)
-		return
+		return fmt.Errorf("failed to delete message: %w", err)
 	}
 
 	if _, err := s.ChannelMessageEditComplex(&discordgo.MessageEdit{
 		Embeds: []*discordgo.MessageEmbed{
 			{
-				Title: "Event deleted",
+				Title: sm.printer(i).T("Event deleted"),
 				Color: discord.Purple,
 			},
 		},
@@ -271,23 +323,29 @@ func (sm *StateManager) ConfirmDeleteHandler(s *discordgo.Session, i *discordgo.
 		Channel:    i.Message.ChannelID,
 		Components: []discordgo.MessageComponent{},
 	}); err != nil {
-		log.Printf("failed to edit message: %v", err)
-		return
+		return fmt.Errorf("failed to edit message: %w", err)
+	}
+
+	if cEvent.ScheduledEventID != "" {
+		if err = s.GuildScheduledEventDelete(guildID, cEvent.ScheduledEventID); err != nil {
+			return fmt.Errorf("failed to delete guild event: %w", err)
+		}
+		return nil
 	}
 
-	events, err := s.GuildScheduledEvents(sm.Config.Discord.GuildID, false)
+	// Events created before scheduled event IDs were stored can only be matched by their link
+	events, err := s.GuildScheduledEvents(guildID, false)
 	if err != nil {
-		log.Printf("cannot get guild events: %v", err)
-		return
+		return fmt.Errorf("cannot get guild events: %w", err)
 	}
 
 	for _, guildEvent := range events {
-		if guildEvent.Description == cEvent.Description {
-			err = s.GuildScheduledEventDelete(sm.Config.Discord.GuildID, guildEvent.ID)
+		if guildEvent.Description == url {
+			err = s.GuildScheduledEventDelete(guildID, guildEvent.ID)
 			if err != nil {
-				log.Printf("failed to delete guild event: %v", err)
-				return
+				return fmt.Errorf("failed to delete guild event: %w", err)
 			}
 		}
 	}
+	return nil
 }
@@ -2,19 +2,51 @@ package internal
 
 import (
 	"fmt"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/discord"
 	"gopkg.in/yaml.v3"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 )
 
 const (
 	configFileName     = "config.yaml"
 	credentialFileName = "credentials.json"
+
+	defaultServerAddress = ":8080"
+	defaultStorePath     = "store.json"
+
+	defaultRetentionDays = 365
+
+	defaultLateCancelHours      = 24
+	defaultLateCancelDays       = 90
+	defaultWaitlistReleaseHours = 24
+	defaultAlertHours           = 24
+
+	defaultReminderSeconds = 20
 )
 
 type Config struct {
 	Discord struct {
 		GuildID string `yaml:"guild_id"`
+		// SyncInterested mirrors users marked as "Interested" in a guild scheduled event into the Tentative list
+		SyncInterested bool `yaml:"sync_interested"`
+		// ImportEvents publishes signup posts for guild scheduled events created outside the bot
+		ImportEvents bool `yaml:"import_events"`
+		// EventsChannelID is the channel imported events and new events created without a channel are posted to
+		EventsChannelID string `yaml:"events_channel_id"`
+		// CategoryChannels routes new events to a channel by category, such as workshops to #workshops
+		CategoryChannels map[string]string `yaml:"category_channels"`
+		// ArchiveChannelID receives a copy of each event once it ends
+		ArchiveChannelID string `yaml:"archive_channel_id"`
+		// EventThreads attaches a discussion thread to each new event for its attendees
+		EventThreads bool `yaml:"event_threads"`
+		// TextButtons labels the signup buttons of event posts in words instead of emoji
+		TextButtons bool `yaml:"text_buttons"`
+		// Locale is the language used when a member's own language has no translation, such as es-ES
+		Locale string `yaml:"locale"`
 	}
 	Google struct {
 		CalendarID  string `yaml:"calendar_id"`
@@ -23,11 +55,71 @@ type Config struct {
 	Secret struct {
 		Token string `yaml:"token"`
 	}
+	Log struct {
+		Level string `yaml:"level"`
+	}
+	Server struct {
+		// Address serves metrics and health checks
+		Address string `yaml:"address"`
+	}
+	// Rules are custom signup rules applied when members accept an event
+	Rules struct {
+		// LateCancelHours is how close to the start dropping out counts as a late cancellation
+		LateCancelHours int `yaml:"late_cancel_hours"`
+		// LateCancelLimit waitlists members with this many late cancellations in the last LateCancelDays. 0 disables it.
+		LateCancelLimit int `yaml:"late_cancel_limit"`
+		LateCancelDays  int `yaml:"late_cancel_days"`
+		// WaitlistReleaseHours is how close to the start waitlisted members are moved into open spots
+		WaitlistReleaseHours int `yaml:"waitlist_release_hours"`
+		// PriorityRoleIDs are Discord roles placed ahead of other members on waitlists
+		PriorityRoleIDs []string `yaml:"priority_role_ids"`
+		// WeeklyLimit is how many events a member can accept per week. 0 disables it.
+		WeeklyLimit int `yaml:"weekly_limit"`
+	}
+	// Alerts are DMs sent to organizers about changes to their events
+	Alerts struct {
+		// LateCancelHours is how close to the start organizers are told about members dropping out. 0 disables it.
+		LateCancelHours int `yaml:"late_cancel_hours"`
+	}
+	// Categories are the event categories organizers choose from. Defaults to social, workshop, performance and trip.
+	Categories []struct {
+		Name  string `yaml:"name"`
+		Emoji string `yaml:"emoji"`
+		// Color is a hex color for the event post such as "#E67E22"
+		Color string `yaml:"color"`
+		// CalendarColorID is a Google Calendar event color from "1" to "11"
+		CalendarColorID string `yaml:"calendar_color_id"`
+	} `yaml:"categories"`
+	// Prompts are the questions asked in DMs, such as when creating an event
+	Prompts struct {
+		// TimeoutSeconds is the least time a prompt waits for an answer. Prompts wait 60 seconds, or 10 minutes for long
+		// text such as descriptions, by default.
+		TimeoutSeconds int `yaml:"timeout_seconds"`
+		// StateTimeoutSeconds sets the wait of single prompts by state, such as addDescription: 1200
+		StateTimeoutSeconds map[string]int `yaml:"state_timeout_seconds"`
+		// ReminderSeconds is how long before a prompt times out the user is reminded to answer. 0 disables it.
+		ReminderSeconds int `yaml:"reminder_seconds"`
+	}
+	Store struct {
+		// Path is the JSON file for records which are not kept in event messages such as attendance
+		Path string `yaml:"path"`
+		// RetentionDays is how long late cancellations and announcements are kept. Late cancellations are kept for at
+		// least Rules.LateCancelDays. 0 keeps them forever.
+		RetentionDays int `yaml:"retention_days"`
+	}
 }
 
 // NewConfig gets the bot config from the directory of the executable's path
 func NewConfig() (*Config, error) {
 	config := &Config{}
+	config.Server.Address = defaultServerAddress
+	config.Store.Path = defaultStorePath
+	config.Store.RetentionDays = defaultRetentionDays
+	config.Rules.LateCancelHours = defaultLateCancelHours
+	config.Rules.LateCancelDays = defaultLateCancelDays
+	config.Rules.WaitlistReleaseHours = defaultWaitlistReleaseHours
+	config.Alerts.LateCancelHours = defaultAlertHours
+	config.Prompts.ReminderSeconds = defaultReminderSeconds
 
 	var err error
 	credentials := os.Getenv("GOOGLE_CREDENTIALS")
@@ -45,6 +137,70 @@ func NewConfig() (*Config, error) {
 		config.Google.CalendarID = calendarID
 	}
 
+	if sync := os.Getenv("DISCORD_SYNC_INTERESTED"); sync != "" {
+		config.Discord.SyncInterested, err = strconv.ParseBool(sync)
+		if err != nil {
+			return nil, fmt.Errorf("invalid DISCORD_SYNC_INTERESTED: %v", err)
+		}
+	}
+
+	if importEvents := os.Getenv("DISCORD_IMPORT_EVENTS"); importEvents != "" {
+		config.Discord.ImportEvents, err = strconv.ParseBool(importEvents)
+		if err != nil {
+			return nil, fmt.Errorf("invalid DISCORD_IMPORT_EVENTS: %v", err)
+		}
+	}
+	if channelID := os.Getenv("DISCORD_EVENTS_CHANNEL_ID"); channelID != "" {
+		config.Discord.EventsChannelID = channelID
+	}
+
+	if channelID := os.Getenv("DISCORD_ARCHIVE_CHANNEL_ID"); channelID != "" {
+		config.Discord.ArchiveChannelID = channelID
+	}
+	if threads := os.Getenv("DISCORD_EVENT_THREADS"); threads != "" {
+		config.Discord.EventThreads, err = strconv.ParseBool(threads)
+		if err != nil {
+			return nil, fmt.Errorf("invalid DISCORD_EVENT_THREADS: %v", err)
+		}
+	}
+	if textButtons := os.Getenv("DISCORD_TEXT_BUTTONS"); textButtons != "" {
+		config.Discord.TextButtons, err = strconv.ParseBool(textButtons)
+		if err != nil {
+			return nil, fmt.Errorf("invalid DISCORD_TEXT_BUTTONS: %v", err)
+		}
+	}
+	if locale := os.Getenv("DISCORD_LOCALE"); locale != "" {
+		config.Discord.Locale = locale
+	}
+	if timeout := os.Getenv("PROMPT_TIMEOUT_SECONDS"); timeout != "" {
+		config.Prompts.TimeoutSeconds, err = strconv.Atoi(timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid PROMPT_TIMEOUT_SECONDS: %v", err)
+		}
+	}
+	if reminder := os.Getenv("PROMPT_REMINDER_SECONDS"); reminder != "" {
+		config.Prompts.ReminderSeconds, err = strconv.Atoi(reminder)
+		if err != nil {
+			return nil, fmt.Errorf("invalid PROMPT_REMINDER_SECONDS: %v", err)
+		}
+	}
+	if path := os.Getenv("STORE_PATH"); path != "" {
+		config.Store.Path = path
+	}
+	if retention := os.Getenv("STORE_RETENTION_DAYS"); retention != "" {
+		config.Store.RetentionDays, err = strconv.Atoi(retention)
+		if err != nil {
+			return nil, fmt.Errorf("invalid STORE_RETENTION_DAYS: %v", err)
+		}
+	}
+
+	if level := os.Getenv("LOG_LEVEL"); level != "" {
+		config.Log.Level = level
+	}
+	if port := os.Getenv("PORT"); port != "" {
+		config.Server.Address = ":" + port
+	}
+
 	dig, token := os.Getenv("DISCORD_GUILD_ID"), os.Getenv("DISCORD_TOKEN")
 	if dig != "" && token != "" {
 		config.Discord.GuildID = dig
@@ -68,3 +224,40 @@ func NewConfig() (*Config, error) {
 	}
 	return config, nil
 }
+
+// EventCategories converts the configured categories, falling back to the defaults when there are none
+func (c *Config) EventCategories() ([]discord.Category, error) {
+	if len(c.Categories) == 0 {
+		return discord.DefaultCategories, nil
+	}
+	categories := make([]discord.Category, 0, len(c.Categories))
+	for _, category := range c.Categories {
+		if category.Name == "" {
+			return nil, fmt.Errorf("category is missing a name")
+		}
+		color, err := strconv.ParseInt(strings.TrimPrefix(category.Color, "#"), 16, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid color for category %s: %v", category.Name, err)
+		}
+		categories = append(categories, discord.Category{
+			Name:            category.Name,
+			Emoji:           category.Emoji,
+			Color:           int(color),
+			CalendarColorID: category.CalendarColorID,
+		})
+	}
+	return categories, nil
+}
+
+// PromptTimeouts converts the configured prompt timeouts
+func (c *Config) PromptTimeouts() discord.Timeouts {
+	timeouts := discord.Timeouts{
+		Default:  time.Duration(c.Prompts.TimeoutSeconds) * time.Second,
+		Reminder: time.Duration(c.Prompts.ReminderSeconds) * time.Second,
+		States:   make(map[string]time.Duration, len(c.Prompts.StateTimeoutSeconds)),
+	}
+	for state, seconds := range c.Prompts.StateTimeoutSeconds {
+		timeouts.States[state] = time.Duration(seconds) * time.Second
+	}
+	return timeouts
+}
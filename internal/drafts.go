@@ -0,0 +1,197 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/discord"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/errs"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/logging"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/metrics"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/store"
+	"github.com/bwmarrin/discordgo"
+	"log/slog"
+	"time"
+)
+
+const publishInterval = time.Minute
+
+// errDraftClaimed is returned when a draft was published or deleted by someone else before it could be claimed
+var errDraftClaimed = errors.New("draft was already published or deleted")
+
+// DraftsHandler lists the drafts and scheduled posts of the user with buttons to edit or cancel them
+func (sm *StateManager) DraftsHandler(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	if sm.Store == nil {
+		return fmt.Errorf("draft store is not configured")
+	}
+	return sm.showDrafts(s, i)
+}
+
+// DraftEditHandler opens a draft in the creation DMs at the preview, where it can be changed, published, saved or
+// scheduled again
+func (sm *StateManager) DraftEditHandler(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	logger := logging.FromInteraction(i)
+	d, err := sm.ownDraft(i)
+	if err != nil {
+		return err
+	}
+	opts := sm.createOptions(s, i)
+	opts.ChannelID = d.ChannelID
+	// The category can still change, so only a channel chosen with the command is checked up front
+	if opts.ChannelID != "" {
+		if err = discord.CheckCanPost(s, opts.ChannelID); err != nil {
+			return err
+		}
+	}
+
+	c, err := s.UserChannelCreate(i.Member.User.ID)
+	if err != nil {
+		return errs.NewUpstream("cannot create channel", err)
+	}
+	opts.Channel = c
+	ctx := context.Background()
+
+	f, err := NewDefaultStateFactory(opts).Factory(commands.CreateType)
+	if err != nil {
+		return err
+	}
+	if err = f.Event(ctx, states.StartCreate.String()); err != nil {
+		return sequenceError(logger, f, err)
+	}
+	discord.LoadDraft(f, d, sm.Categories)
+	if err = runSequence(ctx, f, states.ReviewEvent.String(), commands.CreateNext); err != nil {
+		return sequenceError(logger, f, err)
+	}
+	return nil
+}
+
+// DraftCancelHandler deletes a draft, which also cancels a scheduled post
+func (sm *StateManager) DraftCancelHandler(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	d, err := sm.ownDraft(i)
+	if err != nil {
+		return err
+	}
+	if err = sm.Store.DeleteDraft(d.ID); err != nil {
+		return fmt.Errorf("cannot delete draft: %w", err)
+	}
+	logging.FromInteraction(i).Info("User deleted draft", logging.EventKey, d.Title, "scheduled", d.Scheduled())
+	return sm.showDrafts(s, i)
+}
+
+// ownDraft gets the draft a button acts on. Only the organizer who saved a draft can change it.
+func (sm *StateManager) ownDraft(i *discordgo.InteractionCreate) (store.Draft, error) {
+	if sm.Store == nil {
+		return store.Draft{}, fmt.Errorf("draft store is not configured")
+	}
+	id, err := discord.ParseDraftActionID(i.MessageComponentData().CustomID)
+	if err != nil {
+		return store.Draft{}, err
+	}
+	d, ok := sm.Store.Draft(id)
+	if !ok {
+		return store.Draft{}, errs.NewValidation("That draft was already published or deleted")
+	}
+	if d.GuildID != i.GuildID || d.OwnerID != i.Member.User.ID {
+		return store.Draft{}, errs.NewPermission("You can only change your own drafts")
+	}
+	return d, nil
+}
+
+// showDrafts replaces the response to an interaction with the drafts of the user
+func (sm *StateManager) showDrafts(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	embed, components := discord.DraftsMessage(sm.Store.Drafts(i.GuildID, i.Member.User.ID))
+	if _, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Embeds:     &[]*discordgo.MessageEmbed{sm.printer(i).Embed(embed)},
+		Components: &components,
+	}); err != nil {
+		return errs.NewUpstream("failed to edit response", err)
+	}
+	return nil
+}
+
+// RunPublisher posts scheduled events as they come due until the context is canceled
+func (sm *StateManager) RunPublisher(ctx context.Context, s *discordgo.Session) {
+	ticker := time.NewTicker(publishInterval)
+	defer ticker.Stop()
+	for {
+		sm.publishDueDrafts(s, time.Now())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// publishDueDrafts posts every scheduled event which is due. Posts of organizers with a DM sequence in progress wait
+// for the next run, since they may be editing the post. Posts which fail are kept as drafts so they are not retried
+// over and over.
+func (sm *StateManager) publishDueDrafts(s *discordgo.Session, now time.Time) {
+	logger := slog.With("job", "publish")
+	for _, d := range sm.Store.DueDrafts(now) {
+		if sm.HasUser(d.OwnerID) {
+			continue
+		}
+		p := sm.userPrinter(d.OwnerID, d.Locale)
+		event, err := sm.publishDraft(s, d, now)
+		if errors.Is(err, errDraftClaimed) {
+			logger.Info("Skipped scheduled post which was already published or deleted", "draft", d.ID)
+			continue
+		}
+		if err != nil {
+			logger.Error("cannot publish scheduled post", "draft", d.ID, logging.ErrorKey, err)
+			d.PublishAt = time.Time{}
+			if err = sm.Store.SaveDraft(d); err != nil {
+				logger.Error("cannot unschedule draft", "draft", d.ID, logging.ErrorKey, err)
+			}
+			if err = discord.NotifyPostFailed(s, p, d.OwnerID, d); err != nil {
+				logger.Warn("cannot tell organizer about failed post", "draft", d.ID, logging.ErrorKey, err)
+			}
+			continue
+		}
+		metrics.PostsPublished.Inc()
+		logger.Info("Published scheduled post", logging.EventKey, event.DiscordLink)
+
+		if err = discord.NotifyPostLive(s, p, d.OwnerID, event); err != nil {
+			logger.Warn("cannot tell organizer their post is live", logging.EventKey, event.DiscordLink, logging.ErrorKey, err)
+		}
+	}
+}
+
+// publishDraft removes a scheduled post from the store and posts it, creating its calendar entry and guild scheduled
+// event. The draft is claimed first so a post which went live is never published again, such as when the organizer
+// publishes it by hand at the same time. The caller keeps the draft when publishing fails, unless it was claimed
+// elsewhere.
+func (sm *StateManager) publishDraft(s *discordgo.Session, d store.Draft, now time.Time) (*discord.Event, error) {
+	if !d.Start.After(now) {
+		return nil, fmt.Errorf("event started before it was posted")
+	}
+	channelID := d.PostChannelID
+	if channelID == "" {
+		channelID = sm.Config.Discord.EventsChannelID
+	}
+	if channelID == "" {
+		return nil, fmt.Errorf("no channel to post to")
+	}
+
+	opts := discord.Options{
+		Session:        s,
+		EventThreads:   sm.Config.Discord.EventThreads,
+		TextButtons:    sm.Config.Discord.TextButtons,
+		CalendarClient: sm.CalendarClient,
+	}
+	d, claimed, err := sm.Store.ClaimDraft(d.ID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot remove draft before publishing: %w", err)
+	}
+	if !claimed {
+		return nil, errDraftClaimed
+	}
+	event := discord.FromDraft(d, sm.Categories)
+	if err := opts.Publish(event, channelID, d.OwnerID); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
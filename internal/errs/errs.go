@@ -0,0 +1,114 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+	"google.golang.org/api/googleapi"
+)
+
+// Kind classifies an error by who can do something about it
+type Kind int
+
+const (
+	// Internal errors are bugs or unexpected states in the bot
+	Internal Kind = iota
+	// Permission errors are returned when a user is not allowed to perform an action
+	Permission
+	// Validation errors are returned when a user supplies input the bot cannot use
+	Validation
+	// Upstream errors come from Discord or Google Calendar
+	Upstream
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Permission:
+		return "permission"
+	case Validation:
+		return "validation"
+	case Upstream:
+		return "upstream"
+	default:
+		return "internal"
+	}
+}
+
+// Error is an error with a kind and a message that is safe to show to users. The message is a format string for the
+// args, so it can be translated before it is formatted.
+type Error struct {
+	Kind    Kind
+	Message string
+	Args    []any
+	Err     error
+}
+
+func (e *Error) Error() string {
+	msg := e.Message
+	if len(e.Args) > 0 {
+		msg = fmt.Sprintf(e.Message, e.Args...)
+	}
+	if e.Err == nil {
+		return msg
+	}
+	return fmt.Sprintf("%s: %v", msg, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// NewPermission returns a permission error with a message for the user
+func NewPermission(message string) error {
+	return &Error{Kind: Permission, Message: message}
+}
+
+// NewValidation returns a validation error with a message for the user
+func NewValidation(message string) error {
+	return &Error{Kind: Validation, Message: message}
+}
+
+// NewValidationf returns a validation error with a message for the user formatted from the args
+func NewValidationf(format string, a ...any) error {
+	return &Error{Kind: Validation, Message: format, Args: a}
+}
+
+// NewUpstream wraps an error from Discord or Google Calendar
+func NewUpstream(message string, err error) error {
+	return &Error{Kind: Upstream, Message: message, Err: err}
+}
+
+// KindOf returns the kind of an error. API errors from Discord and Google are treated as upstream errors.
+func KindOf(err error) Kind {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+	var restErr *discordgo.RESTError
+	var googleErr *googleapi.Error
+	if errors.As(err, &restErr) || errors.As(err, &googleErr) {
+		return Upstream
+	}
+	return Internal
+}
+
+// UserMessage returns the text shown to a user for an error
+func UserMessage(err error) string {
+	return LocalizedMessage(err, fmt.Sprintf)
+}
+
+// LocalizedMessage returns the text shown to a user for an error, translated and formatted with tf
+func LocalizedMessage(err error, tf func(format string, a ...any) string) string {
+	var e *Error
+	if errors.As(err, &e) && e.Message != "" && e.Kind != Upstream && e.Kind != Internal {
+		return tf(e.Message, e.Args...)
+	}
+	switch KindOf(err) {
+	case Permission:
+		return tf("You don't have permission to do that.")
+	case Upstream:
+		return tf("Discord or Google Calendar could not complete the request. Try again later.")
+	default:
+		return tf("Something went wrong.")
+	}
+}
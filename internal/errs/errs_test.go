@@ -0,0 +1,103 @@
+package errs
+
+import (
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/googleapi"
+	"testing"
+)
+
+func TestKindOf(t *testing.T) {
+	cases := []struct {
+		name     string
+		err      error
+		expected Kind
+	}{
+		{
+			name:     "permission",
+			err:      NewPermission("no"),
+			expected: Permission,
+		},
+		{
+			name:     "wrapped validation",
+			err:      fmt.Errorf("parse: %w", NewValidation("bad input")),
+			expected: Validation,
+		},
+		{
+			name:     "discord",
+			err:      fmt.Errorf("edit: %w", &discordgo.RESTError{}),
+			expected: Upstream,
+		},
+		{
+			name:     "google",
+			err:      &googleapi.Error{Code: 500},
+			expected: Upstream,
+		},
+		{
+			name:     "plain",
+			err:      fmt.Errorf("nil session"),
+			expected: Internal,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, KindOf(tc.err))
+		})
+	}
+}
+
+func TestUserMessage(t *testing.T) {
+	cases := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{
+			name:     "permission",
+			err:      NewPermission("You don't have permission to delete that event"),
+			expected: "You don't have permission to delete that event",
+		},
+		{
+			name:     "validation",
+			err:      fmt.Errorf("wrapped: %w", NewValidation("Use this command in a server")),
+			expected: "Use this command in a server",
+		},
+		{
+			name:     "formatted",
+			err:      NewValidationf("%s is full", "Accepted"),
+			expected: "Accepted is full",
+		},
+		{
+			name:     "upstream hides details",
+			err:      NewUpstream("cannot list events", &googleapi.Error{Code: 500}),
+			expected: "Discord or Google Calendar could not complete the request. Try again later.",
+		},
+		{
+			name:     "internal",
+			err:      fmt.Errorf("nil session"),
+			expected: "Something went wrong.",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, UserMessage(tc.err))
+		})
+	}
+}
+
+func TestLocalizedMessage(t *testing.T) {
+	catalog := map[string]string{
+		"Accepted is full":              "Aceptados está completo",
+		"You can bring up to %d guests": "Puedes traer hasta %d invitados",
+		"Something went wrong.":         "Algo salió mal.",
+	}
+	tf := func(format string, a ...any) string {
+		return fmt.Sprintf(catalog[format], a...)
+	}
+	assert.Equal(t, "Aceptados está completo", LocalizedMessage(NewValidation("Accepted is full"), tf))
+	assert.Equal(t, "Puedes traer hasta 2 invitados", LocalizedMessage(NewValidationf("You can bring up to %d guests", 2), tf))
+	assert.Equal(t, "Algo salió mal.", LocalizedMessage(fmt.Errorf("nil session"), tf))
+}
@@ -0,0 +1,191 @@
+// Package i18n translates bot-facing text. Catalogs are keyed by the English text so English needs no catalog and
+// untranslated text falls back to English.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+	"path"
+	"strings"
+)
+
+// DefaultLocale is the language of the text in the source code
+const DefaultLocale = discordgo.EnglishUS
+
+//go:embed locales/*.json
+var files embed.FS
+
+// Catalog holds the translations for one locale
+type Catalog struct {
+	// Messages translate English text and format strings
+	Messages map[string]string `json:"messages"`
+	// Names translate command and option names, which Discord requires to be lowercase without spaces
+	Names map[string]string `json:"names"`
+	// Keywords are words typed in place of English keywords such as "none" and "cancel"
+	Keywords map[string][]string `json:"keywords"`
+}
+
+var catalogs = mustLoad()
+
+func mustLoad() map[discordgo.Locale]*Catalog {
+	result := map[discordgo.Locale]*Catalog{}
+	entries, err := files.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+	for _, entry := range entries {
+		data, err := files.ReadFile(path.Join("locales", entry.Name()))
+		if err != nil {
+			panic(err)
+		}
+		c := &Catalog{}
+		if err = json.Unmarshal(data, c); err != nil {
+			panic(fmt.Sprintf("invalid catalog %s: %v", entry.Name(), err))
+		}
+		result[discordgo.Locale(strings.TrimSuffix(entry.Name(), ".json"))] = c
+	}
+	return result
+}
+
+// Localizer translates text into the first locale with a catalog. A nil Localizer leaves text in English.
+type Localizer struct {
+	Locale  discordgo.Locale
+	catalog *Catalog
+}
+
+// NewLocalizer uses the first locale which has a catalog. Regional locales fall back to their language, such as
+// es-419 to es-ES.
+func NewLocalizer(locales ...discordgo.Locale) *Localizer {
+	for _, locale := range locales {
+		if locale == "" {
+			continue
+		}
+		if c, ok := catalogs[locale]; ok {
+			return &Localizer{Locale: locale, catalog: c}
+		}
+		if locale == DefaultLocale || language(locale) == language(DefaultLocale) {
+			break
+		}
+		for l, c := range catalogs {
+			if language(l) == language(locale) {
+				return &Localizer{Locale: l, catalog: c}
+			}
+		}
+	}
+	return &Localizer{Locale: DefaultLocale}
+}
+
+// FromInteraction localizes text for the user of an interaction. The guild locale is used when the user's language has
+// no catalog, followed by the server's locale in Discord.
+func FromInteraction(i *discordgo.InteractionCreate, guildLocale string) *Localizer {
+	locales := []discordgo.Locale{i.Locale, discordgo.Locale(guildLocale)}
+	if i.GuildLocale != nil {
+		locales = append(locales, *i.GuildLocale)
+	}
+	return NewLocalizer(locales...)
+}
+
+func language(locale discordgo.Locale) string {
+	lang, _, _ := strings.Cut(string(locale), "-")
+	return lang
+}
+
+// T translates text. Multi-line text without a translation of its own is translated line by line.
+func (l *Localizer) T(text string) string {
+	if l == nil || l.catalog == nil || text == "" {
+		return text
+	}
+	if translated, ok := l.catalog.Messages[text]; ok {
+		return translated
+	}
+	if !strings.Contains(text, "\n") {
+		return text
+	}
+	lines := strings.Split(text, "\n")
+	for n, line := range lines {
+		if translated, ok := l.catalog.Messages[line]; ok {
+			lines[n] = translated
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Tf translates a format string before formatting it
+func (l *Localizer) Tf(format string, a ...any) string {
+	return fmt.Sprintf(l.T(format), a...)
+}
+
+// Embed returns a copy of an embed with its title, description, footer and field names translated. Field values
+// usually hold event details so they are left as is.
+func (l *Localizer) Embed(e *discordgo.MessageEmbed) *discordgo.MessageEmbed {
+	c := *e
+	c.Title = l.T(e.Title)
+	c.Description = l.T(e.Description)
+	if e.Footer != nil {
+		footer := *e.Footer
+		footer.Text = l.T(e.Footer.Text)
+		c.Footer = &footer
+	}
+	if len(e.Fields) > 0 {
+		c.Fields = make([]*discordgo.MessageEmbedField, 0, len(e.Fields))
+		for _, f := range e.Fields {
+			field := *f
+			field.Name = l.T(f.Name)
+			c.Fields = append(c.Fields, &field)
+		}
+	}
+	return &c
+}
+
+// Keyword converts a localized keyword into its English form so input parsers only deal with English. Other input is
+// returned as is.
+func (l *Localizer) Keyword(input string) string {
+	if l == nil || l.catalog == nil {
+		return input
+	}
+	trimmed := strings.TrimSpace(input)
+	for keyword, words := range l.catalog.Keywords {
+		for _, w := range words {
+			if strings.EqualFold(trimmed, w) {
+				return keyword
+			}
+		}
+	}
+	return input
+}
+
+// Localizations translates text into every locale with a translation, as used by command descriptions
+func Localizations(text string) map[discordgo.Locale]string {
+	result := map[discordgo.Locale]string{}
+	for locale, c := range catalogs {
+		if translated, ok := c.Messages[text]; ok {
+			result[locale] = translated
+		}
+	}
+	return result
+}
+
+// NameLocalizations translates a command or option name into every locale with a translation
+func NameLocalizations(name string) map[discordgo.Locale]string {
+	result := map[discordgo.Locale]string{}
+	for locale, c := range catalogs {
+		if translated, ok := c.Names[name]; ok {
+			result[locale] = translated
+		}
+	}
+	return result
+}
+
+// LocalizeCommands adds the name and description translations of commands and their options
+func LocalizeCommands(commands []*discordgo.ApplicationCommand) {
+	for _, cmd := range commands {
+		names, descriptions := NameLocalizations(cmd.Name), Localizations(cmd.Description)
+		cmd.NameLocalizations, cmd.DescriptionLocalizations = &names, &descriptions
+		for _, opt := range cmd.Options {
+			opt.NameLocalizations = NameLocalizations(opt.Name)
+			opt.DescriptionLocalizations = Localizations(opt.Description)
+		}
+	}
+}
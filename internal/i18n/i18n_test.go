@@ -0,0 +1,175 @@
+package i18n
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewLocalizer(t *testing.T) {
+	cases := []struct {
+		name     string
+		locales  []discordgo.Locale
+		expected discordgo.Locale
+	}{
+		{
+			name:     "exact match",
+			locales:  []discordgo.Locale{discordgo.SpanishES},
+			expected: discordgo.SpanishES,
+		},
+		{
+			name:     "same language",
+			locales:  []discordgo.Locale{discordgo.Locale("es-419")},
+			expected: discordgo.SpanishES,
+		},
+		{
+			name:     "english stops the search",
+			locales:  []discordgo.Locale{discordgo.EnglishGB, discordgo.SpanishES},
+			expected: DefaultLocale,
+		},
+		{
+			name:     "falls back to guild locale",
+			locales:  []discordgo.Locale{discordgo.French, "", discordgo.SpanishES},
+			expected: discordgo.SpanishES,
+		},
+		{
+			name:     "no catalog",
+			locales:  []discordgo.Locale{discordgo.French},
+			expected: DefaultLocale,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, NewLocalizer(tc.locales...).Locale)
+		})
+	}
+}
+
+func TestLocalizer_T(t *testing.T) {
+	es := NewLocalizer(discordgo.SpanishES)
+	cases := []struct {
+		name      string
+		localizer *Localizer
+		text      string
+		expected  string
+	}{
+		{
+			name:      "translated",
+			localizer: es,
+			text:      "Who showed up?",
+			expected:  "¿Quién vino?",
+		},
+		{
+			name:      "line by line",
+			localizer: es,
+			text:      "Enter a number to select an option\nTo go back a step, type 'back'. To exit, type 'cancel'",
+			expected:  "Escribe un número para elegir una opción\nPara volver al paso anterior, escribe 'atrás'. Para salir, escribe 'cancelar'",
+		},
+		{
+			name:      "untranslated",
+			localizer: es,
+			text:      "Event ended",
+			expected:  "Event ended",
+		},
+		{
+			name:      "english",
+			localizer: NewLocalizer(discordgo.EnglishUS),
+			text:      "Who showed up?",
+			expected:  "Who showed up?",
+		},
+		{
+			name:     "nil",
+			text:     "Who showed up?",
+			expected: "Who showed up?",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.localizer.T(tc.text))
+		})
+	}
+}
+
+func TestLocalizer_Tf(t *testing.T) {
+	assert.Equal(t, "Se marcaron 2 de 3 asistentes", NewLocalizer(discordgo.SpanishES).Tf("Checked in %d of %d attendees", 2, 3))
+}
+
+func TestLocalizer_Keyword(t *testing.T) {
+	es := NewLocalizer(discordgo.SpanishES)
+	cases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "none",
+			input:    "Ninguno",
+			expected: "none",
+		},
+		{
+			name:     "cancel",
+			input:    " cancelar ",
+			expected: "cancel",
+		},
+		{
+			name:     "back",
+			input:    "Atrás",
+			expected: "back",
+		},
+		{
+			name:     "english still works",
+			input:    "cancel",
+			expected: "cancel",
+		},
+		{
+			name:     "other input",
+			input:    "mañana a las 8pm",
+			expected: "mañana a las 8pm",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, es.Keyword(tc.input))
+		})
+	}
+}
+
+func TestLocalizer_Embed(t *testing.T) {
+	original := &discordgo.MessageEmbed{
+		Title:  "What would you like to modify?",
+		Footer: &discordgo.MessageEmbedFooter{Text: "To go back a step, type 'back'. To exit, type 'cancel'"},
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "1 ⋅ Title", Value: "Who showed up?"},
+		},
+	}
+	translated := NewLocalizer(discordgo.SpanishES).Embed(original)
+
+	assert.Equal(t, "¿Qué quieres modificar?", translated.Title)
+	assert.Equal(t, "Para volver al paso anterior, escribe 'atrás'. Para salir, escribe 'cancelar'", translated.Footer.Text)
+	assert.Equal(t, "1 ⋅ Título", translated.Fields[0].Name)
+	assert.Equal(t, "Who showed up?", translated.Fields[0].Value)
+
+	assert.Equal(t, "What would you like to modify?", original.Title)
+	assert.Equal(t, "To go back a step, type 'back'. To exit, type 'cancel'", original.Footer.Text)
+	assert.Equal(t, "1 ⋅ Title", original.Fields[0].Name)
+}
+
+func TestLocalizeCommands(t *testing.T) {
+	commands := []*discordgo.ApplicationCommand{
+		{
+			Name:        "event",
+			Description: "Create a new event",
+			Options: []*discordgo.ApplicationCommandOption{
+				{Name: "channel", Description: "Channel to post the event in. Defaults to the events channel"},
+			},
+		},
+	}
+	LocalizeCommands(commands)
+
+	assert.Equal(t, "evento", (*commands[0].NameLocalizations)[discordgo.SpanishES])
+	assert.Equal(t, "Crear un evento nuevo", (*commands[0].DescriptionLocalizations)[discordgo.SpanishES])
+	assert.Equal(t, "canal", commands[0].Options[0].NameLocalizations[discordgo.SpanishES])
+}
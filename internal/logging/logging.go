@@ -0,0 +1,96 @@
+package logging
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Structured log field names
+const (
+	GuildKey = "guild"
+	UserKey  = "user"
+	EventKey = "event"
+	StateKey = "state"
+	ErrorKey = "err"
+
+	CorrelationKey = "correlationID"
+)
+
+// correlationIDs maps interaction IDs to the correlation ID of the handler processing them
+var correlationIDs sync.Map
+
+// Setup configures the default logger to write leveled JSON logs to stdout
+func Setup(level string) error {
+	var l slog.Level
+	if level != "" {
+		if err := l.UnmarshalText([]byte(strings.ToUpper(level))); err != nil {
+			return fmt.Errorf("invalid log level %q: %v", level, err)
+		}
+	}
+	slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: l})))
+	return nil
+}
+
+// FromInteraction returns a logger with the guild, user and event message of an interaction
+func FromInteraction(i *discordgo.InteractionCreate) *slog.Logger {
+	logger := slog.Default()
+	if i == nil || i.Interaction == nil {
+		return logger
+	}
+	if id, ok := correlationIDs.Load(i.ID); ok {
+		logger = logger.With(CorrelationKey, id)
+	}
+	logger = logger.With(GuildKey, i.GuildID)
+	if user := InteractionUser(i); user != nil {
+		logger = logger.With(UserKey, user.Username)
+	}
+	if i.Message != nil {
+		logger = logger.With(EventKey, EventPath(i.ChannelID, i.Message.ID))
+	}
+	return logger
+}
+
+// InteractionUser returns the user of an interaction sent in either a guild or a DM
+func InteractionUser(i *discordgo.InteractionCreate) *discordgo.User {
+	if i.Member != nil && i.Member.User != nil {
+		return i.Member.User
+	}
+	return i.User
+}
+
+// EventPath identifies an event by the channel and message of its post
+func EventPath(channelID, messageID string) string {
+	return fmt.Sprintf("%s/%s", channelID, messageID)
+}
+
+// Track assigns a correlation ID to an interaction so every log line written while handling it can be matched with the
+// reference shown to the user. The returned func stops tracking the interaction.
+func Track(i *discordgo.InteractionCreate) (string, func()) {
+	id := newCorrelationID()
+	correlationIDs.Store(i.ID, id)
+	return id, func() {
+		correlationIDs.Delete(i.ID)
+	}
+}
+
+// CorrelationID returns the correlation ID of an interaction being handled
+func CorrelationID(i *discordgo.InteractionCreate) string {
+	if id, ok := correlationIDs.Load(i.ID); ok {
+		return id.(string)
+	}
+	return ""
+}
+
+func newCorrelationID() string {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
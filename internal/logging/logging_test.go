@@ -0,0 +1,64 @@
+package logging
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSetup(t *testing.T) {
+	cases := []struct {
+		name  string
+		level string
+		isErr bool
+	}{
+		{
+			name:  "default",
+			level: "",
+		},
+		{
+			name:  "debug",
+			level: "debug",
+		},
+		{
+			name:  "invalid",
+			level: "loud",
+			isErr: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Setup(tc.level)
+			if tc.isErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestInteractionUser(t *testing.T) {
+	member := &discordgo.User{Username: "member"}
+	user := &discordgo.User{Username: "user"}
+
+	guild := &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{Member: &discordgo.Member{User: member}}}
+	assert.Equal(t, member, InteractionUser(guild))
+
+	dm := &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{User: user}}
+	assert.Equal(t, user, InteractionUser(dm))
+}
+
+func TestTrack(t *testing.T) {
+	i := &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{ID: "1"}}
+	id, done := Track(i)
+	assert.Len(t, id, 12)
+
+	tracked, ok := correlationIDs.Load(i.ID)
+	assert.True(t, ok)
+	assert.Equal(t, id, tracked)
+	assert.Equal(t, id, CorrelationID(i))
+
+	done()
+	assert.Empty(t, CorrelationID(i))
+}
@@ -0,0 +1,83 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"time"
+)
+
+const namespace = "gang_gang_bot"
+
+// FSM outcomes
+const (
+	Completed = "completed"
+	Drafted   = "drafted"
+	Scheduled = "scheduled"
+	Canceled  = "canceled"
+	TimedOut  = "timeout"
+)
+
+var (
+	// Interactions counts interactions by type and command or component name
+	Interactions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "interactions_total",
+		Help:      "Number of Discord interactions handled",
+	}, []string{"type", "name"})
+
+	// FSMOutcomes counts how DM sequences end by action
+	FSMOutcomes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "fsm_outcomes_total",
+		Help:      "Number of state machine sequences completed, saved as drafts, scheduled, canceled or timed out",
+	}, []string{"action", "outcome"})
+
+	// HandlerErrors counts interaction handlers that failed by error kind
+	HandlerErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "handler_errors_total",
+		Help:      "Number of interaction handlers that returned an error or panicked",
+	}, []string{"kind"})
+
+	// EventsArchived counts events archived after they ended
+	EventsArchived = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "events_archived_total",
+		Help:      "Number of ended events whose buttons were disabled",
+	})
+
+	// PostsPublished counts scheduled event posts which went live
+	PostsPublished = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "scheduled_posts_published_total",
+		Help:      "Number of scheduled event posts published",
+	})
+
+	// CalendarLatency observes Google Calendar API request durations
+	CalendarLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "calendar_request_duration_seconds",
+		Help:      "Latency of Google Calendar API requests",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"operation"})
+
+	// CalendarErrors counts failed Google Calendar API requests
+	CalendarErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "calendar_request_errors_total",
+		Help:      "Number of failed Google Calendar API requests",
+	}, []string{"operation"})
+)
+
+// ObserveCalendarRequest records the latency and result of a Google Calendar API request
+func ObserveCalendarRequest(operation string, start time.Time, err error) {
+	CalendarLatency.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+	if err != nil {
+		CalendarErrors.WithLabelValues(operation).Inc()
+	}
+}
+
+// RecordFSMOutcome records how a DM sequence for an action ended
+func RecordFSMOutcome(action, outcome string) {
+	FSMOutcomes.WithLabelValues(action, outcome).Inc()
+}
@@ -0,0 +1,23 @@
+package metrics
+
+import (
+	"fmt"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestObserveCalendarRequest(t *testing.T) {
+	ObserveCalendarRequest("test", time.Now(), nil)
+	assert.Equal(t, float64(0), testutil.ToFloat64(CalendarErrors.WithLabelValues("test")))
+
+	ObserveCalendarRequest("test", time.Now(), fmt.Errorf("failed"))
+	assert.Equal(t, float64(1), testutil.ToFloat64(CalendarErrors.WithLabelValues("test")))
+	assert.Equal(t, 1, testutil.CollectAndCount(CalendarLatency))
+}
+
+func TestRecordFSMOutcome(t *testing.T) {
+	RecordFSMOutcome("creation", Canceled)
+	assert.Equal(t, float64(1), testutil.ToFloat64(FSMOutcomes.WithLabelValues("creation", Canceled)))
+}
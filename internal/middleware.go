@@ -0,0 +1,270 @@
+package internal
+
+import (
+	"fmt"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/discord"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/errs"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/logging"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/metrics"
+	"github.com/bwmarrin/discordgo"
+	"log/slog"
+	"runtime/debug"
+	"time"
+)
+
+// HandlerFunc handles a command or component interaction. Returned errors are reported back to the user.
+type HandlerFunc func(s *discordgo.Session, i *discordgo.InteractionCreate) error
+
+// Middleware wraps a handler with behavior shared between handlers
+type Middleware func(next HandlerFunc) HandlerFunc
+
+// AckType is how an interaction is acknowledged before its handler runs
+type AckType int
+
+const (
+	// AckNone leaves responding to the handler
+	AckNone AckType = iota
+	// AckUpdate acknowledges a component interaction without changing its message
+	AckUpdate
+	// AckDeferEphemeral shows a loading state for slow handlers which later edit the response
+	AckDeferEphemeral
+)
+
+// Handler declares an interaction handler along with what it needs before it runs
+type Handler struct {
+	Handle HandlerFunc
+	// Ack acknowledges the interaction once every check has passed
+	Ack AckType
+	// Calendar handlers need a Google Calendar client
+	Calendar bool
+	// GuildOnly handlers cannot be used in DMs
+	GuildOnly bool
+	// Permissions are required to use the handler. Event organizers are exempt when OwnerAllowed is set.
+	Permissions  int64
+	OwnerAllowed bool
+	// Exclusive handlers start a DM sequence and cannot run while the user has another in progress
+	Exclusive bool
+}
+
+// Chain wraps a handler with middleware. The first middleware is the outermost.
+func Chain(h HandlerFunc, m ...Middleware) HandlerFunc {
+	for i := len(m) - 1; i >= 0; i-- {
+		h = m[i](h)
+	}
+	return h
+}
+
+// Build creates the middleware chain for a handler from its requirements
+func (sm *StateManager) Build(h Handler) func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	m := []Middleware{Trace, sm.ReportErrors, Recover, LogDuration, sm.RateLimit, sm.RememberLocale}
+	if h.Calendar {
+		m = append(m, sm.RequireCalendar)
+	}
+	if h.GuildOnly || h.Permissions != 0 {
+		m = append(m, RequireGuild)
+	}
+	if h.Permissions != 0 {
+		m = append(m, RequirePermissions(h.Permissions, h.OwnerAllowed))
+	}
+	if h.Exclusive {
+		m = append(m, sm.Exclusive)
+	}
+	if h.Ack != AckNone {
+		m = append(m, Acknowledge(h.Ack))
+	}
+	handle := Chain(h.Handle, m...)
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		_ = handle(s, i)
+	}
+}
+
+// Trace assigns a correlation ID which is attached to logs and shown to users when a handler fails
+func Trace(next HandlerFunc) HandlerFunc {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+		_, done := logging.Track(i)
+		defer done()
+		return next(s, i)
+	}
+}
+
+// ReportErrors logs handler errors by kind and replies to the user with an ephemeral message in their language
+func (sm *StateManager) ReportErrors(next HandlerFunc) HandlerFunc {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+		err := next(s, i)
+		if err == nil {
+			return nil
+		}
+		logger := logging.FromInteraction(i)
+		kind := errs.KindOf(err)
+		metrics.HandlerErrors.WithLabelValues(kind.String()).Inc()
+		switch kind {
+		case errs.Permission, errs.Validation:
+			logger.Warn("handler rejected interaction", "kind", kind.String(), logging.ErrorKey, err)
+		default:
+			logger.Error("handler failed", "kind", kind.String(), logging.ErrorKey, err)
+		}
+		replyError(s, i, sm.printer(i), logger, err)
+		return err
+	}
+}
+
+// Recover turns a panic in a handler into an internal error
+func Recover(next HandlerFunc) HandlerFunc {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				logging.FromInteraction(i).Error("handler panicked", "panic", r, "stack", string(debug.Stack()))
+				err = fmt.Errorf("panic: %v", r)
+			}
+		}()
+		return next(s, i)
+	}
+}
+
+// LogDuration logs how long a handler took, which includes the full DM sequence for exclusive handlers
+func LogDuration(next HandlerFunc) HandlerFunc {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+		start := time.Now()
+		err := next(s, i)
+		logging.FromInteraction(i).Debug("handled interaction", "duration", time.Since(start).String())
+		return err
+	}
+}
+
+// RateLimit rejects users sending too many interactions in a short period
+func (sm *StateManager) RateLimit(next HandlerFunc) HandlerFunc {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+		if user := logging.InteractionUser(i); user != nil && !sm.limiter.Allow(user.ID) {
+			return errs.NewValidation("You're doing that too often. Try again in a few seconds.")
+		}
+		return next(s, i)
+	}
+}
+
+// RememberLocale saves the language of the user's Discord client when it changes, so messages sent to them outside
+// their own interactions are in that language
+func (sm *StateManager) RememberLocale(next HandlerFunc) HandlerFunc {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+		if user := logging.InteractionUser(i); user != nil && sm.Store != nil && i.Locale != "" {
+			if prefs := sm.Store.Preferences(user.ID); prefs.Locale != string(i.Locale) {
+				prefs.Locale = string(i.Locale)
+				if err := sm.Store.SetPreferences(user.ID, prefs); err != nil {
+					logging.FromInteraction(i).Warn("cannot save locale", logging.ErrorKey, err)
+				}
+			}
+		}
+		return next(s, i)
+	}
+}
+
+// RequireCalendar fails handlers which need Google Calendar when it is not configured
+func (sm *StateManager) RequireCalendar(next HandlerFunc) HandlerFunc {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+		if sm.CalendarClient == nil {
+			return fmt.Errorf("calendar client is nil")
+		}
+		return next(s, i)
+	}
+}
+
+// RequireGuild rejects interactions sent from DMs
+func RequireGuild(next HandlerFunc) HandlerFunc {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+		if i.Member == nil || i.Member.User == nil {
+			return errs.NewValidation("This can only be used in a server")
+		}
+		return next(s, i)
+	}
+}
+
+// RequirePermissions rejects members without the given permissions. When ownerAllowed is set, the organizer of the
+// event the interaction was sent from is also allowed.
+func RequirePermissions(permissions int64, ownerAllowed bool) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+			if i.Member.Permissions&permissions == permissions {
+				return next(s, i)
+			}
+			if ownerAllowed && i.Message != nil {
+				e, err := discord.GetEventFromMessage(i.Message)
+				if err != nil {
+					return fmt.Errorf("failed to get event: %w", err)
+				}
+				if e.Owner == i.Member.User.Username {
+					return next(s, i)
+				}
+			}
+			return errs.NewPermission("You must either be the event organizer or have the `Manage Events` permission to do that")
+		}
+	}
+}
+
+// Exclusive prevents a user from starting a DM sequence while another is in progress
+func (sm *StateManager) Exclusive(next HandlerFunc) HandlerFunc {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+		user := logging.InteractionUser(i)
+		if user == nil {
+			return fmt.Errorf("cannot find user")
+		}
+		if !sm.TryAddUser(user.ID) {
+			discord.NotifyCommandInProgress(s, i, sm.printer(i))
+			return nil
+		}
+		defer sm.RemoveUser(user.ID)
+		return next(s, i)
+	}
+}
+
+// Acknowledge responds to an interaction before the handler runs so slow handlers do not time out
+func Acknowledge(ack AckType) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+			resp := &discordgo.InteractionResponse{Type: discordgo.InteractionResponseUpdateMessage}
+			if ack == AckDeferEphemeral {
+				resp = &discordgo.InteractionResponse{
+					Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+					Data: &discordgo.InteractionResponseData{
+						Flags: discordgo.MessageFlagsEphemeral,
+					},
+				}
+			}
+			if err := s.InteractionRespond(i.Interaction, resp); err != nil {
+				// Handlers can often still finish their work, so the failure is only logged
+				logging.FromInteraction(i).Error("failed to respond", logging.ErrorKey, err)
+			}
+			return next(s, i)
+		}
+	}
+}
+
+// unknownHandler fails interactions the bot has no handler for, such as buttons from removed features
+func unknownHandler(kind, name string) HandlerFunc {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+		return fmt.Errorf("no handler for %s %q", kind, name)
+	}
+}
+
+// replyError tells the user why an interaction failed. Handlers which already acknowledged the interaction get a
+// followup message instead.
+func replyError(s *discordgo.Session, i *discordgo.InteractionCreate, p *discord.Printer, logger *slog.Logger, err error) {
+	embed := &discordgo.MessageEmbed{
+		Title:  errs.LocalizedMessage(err, p.Tf),
+		Color:  discord.Purple,
+		Footer: &discordgo.MessageEmbedFooter{Text: p.Tf("Reference: %s", logging.CorrelationID(i))},
+	}
+	if respondErr := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+			Flags:  discordgo.MessageFlagsEphemeral,
+		},
+	}); respondErr == nil {
+		return
+	}
+	if _, followupErr := s.FollowupMessageCreate(i.Interaction, false, &discordgo.WebhookParams{
+		Embeds: []*discordgo.MessageEmbed{embed},
+		Flags:  discordgo.MessageFlagsEphemeral,
+	}); followupErr != nil {
+		logger.Error("failed to send error reply", logging.ErrorKey, followupErr)
+	}
+}
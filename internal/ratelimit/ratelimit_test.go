@@ -0,0 +1,25 @@
+package ratelimit
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestLimiter_Allow(t *testing.T) {
+	start := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	now := start
+	l := NewLimiter(2, 10*time.Second)
+	l.now = func() time.Time { return now }
+
+	assert.True(t, l.Allow("foo"))
+	assert.True(t, l.Allow("foo"))
+	assert.False(t, l.Allow("foo"))
+	assert.True(t, l.Allow("bar"), "limits are per key")
+
+	now = start.Add(5 * time.Second)
+	assert.False(t, l.Allow("foo"))
+
+	now = start.Add(11 * time.Second)
+	assert.True(t, l.Allow("foo"), "hits outside the window are dropped")
+}
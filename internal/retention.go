@@ -0,0 +1,36 @@
+package internal
+
+import (
+	"context"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/logging"
+	"log/slog"
+	"time"
+)
+
+const pruneInterval = 24 * time.Hour
+
+// RunPruner removes old records from the store once a day until the context is canceled
+func (sm *StateManager) RunPruner(ctx context.Context) {
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+	for {
+		sm.pruneStore(time.Now())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// pruneStore removes late cancellations and announcements older than the retention period. Late cancellations are
+// still needed for as long as they count towards the late cancellation limit.
+func (sm *StateManager) pruneStore(now time.Time) {
+	days := sm.Config.Store.RetentionDays
+	if days < sm.Config.Rules.LateCancelDays {
+		days = sm.Config.Rules.LateCancelDays
+	}
+	if err := sm.Store.Prune(now.AddDate(0, 0, -days)); err != nil {
+		slog.Error("cannot prune store", "job", "prune", logging.ErrorKey, err)
+	}
+}
@@ -0,0 +1,217 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/discord"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/role"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/errs"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/logging"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/store"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/pkg/util"
+	"github.com/bwmarrin/discordgo"
+	"golang.org/x/exp/slices"
+	"log/slog"
+	"time"
+)
+
+const releaseInterval = 5 * time.Minute
+
+// signupRules builds the configured signup rules for the user of an interaction joining an event, or nil when no rules
+// are configured. The events the user accepted this week are counted beforehand, so the calendar is not listed while
+// the event is locked.
+func (sm *StateManager) signupRules(s *discordgo.Session, i *discordgo.InteractionCreate, acceptedThisWeek int) *role.Rules {
+	cfg := sm.Config.Rules
+	var rules []role.Rule
+	if cfg.LateCancelLimit > 0 && sm.Store != nil {
+		rules = append(rules, role.LateCancelRule{Limit: cfg.LateCancelLimit})
+	}
+	if cfg.WeeklyLimit > 0 && sm.CalendarClient != nil {
+		rules = append(rules, role.WeeklyLimitRule{Limit: cfg.WeeklyLimit})
+	}
+	if len(rules) == 0 && len(cfg.PriorityRoleIDs) == 0 {
+		return nil
+	}
+
+	priority := map[string]bool{}
+	return &role.Rules{
+		Rules: rules,
+		// Only the user of the interaction joins through signup buttons
+		Member: func(name string) (role.Member, error) {
+			m := role.Member{
+				Priority:         hasAnyRole(i.Member.Roles, cfg.PriorityRoleIDs),
+				AcceptedThisWeek: acceptedThisWeek,
+			}
+			if cfg.LateCancelLimit > 0 && sm.Store != nil {
+				since := time.Now().AddDate(0, 0, -cfg.LateCancelDays)
+				m.LateCancellations = len(sm.Store.LateCancellations(name, since))
+			}
+			return m, nil
+		},
+		IsPriority: func(name string) bool {
+			if p, ok := priority[name]; ok {
+				return p
+			}
+			priority[name] = sm.memberHasRole(s, i.GuildID, name, cfg.PriorityRoleIDs)
+			return priority[name]
+		},
+	}
+}
+
+// acceptedThisWeek counts the other events the user of a signup click accepted in the week of the event, or 0 when the
+// weekly limit does not apply to the click. Listing the calendar is slow, so it is done once before the event is locked
+// rather than on every attempt to apply the click.
+func (sm *StateManager) acceptedThisWeek(s *discordgo.Session, i *discordgo.InteractionCreate, field role.FieldType) (int, error) {
+	if field != role.AcceptedField || sm.Config.Rules.WeeklyLimit == 0 || sm.CalendarClient == nil {
+		return 0, nil
+	}
+	event, err := discord.GetEventFromMessage(i.Message)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get event: %w", err)
+	}
+	name := i.Member.User.Username
+	// Clicking accept again drops out, which no rule limits
+	if event.RoleGroup.GetResponse(name).Field == role.AcceptedField {
+		return 0, nil
+	}
+	return sm.acceptedInWeek(s, logging.FromInteraction(i), name, event.Start, i.Message.ID)
+}
+
+// acceptedInWeek counts the other events a user accepted in the same week as an event
+func (sm *StateManager) acceptedInWeek(s *discordgo.Session, logger *slog.Logger, name string, start time.Time, messageID string) (int, error) {
+	weekStart := util.StartOfWeek(start.In(time.Local))
+	weekEnd := weekStart.AddDate(0, 0, 7)
+	events, err := sm.CalendarClient.ListEventsBetween(weekStart, weekEnd)
+	if err != nil {
+		return 0, errs.NewUpstream("cannot list events from Google calendar", err)
+	}
+	var count int
+	for _, e := range linkedEvents(s, logger, events) {
+		if _, _, id, err := util.GetIDsFromDiscordLink(e.DiscordLink); err == nil && id == messageID {
+			continue
+		}
+		if !e.Start.Before(weekStart) && e.Start.Before(weekEnd) && e.RoleGroup.HasUser(name, role.AcceptedField) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// memberHasRole looks up a guild member by username and reports whether they have any of the roles
+func (sm *StateManager) memberHasRole(s *discordgo.Session, guildID, name string, roleIDs []string) bool {
+	m, err := discord.FindMember(s, guildID, name)
+	if err != nil {
+		slog.Warn("cannot look up member roles", logging.UserKey, name, logging.ErrorKey, err)
+		return false
+	}
+	return m != nil && hasAnyRole(m.Roles, roleIDs)
+}
+
+func hasAnyRole(roles, roleIDs []string) bool {
+	for _, id := range roleIDs {
+		if slices.Contains(roles, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// isLateCancellation reports whether a user left the accepted list shortly before an event started
+func isLateCancellation(before, after role.Response, start, now time.Time, window time.Duration) bool {
+	if before.Field != role.AcceptedField || before.Waitlist != 0 || after.Field == role.AcceptedField {
+		return false
+	}
+	return now.Before(start) && start.Sub(now) <= window
+}
+
+// recordLateCancellation stores a user leaving an event shortly before it started so rules can account for it
+func (sm *StateManager) recordLateCancellation(i *discordgo.InteractionCreate, event *discord.Event, before, after role.Response) {
+	now := time.Now()
+	window := time.Duration(sm.Config.Rules.LateCancelHours) * time.Hour
+	if sm.Store == nil || !isLateCancellation(before, after, event.Start, now, window) {
+		return
+	}
+	name := i.Member.User.Username
+	if err := sm.Store.RecordLateCancellation(store.Cancellation{
+		Name:      name,
+		MessageID: i.Message.ID,
+		Start:     event.Start,
+		At:        now,
+	}); err != nil {
+		logging.FromInteraction(i).Error("cannot record late cancellation", logging.ErrorKey, err)
+	}
+}
+
+// alertLateCancellation tells the organizer when a member drops out of an event starting soon
+func (sm *StateManager) alertLateCancellation(s *discordgo.Session, i *discordgo.InteractionCreate, event *discord.Event, before, after role.Response, promoted []string) {
+	window := time.Duration(sm.Config.Alerts.LateCancelHours) * time.Hour
+	if !isLateCancellation(before, after, event.Start, time.Now(), window) {
+		return
+	}
+	if err := discord.NotifyLateCancellation(s, sm.Store, sm.memberPrinter, i.GuildID, discord.LateCancellation{
+		Event:     event,
+		ChannelID: i.ChannelID,
+		MessageID: i.Message.ID,
+		Dropped:   []string{i.Member.User.Username},
+		Promoted:  promoted,
+	}); err != nil {
+		logging.FromInteraction(i).Error("cannot alert organizer", logging.ErrorKey, err)
+	}
+}
+
+// RunWaitlistRelease moves waitlisted members into open spots of events starting soon until the context is canceled.
+// Members waitlisted by signup rules are otherwise only promoted when someone drops out.
+func (sm *StateManager) RunWaitlistRelease(ctx context.Context, s *discordgo.Session) {
+	ticker := time.NewTicker(releaseInterval)
+	defer ticker.Stop()
+	for {
+		sm.releaseWaitlists(s, time.Now())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (sm *StateManager) releaseWaitlists(s *discordgo.Session, now time.Time) {
+	logger := slog.With("job", "waitlist_release")
+	window := time.Duration(sm.Config.Rules.WaitlistReleaseHours) * time.Hour
+	events, err := sm.CalendarClient.ListEventsBetween(now, now.Add(window))
+	if err != nil {
+		logger.Error("cannot list events from Google calendar", logging.ErrorKey, err)
+		return
+	}
+	for _, e := range linkedEvents(s, logger, events) {
+		if !e.Start.After(now) || e.RoleGroup.PeekWaitlist(role.AcceptedField) == "" {
+			continue
+		}
+		guildID, channelID, messageID, err := util.GetIDsFromDiscordLink(e.DiscordLink)
+		if err != nil {
+			continue
+		}
+		var promoted []string
+		event, err := sm.updateEvent(s, guildID, channelID, messageID, func(e *discord.Event) (err error) {
+			if promoted, err = e.PromoteFromWaitlists(); err != nil {
+				return err
+			}
+			if len(promoted) == 0 {
+				return errUnchanged
+			}
+			return nil
+		})
+		if err != nil {
+			if !errors.Is(err, errUnchanged) {
+				logger.Error("cannot release waitlist", logging.EventKey, logging.EventPath(channelID, messageID), logging.ErrorKey, err)
+			}
+			continue
+		}
+		for _, name := range promoted {
+			if err = event.NotifyUserOffWaitlist(s, sm.memberPrinter, guildID, name); err != nil {
+				logger.Error("cannot notify user", logging.UserKey, name, logging.ErrorKey, err)
+			}
+		}
+		logger.Info("Released waitlist", logging.EventKey, logging.EventPath(channelID, messageID), "promoted", len(promoted))
+	}
+}
@@ -0,0 +1,224 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/discord"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/role"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/logging"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/pkg/util"
+	"github.com/bwmarrin/discordgo"
+	"log/slog"
+	"time"
+)
+
+// InterestedAddHandler mirrors a user marking a guild scheduled event as "Interested" into the Tentative list
+func (sm *StateManager) InterestedAddHandler(s *discordgo.Session, u *discordgo.GuildScheduledEventUserAdd) {
+	logger := slog.With(logging.GuildKey, u.GuildID, "guildEvent", u.GuildScheduledEventID)
+	user, err := s.User(u.UserID)
+	if err != nil {
+		logger.Error("cannot find user", "userID", u.UserID, logging.ErrorKey, err)
+		return
+	}
+	guildEvent, err := s.GuildScheduledEvent(u.GuildID, u.GuildScheduledEventID, false)
+	if err != nil {
+		logger.Error("cannot get guild event", logging.ErrorKey, err)
+		return
+	}
+	_, msg, err := getLinkedEvent(s, guildEvent)
+	if err != nil {
+		logger.Error("cannot find event for guild event", logging.ErrorKey, err)
+		return
+	}
+	if _, err = sm.updateEvent(s, u.GuildID, msg.ChannelID, msg.ID, func(e *discord.Event) error {
+		// Explicit responses from the signup buttons take precedence over interest
+		if e.RoleGroup.HasResponse(user.Username) || e.SignupsClosed(time.Now()) {
+			return errUnchanged
+		}
+		return e.RoleGroup.ToggleRole(role.TentativeField, user.Username)
+	}); err != nil {
+		if !errors.Is(err, errUnchanged) {
+			logger.Error("toggle tentative", logging.ErrorKey, err)
+		}
+		return
+	}
+	logger.Info("User marked interested", logging.UserKey, user.Username, logging.EventKey, logging.EventPath(msg.ChannelID, msg.ID))
+}
+
+// InterestedRemoveHandler removes a user from the Tentative list when they are no longer interested in a guild scheduled event
+func (sm *StateManager) InterestedRemoveHandler(s *discordgo.Session, u *discordgo.GuildScheduledEventUserRemove) {
+	logger := slog.With(logging.GuildKey, u.GuildID, "guildEvent", u.GuildScheduledEventID)
+	user, err := s.User(u.UserID)
+	if err != nil {
+		logger.Error("cannot find user", "userID", u.UserID, logging.ErrorKey, err)
+		return
+	}
+	guildEvent, err := s.GuildScheduledEvent(u.GuildID, u.GuildScheduledEventID, false)
+	if err != nil {
+		logger.Error("cannot get guild event", logging.ErrorKey, err)
+		return
+	}
+	_, msg, err := getLinkedEvent(s, guildEvent)
+	if err != nil {
+		logger.Error("cannot find event for guild event", logging.ErrorKey, err)
+		return
+	}
+	if _, err = sm.updateEvent(s, u.GuildID, msg.ChannelID, msg.ID, func(e *discord.Event) error {
+		if !e.RoleGroup.HasUser(user.Username, role.TentativeField) {
+			return errUnchanged
+		}
+		return e.RoleGroup.ToggleRole(role.TentativeField, user.Username)
+	}); err != nil {
+		if !errors.Is(err, errUnchanged) {
+			logger.Error("toggle tentative", logging.ErrorKey, err)
+		}
+		return
+	}
+	logger.Info("User is no longer interested", logging.UserKey, user.Username, logging.EventKey, logging.EventPath(msg.ChannelID, msg.ID))
+}
+
+// ScheduledEventCreateHandler publishes a signup post for guild scheduled events created outside the bot
+func (sm *StateManager) ScheduledEventCreateHandler(s *discordgo.Session, c *discordgo.GuildScheduledEventCreate) {
+	logger := slog.With(logging.GuildKey, c.GuildID, "guildEvent", c.ID)
+	if c.CreatorID == s.State.User.ID || util.FindDiscordLink(c.Description) != "" {
+		return
+	}
+	if sm.Config.Discord.EventsChannelID == "" {
+		logger.Warn("cannot import guild event: events channel is not configured")
+		return
+	}
+	event, err := sm.importScheduledEvent(s, c.GuildScheduledEvent, sm.Config.Discord.EventsChannelID)
+	if err != nil {
+		logger.Error("failed to import guild event", logging.ErrorKey, err)
+		return
+	}
+	logger.Info("Imported guild event", logging.EventKey, event.DiscordLink)
+}
+
+// ScheduledEventUpdateHandler copies changes made to a guild scheduled event in Discord onto its linked event
+func (sm *StateManager) ScheduledEventUpdateHandler(s *discordgo.Session, u *discordgo.GuildScheduledEventUpdate) {
+	logger := slog.With(logging.GuildKey, u.GuildID, "guildEvent", u.ID)
+	if util.FindDiscordLink(u.Description) == "" {
+		return
+	}
+	_, msg, err := getLinkedEvent(s, u.GuildScheduledEvent)
+	if err != nil {
+		logger.Error("cannot find event for guild event", logging.ErrorKey, err)
+		return
+	}
+	event, err := sm.updateEvent(s, u.GuildID, msg.ChannelID, msg.ID, func(e *discord.Event) error {
+		// Edits made through the bot are echoed back by Discord and will not have changed anything
+		if !e.SyncFromGuildScheduledEvent(u.GuildScheduledEvent) {
+			return errUnchanged
+		}
+		return nil
+	})
+	if err != nil {
+		if !errors.Is(err, errUnchanged) {
+			logger.Error("failed to edit embed", logging.ErrorKey, err)
+		}
+		return
+	}
+	if sm.CalendarClient != nil {
+		if err = sm.CalendarClient.UpdateEvent(event); err != nil {
+			logger.Error("cannot update google event", logging.ErrorKey, err)
+			return
+		}
+	}
+	logger.Info("Synced guild event", logging.EventKey, logging.EventPath(msg.ChannelID, msg.ID))
+}
+
+// ScheduledEventDeleteHandler removes the linked event when a guild scheduled event is deleted in Discord
+func (sm *StateManager) ScheduledEventDeleteHandler(s *discordgo.Session, d *discordgo.GuildScheduledEventDelete) {
+	logger := slog.With(logging.GuildKey, d.GuildID, "guildEvent", d.ID)
+	if util.FindDiscordLink(d.Description) == "" {
+		return
+	}
+	event, msg, err := getLinkedEvent(s, d.GuildScheduledEvent)
+	if err != nil {
+		// The event post is already gone when deleted through the bot
+		logger.Warn("cannot find event for guild event", logging.ErrorKey, err)
+		return
+	}
+	if sm.CalendarClient != nil {
+		if err = sm.CalendarClient.DeleteEvent(event); err != nil {
+			logger.Error("cannot delete google event", logging.ErrorKey, err)
+			return
+		}
+	}
+	if err = s.ChannelMessageDelete(msg.ChannelID, msg.ID); err != nil {
+		logger.Error("failed to delete message", logging.ErrorKey, err)
+		return
+	}
+	logger.Info("Deleted event with guild event", logging.EventKey, logging.EventPath(msg.ChannelID, msg.ID))
+}
+
+// importScheduledEvent posts a signup embed for a guild scheduled event, creates its calendar entry and links them together
+func (sm *StateManager) importScheduledEvent(s *discordgo.Session, guildEvent *discordgo.GuildScheduledEvent, channelID string) (*discord.Event, error) {
+	event := discord.FromGuildScheduledEvent(guildEvent)
+	if event.Owner == "" {
+		user, err := s.User(guildEvent.CreatorID)
+		if err != nil {
+			return nil, fmt.Errorf("cannot find event creator: %v", err)
+		}
+		event.Owner = user.Username
+	}
+
+	embed, err := discord.ConvertEventToMessageEmbed(event)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := s.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+		Embeds:     []*discordgo.MessageEmbed{embed},
+		Components: discord.EventButtons(sm.Config.Discord.TextButtons),
+	})
+	if err != nil {
+		return nil, err
+	}
+	event.DiscordLink = fmt.Sprintf("https://discord.com/channels/%s/%s/%s", guildEvent.GuildID, channelID, msg.ID)
+
+	// The calendar entry is created once the post exists so a failed post does not leave an orphaned entry
+	if sm.CalendarClient != nil {
+		if err = sm.CalendarClient.CreateGoogleEvent(event); err != nil {
+			if delErr := s.ChannelMessageDelete(channelID, msg.ID); delErr != nil {
+				slog.Warn("cannot delete event post", logging.EventKey, event.DiscordLink, logging.ErrorKey, delErr)
+			}
+			return nil, err
+		}
+		if err = discord.EditEventMessage(s, channelID, msg.ID, event); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err = s.GuildScheduledEventEdit(guildEvent.GuildID, guildEvent.ID, &discordgo.GuildScheduledEventParams{
+		Description: util.AppendDiscordLink(guildEvent.Description, event.DiscordLink, discord.GuildEventDescriptionLimit),
+	}); err != nil {
+		return nil, fmt.Errorf("failed to link guild event: %v", err)
+	}
+	return event, nil
+}
+
+// getLinkedEvent finds the event message a guild scheduled event links to through its description
+func getLinkedEvent(s *discordgo.Session, guildEvent *discordgo.GuildScheduledEvent) (*discord.Event, *discordgo.Message, error) {
+	link := util.FindDiscordLink(guildEvent.Description)
+	if link == "" {
+		return nil, nil, fmt.Errorf("guild event is not linked to a message")
+	}
+	_, channelID, messageID, err := util.GetIDsFromDiscordLink(link)
+	if err != nil {
+		return nil, nil, err
+	}
+	msg, err := s.ChannelMessage(channelID, messageID)
+	if err != nil {
+		return nil, nil, err
+	}
+	event, err := discord.GetEventFromMessage(msg)
+	if err != nil {
+		return nil, nil, err
+	}
+	if event.ScheduledEventID != "" && event.ScheduledEventID != guildEvent.ID {
+		return nil, nil, fmt.Errorf("message %s is linked to another guild event", messageID)
+	}
+	event.DiscordLink = link
+	return event, msg, nil
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// maxHeartbeatAge is how long the gateway can go without acknowledging a heartbeat before the bot is considered wedged
+const maxHeartbeatAge = 2 * time.Minute
+
+// Health reports whether the Discord gateway connection is up
+type Health struct {
+	session   *discordgo.Session
+	connected atomic.Bool
+}
+
+// NewHealth tracks the gateway connection of a session
+func NewHealth(s *discordgo.Session) *Health {
+	h := &Health{session: s}
+	s.AddHandler(func(_ *discordgo.Session, _ *discordgo.Connect) {
+		h.connected.Store(true)
+	})
+	s.AddHandler(func(_ *discordgo.Session, _ *discordgo.Disconnect) {
+		h.connected.Store(false)
+	})
+	return h
+}
+
+// Healthy checks the gateway is connected and still acknowledging heartbeats
+func (h *Health) Healthy() bool {
+	if !h.connected.Load() {
+		return false
+	}
+	h.session.RLock()
+	last := h.session.LastHeartbeatAck
+	h.session.RUnlock()
+	return time.Since(last) < maxHeartbeatAge
+}
+
+func (h *Health) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	if !h.Healthy() {
+		http.Error(w, "gateway disconnected", http.StatusServiceUnavailable)
+		return
+	}
+	_, _ = w.Write([]byte("ok"))
+}
+
+// NewServer creates a server for Prometheus metrics and health checks
+func NewServer(addr string, h *Health) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.Handle("/healthz", h)
+	return &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+}
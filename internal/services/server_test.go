@@ -0,0 +1,53 @@
+package services
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHealth_ServeHTTP(t *testing.T) {
+	cases := []struct {
+		name      string
+		connected bool
+		lastAck   time.Time
+		expected  int
+	}{
+		{
+			name:      "connected",
+			connected: true,
+			lastAck:   time.Now(),
+			expected:  http.StatusOK,
+		},
+		{
+			name:      "disconnected",
+			connected: false,
+			lastAck:   time.Now(),
+			expected:  http.StatusServiceUnavailable,
+		},
+		{
+			name:      "missed heartbeats",
+			connected: true,
+			lastAck:   time.Now().Add(-time.Hour),
+			expected:  http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := discordgo.New("Bot token")
+			assert.NoError(t, err)
+			s.LastHeartbeatAck = tc.lastAck
+
+			h := NewHealth(s)
+			h.connected.Store(tc.connected)
+
+			rec := httptest.NewRecorder()
+			NewServer("", h).Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+			assert.Equal(t, tc.expected, rec.Code)
+		})
+	}
+}
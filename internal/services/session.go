@@ -2,7 +2,7 @@ package services
 
 import (
 	"github.com/bwmarrin/discordgo"
-	"log"
+	"log/slog"
 )
 
 // NewDiscordSession creates a new discord session
@@ -16,5 +16,5 @@ func NewDiscordSession(token string) (*discordgo.Session, error) {
 }
 
 func ReadyEvent(_ *discordgo.Session, _ *discordgo.Ready) {
-	log.Println("Discord session is up")
+	slog.Info("Discord session is up")
 }
@@ -0,0 +1,168 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/discord"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/role"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/errs"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/logging"
+	"github.com/bwmarrin/discordgo"
+	"time"
+)
+
+// errUnchanged is returned by changes passed to updateEvent which found nothing to do
+var errUnchanged = errors.New("event unchanged")
+
+// updateEvent applies a change to the latest version of an event message. Changes made by this process to the same
+// message are serialized, but nothing stops another running instance of the bot from writing in between.
+func (sm *StateManager) updateEvent(s *discordgo.Session, guildID, channelID, messageID string, apply func(e *discord.Event) error) (*discord.Event, error) {
+	return sm.updateEventComponents(s, guildID, channelID, messageID, nil, apply)
+}
+
+// updateEventComponents is updateEvent which also replaces the buttons of the message in the same edit, unless
+// components is nil
+func (sm *StateManager) updateEventComponents(s *discordgo.Session, guildID, channelID, messageID string, components []discordgo.MessageComponent, apply func(e *discord.Event) error) (*discord.Event, error) {
+	unlock := sm.LockEvent(messageID)
+	defer unlock()
+
+	event, err := getEvent(s, guildID, channelID, messageID)
+	if err != nil {
+		return nil, err
+	}
+	if err = apply(event); err != nil {
+		return nil, err
+	}
+	if err = discord.EditEventMessageComponents(s, channelID, messageID, event, components); err != nil {
+		return nil, fmt.Errorf("failed to edit embed: %w", err)
+	}
+	return event, nil
+}
+
+// toggleResponse toggles the user's response on the latest version of the event, notifies anyone moved off the
+// waitlist and tells the user where they ended up
+func (sm *StateManager) toggleResponse(s *discordgo.Session, i *discordgo.InteractionCreate, field role.FieldType) error {
+	name := i.Member.User.Username
+	weekly, err := sm.acceptedThisWeek(s, i, field)
+	if err != nil {
+		return err
+	}
+	var before, after role.Response
+	var promoted []string
+	var rules *role.Rules
+	event, err := sm.updateEvent(s, i.GuildID, i.ChannelID, i.Message.ID, func(e *discord.Event) (err error) {
+		before = e.RoleGroup.GetResponse(name)
+		if err = checkSignupsOpen(e, before, field); err != nil {
+			return err
+		}
+		rules = sm.signupRules(s, i, weekly)
+		e.RoleGroup.Rules = rules
+		if promoted, err = e.ToggleResponse(field, name); err != nil {
+			return err
+		}
+		after = e.RoleGroup.GetResponse(name)
+		return nil
+	})
+	var ruleErr *role.RuleError
+	switch {
+	case errors.Is(err, role.ErrEventFull):
+		return errs.NewValidationf("%s is full", field)
+	case errors.As(err, &ruleErr):
+		return errs.NewValidationf(ruleErr.Reason, ruleErr.Args...)
+	case err != nil:
+		return err
+	}
+	sm.recordLateCancellation(i, event, before, after)
+	sm.alertLateCancellation(s, i, event, before, after, promoted)
+	if err = event.SyncThreadMember(s, i.Member.User.ID, before, after); err != nil {
+		logging.FromInteraction(i).Error("cannot update thread members", logging.EventKey, event.DiscordLink, logging.ErrorKey, err)
+	}
+	if err = notifyPromoted(s, sm.memberPrinter, i.GuildID, event, promoted); err != nil {
+		return err
+	}
+	p := sm.printer(i)
+	var reason string
+	if rules != nil && after.Waitlist > 0 && rules.Waitlisted.Reason != "" {
+		reason = p.Tf(rules.Waitlisted.Reason, rules.Waitlisted.Args...)
+	}
+	if _, err = s.FollowupMessageCreate(i.Interaction, false, discord.SignupStatusMessage(p, i.ChannelID, i.Message.ID, before, after, event.MaxGuests, reason)); err != nil {
+		return fmt.Errorf("failed to send signup status: %w", err)
+	}
+	return nil
+}
+
+// setGuests changes how many guests the user is bringing to an event and notifies anyone moved off the waitlist
+func (sm *StateManager) setGuests(s *discordgo.Session, i *discordgo.InteractionCreate, channelID, messageID string, guests int) error {
+	name := i.Member.User.Username
+	var promoted []string
+	event, err := sm.updateEvent(s, i.GuildID, channelID, messageID, func(e *discord.Event) (err error) {
+		// Bringing fewer guests frees up spots so it is always allowed
+		if guests > e.RoleGroup.Guests[name] && e.SignupsClosed(time.Now()) {
+			return errs.NewValidation("Signups for this event are closed. You can still bring fewer guests.")
+		}
+		if e.RoleGroup.GetResponse(name).Field != role.AcceptedField {
+			return errs.NewValidation("Accept the event before adding guests")
+		}
+		if guests > e.MaxGuests {
+			return errs.NewValidationf("You can bring up to %d guests to this event", e.MaxGuests)
+		}
+		if guests == e.RoleGroup.Guests[name] {
+			return errUnchanged
+		}
+		promoted, err = e.SetGuests(name, guests)
+		return err
+	})
+	switch {
+	case errors.Is(err, errUnchanged):
+		return nil
+	case errors.Is(err, role.ErrNoRoomForGuests):
+		return errs.NewValidation("There aren't enough spots left for your guests")
+	case err != nil:
+		return err
+	}
+	return notifyPromoted(s, sm.memberPrinter, i.GuildID, event, promoted)
+}
+
+// notifyPromoted tells users they were moved off the waitlist
+func notifyPromoted(s *discordgo.Session, printers discord.PrinterFunc, guildID string, event *discord.Event, promoted []string) error {
+	for _, name := range promoted {
+		if err := event.NotifyUserOffWaitlist(s, printers, guildID, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkSignupsOpen rejects responses which would add a user to an event after its signups closed. Dropping out and
+// declining are always allowed.
+func checkSignupsOpen(e *discord.Event, before role.Response, field role.FieldType) error {
+	if field != role.AcceptedField && field != role.TentativeField {
+		return nil
+	}
+	if before.Field == field || !e.SignupsClosed(time.Now()) {
+		return nil
+	}
+	if e.Archived {
+		return errs.NewValidation("This event has ended")
+	}
+	if e.Locked {
+		return errs.NewValidation("Signups for this event have been locked by the organizer. You can still drop out.")
+	}
+	return errs.NewValidation("The signup deadline for this event has passed. You can still drop out.")
+}
+
+func getEvent(s *discordgo.Session, guildID, channelID, messageID string) (*discord.Event, error) {
+	msg, err := s.ChannelMessage(channelID, messageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get message: %w", err)
+	}
+	event, err := discord.GetEventFromMessage(msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+	// Messages fetched through the API do not include the guild
+	if event.DiscordLink == "" {
+		event.DiscordLink = fmt.Sprintf("https://discord.com/channels/%s/%s/%s", guildID, channelID, messageID)
+	}
+	return event, nil
+}
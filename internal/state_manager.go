@@ -1,39 +1,166 @@
 package internal
 
 import (
+	"context"
+	"fmt"
+	"github.com/GuessWhoSamFoo/fsm"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states"
 	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/discord"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/logging"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/ratelimit"
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/store"
 	"github.com/bwmarrin/discordgo"
+	"log/slog"
 	"sync"
+	"time"
+)
+
+// Users may send a burst of interactions, such as toggling signups, but not flood the bot
+const (
+	interactionLimit  = 5
+	interactionWindow = 10 * time.Second
 )
 
 type StateManager struct {
 	ActiveMap
-	CommandHandlers   map[string]func(s *discordgo.Session, i *discordgo.InteractionCreate)
-	ComponentHandlers map[string]func(s *discordgo.Session, i *discordgo.InteractionCreate)
+	EventLocks
+	CommandHandlers   map[string]Handler
+	ComponentHandlers map[string]Handler
 	CalendarClient    *discord.CalendarClient
 	Config            *Config
+	Store             *store.Store
+	Categories        []discord.Category
+
+	limiter *ratelimit.Limiter
 }
 
 func NewStateManager(config *Config) *StateManager {
 	sm := &StateManager{
-		Config: config,
+		Config:  config,
+		limiter: ratelimit.NewLimiter(interactionLimit, interactionWindow),
 	}
 	sm.ActiveMap = ActiveMap{
 		userMap: make(map[string]struct{}),
 	}
-	sm.CommandHandlers = map[string]func(s *discordgo.Session, i *discordgo.InteractionCreate){
-		"event":           sm.CreateEventHandler,
-		"my_events":       sm.ListMyEventsHandler,
-		"upcoming_events": sm.ListUpcomingEventsHandler,
+	sm.EventLocks = EventLocks{
+		locks: make(map[string]*eventLock),
+	}
+	sm.CommandHandlers = map[string]Handler{
+		"event": {
+			Handle:    sm.CreateEventHandler,
+			GuildOnly: true,
+			Exclusive: true,
+		},
+		"my_events": {
+			Handle:    sm.ListMyEventsHandler,
+			Ack:       AckDeferEphemeral,
+			Calendar:  true,
+			GuildOnly: true,
+		},
+		"upcoming_events": {
+			Handle:   sm.ListUpcomingEventsHandler,
+			Ack:      AckDeferEphemeral,
+			Calendar: true,
+		},
+		"stats": {
+			Handle:    sm.StatsHandler,
+			Ack:       AckDeferEphemeral,
+			GuildOnly: true,
+		},
+		"announce": {
+			Handle:    sm.AnnounceHandler,
+			Ack:       AckDeferEphemeral,
+			GuildOnly: true,
+		},
+		"drafts": {
+			Handle:    sm.DraftsHandler,
+			Ack:       AckDeferEphemeral,
+			GuildOnly: true,
+		},
+		"accessibility": {
+			Handle: sm.AccessibilityHandler,
+			Ack:    AckDeferEphemeral,
+		},
+		"import_events": {
+			Handle:      sm.ImportEventsHandler,
+			Ack:         AckDeferEphemeral,
+			Calendar:    true,
+			Permissions: discordgo.PermissionManageEvents,
+		},
 		//"edit":      EditEventHandler,
 	}
-	sm.ComponentHandlers = map[string]func(s *discordgo.Session, i *discordgo.InteractionCreate){
-		"accept":        sm.AcceptHandler,
-		"decline":       sm.DeclineHandler,
-		"tentative":     sm.TentativeHandler,
-		"edit":          sm.EditHandler,
-		"delete":        sm.DeleteHandler,
-		"confirmDelete": sm.ConfirmDeleteHandler,
+	sm.ComponentHandlers = map[string]Handler{
+		"accept": {
+			Handle:    sm.AcceptHandler,
+			Ack:       AckUpdate,
+			GuildOnly: true,
+		},
+		"decline": {
+			Handle:    sm.DeclineHandler,
+			Ack:       AckUpdate,
+			GuildOnly: true,
+		},
+		"tentative": {
+			Handle:    sm.TentativeHandler,
+			Ack:       AckUpdate,
+			GuildOnly: true,
+		},
+		discord.UndoPrefix: {
+			Handle:    sm.UndoHandler,
+			Ack:       AckUpdate,
+			GuildOnly: true,
+		},
+		discord.GuestsPrefix: {
+			Handle:    sm.GuestsHandler,
+			Ack:       AckUpdate,
+			GuildOnly: true,
+		},
+		discord.MuteAlertsPrefix: {
+			Handle: sm.MuteAlertsHandler,
+			Ack:    AckUpdate,
+		},
+		discord.MyEventsPrefix: {
+			Handle:    sm.ListMyEventsHandler,
+			Ack:       AckDeferEphemeral,
+			Calendar:  true,
+			GuildOnly: true,
+		},
+		discord.DraftEditPrefix: {
+			Handle:    sm.DraftEditHandler,
+			GuildOnly: true,
+			Exclusive: true,
+		},
+		discord.DraftCancelPrefix: {
+			Handle:    sm.DraftCancelHandler,
+			Ack:       AckUpdate,
+			GuildOnly: true,
+		},
+		"edit": {
+			Handle:       sm.EditHandler,
+			Ack:          AckUpdate,
+			Calendar:     true,
+			Permissions:  discordgo.PermissionManageEvents,
+			OwnerAllowed: true,
+			Exclusive:    true,
+		},
+		"delete": {
+			Handle:       sm.DeleteHandler,
+			Ack:          AckUpdate,
+			Calendar:     true,
+			Permissions:  discordgo.PermissionManageEvents,
+			OwnerAllowed: true,
+		},
+		"lock": {
+			Handle:       sm.LockHandler,
+			Ack:          AckUpdate,
+			Permissions:  discordgo.PermissionManageEvents,
+			OwnerAllowed: true,
+		},
+		"confirmDelete": {
+			Handle:   sm.ConfirmDeleteHandler,
+			Ack:      AckUpdate,
+			Calendar: true,
+		},
 	}
 	return sm
 }
@@ -50,6 +177,17 @@ func (a *ActiveMap) AddUser(user string) {
 	a.userMap[user] = struct{}{}
 }
 
+// TryAddUser adds a user unless they are already present and reports whether they were added
+func (a *ActiveMap) TryAddUser(user string) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, ok := a.userMap[user]; ok {
+		return false
+	}
+	a.userMap[user] = struct{}{}
+	return true
+}
+
 func (a *ActiveMap) RemoveUser(user string) {
 	a.mu.Lock()
 	defer a.mu.Unlock()
@@ -62,3 +200,71 @@ func (a *ActiveMap) HasUser(user string) bool {
 	_, ok := a.userMap[user]
 	return ok
 }
+
+// EventLocks serializes changes to the same event message
+type EventLocks struct {
+	mu    sync.Mutex
+	locks map[string]*eventLock
+}
+
+type eventLock struct {
+	sync.Mutex
+	waiters int
+}
+
+// LockEvent blocks until no other change to the message is in progress and returns a func to release it
+func (l *EventLocks) LockEvent(messageID string) func() {
+	l.mu.Lock()
+	lock, ok := l.locks[messageID]
+	if !ok {
+		lock = &eventLock{}
+		l.locks[messageID] = lock
+	}
+	lock.waiters++
+	l.mu.Unlock()
+
+	lock.Lock()
+	return func() {
+		lock.Unlock()
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		lock.waiters--
+		if lock.waiters == 0 {
+			delete(l.locks, messageID)
+		}
+	}
+}
+
+// runSequence fires the states of a DM sequence from state on, asking next for the state after each one. A nil next is
+// for sequences whose states move on by themselves. When the user goes back, the sequence carries on from the
+// previous prompt.
+func runSequence(ctx context.Context, f *fsm.FSM, state string, next func(f *fsm.FSM) string) error {
+	for state != "" {
+		if err := f.Event(ctx, state); err != nil {
+			if f.Current() != states.Back.String() {
+				return err
+			}
+			previous, ok := states.PreviousPrompt(f)
+			if !ok {
+				return fmt.Errorf("no prompt to go back to: %w", err)
+			}
+			state = previous
+			continue
+		}
+		if next == nil {
+			return nil
+		}
+		state = next(f)
+	}
+	return nil
+}
+
+// sequenceError returns why a DM sequence stopped before completing. Users canceling or timing out is not an error.
+func sequenceError(logger *slog.Logger, f *fsm.FSM, err error) error {
+	state := f.Current()
+	if state == states.Cancel.String() || state == states.Timeout.String() {
+		logger.Info("sequence stopped", logging.StateKey, state, logging.ErrorKey, err)
+		return nil
+	}
+	return fmt.Errorf("sequence failed in state %s: %w", state, err)
+}
@@ -0,0 +1,62 @@
+package store
+
+import (
+	"github.com/GuessWhoSamFoo/gang-gang-bot/internal/commands/states/role"
+	"time"
+)
+
+// MemberEvent is a recorded event a member accepted or was checked in to
+type MemberEvent struct {
+	Title     string
+	Start     time.Time
+	ChannelID string
+	MessageID string
+	// Attended is only known for events which took attendance
+	TookAttendance bool
+	Attended       bool
+}
+
+// MemberStats is the attendance history of a member
+type MemberStats struct {
+	Events   []MemberEvent // most recent first
+	Attended int
+	NoShows  int // accepted but not checked in
+}
+
+// MemberStats gets the attendance history of a member from recorded events
+func (s *Store) MemberStats(name string) MemberStats {
+	var stats MemberStats
+	records := s.ListAttendance()
+	for i := len(records) - 1; i >= 0; i-- {
+		a := records[i]
+		accepted := contains(a.Responses[string(role.AcceptedField)], name)
+		attended := a.HasCheckedIn(name)
+		if !accepted && !attended {
+			continue
+		}
+		stats.Events = append(stats.Events, MemberEvent{
+			Title:          a.Title,
+			Start:          a.Start,
+			ChannelID:      a.ChannelID,
+			MessageID:      a.MessageID,
+			TookAttendance: a.TookAttendance,
+			Attended:       attended,
+		})
+		switch {
+		case attended:
+			stats.Attended++
+		case a.TookAttendance:
+			stats.NoShows++
+		}
+	}
+	return stats
+}
+
+func contains(list []string, name string) bool {
+	for _, n := range list {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package store
+
+import (
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStore_MemberStats(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "store.json"))
+	assert.NoError(t, err)
+	start := time.Date(2023, 6, 10, 18, 0, 0, 0, time.UTC)
+
+	records := []Attendance{
+		{
+			MessageID:      "1",
+			Title:          "attended",
+			Start:          start,
+			Responses:      map[string][]string{"Accepted": {"foo", "bar"}},
+			TookAttendance: true,
+			CheckedIn:      []string{"foo"},
+		},
+		{
+			MessageID:      "2",
+			Title:          "no show",
+			Start:          start.Add(24 * time.Hour),
+			Responses:      map[string][]string{"Accepted": {"foo"}},
+			TookAttendance: true,
+		},
+		{
+			MessageID: "3",
+			Title:     "no attendance taken",
+			Start:     start.Add(48 * time.Hour),
+			Responses: map[string][]string{"Accepted": {"foo"}},
+		},
+		{
+			MessageID: "4",
+			Title:     "declined",
+			Start:     start.Add(72 * time.Hour),
+			Responses: map[string][]string{"Declined": {"foo"}},
+		},
+	}
+	for _, r := range records {
+		assert.NoError(t, s.RecordAttendance(r))
+	}
+
+	cases := []struct {
+		name             string
+		member           string
+		expectedTitles   []string
+		expectedAttended int
+		expectedNoShows  int
+	}{
+		{
+			name:             "history",
+			member:           "foo",
+			expectedTitles:   []string{"no attendance taken", "no show", "attended"},
+			expectedAttended: 1,
+			expectedNoShows:  1,
+		},
+		{
+			name:            "missed only event",
+			member:          "bar",
+			expectedTitles:  []string{"attended"},
+			expectedNoShows: 1,
+		},
+		{
+			name:   "unknown member",
+			member: "baz",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			stats := s.MemberStats(tc.member)
+			var titles []string
+			for _, e := range stats.Events {
+				titles = append(titles, e.Title)
+			}
+			assert.Equal(t, tc.expectedTitles, titles)
+			assert.Equal(t, tc.expectedAttended, stats.Attended)
+			assert.Equal(t, tc.expectedNoShows, stats.NoShows)
+		})
+	}
+}
@@ -0,0 +1,459 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Attendance is the final roster of an event after it ended
+type Attendance struct {
+	ChannelID string              `json:"channel_id"`
+	MessageID string              `json:"message_id"`
+	Title     string              `json:"title"`
+	Owner     string              `json:"owner"`
+	Start     time.Time           `json:"start"`
+	End       time.Time           `json:"end"`
+	Responses map[string][]string `json:"responses"` // users by response field
+	Waitlist  []string            `json:"waitlist,omitempty"`
+	Recorded  time.Time           `json:"recorded"`
+
+	// TookAttendance is set once an organizer checked in the members who showed up
+	TookAttendance bool     `json:"took_attendance,omitempty"`
+	CheckedIn      []string `json:"checked_in,omitempty"`
+
+	// ArchivePending is set while the thread or archive channel copy of an ended event are still to be done
+	ArchivePending bool `json:"archive_pending,omitempty"`
+}
+
+// HasCheckedIn reports whether a member was checked in
+func (a Attendance) HasCheckedIn(name string) bool {
+	return contains(a.CheckedIn, name)
+}
+
+// Cancellation is a member dropping out of an event shortly before it started
+type Cancellation struct {
+	Name      string    `json:"name"`
+	MessageID string    `json:"message_id"`
+	Start     time.Time `json:"start"`
+	At        time.Time `json:"at"`
+}
+
+// Announcement is a message an organizer sent to the members who signed up for an event
+type Announcement struct {
+	ChannelID string    `json:"channel_id"`
+	MessageID string    `json:"message_id"`
+	Author    string    `json:"author"`
+	Text      string    `json:"text"`
+	Roles     []string  `json:"roles"`
+	Delivered []string  `json:"delivered,omitempty"`
+	Mentioned []string  `json:"mentioned,omitempty"` // members with DMs closed who were mentioned in the event channel
+	Failed    []string  `json:"failed,omitempty"`
+	At        time.Time `json:"at"`
+}
+
+// Preferences are how a member wants the bot to talk to them
+type Preferences struct {
+	// Accessible replaces emoji, special spacing and Discord timestamps with plain text for screen readers
+	Accessible bool `json:"accessible,omitempty"`
+	// Locale is the language of the member's Discord client when they last used the bot. Messages sent to them outside
+	// their own interactions use it.
+	Locale string `json:"locale,omitempty"`
+}
+
+// Draft is an event an organizer saved from the creation DMs to finish later
+type Draft struct {
+	ID      string `json:"id"`
+	GuildID string `json:"guild_id"`
+	OwnerID string `json:"owner_id"`
+	// ChannelID is the channel chosen for the event with the command, if any
+	ChannelID   string    `json:"channel_id,omitempty"`
+	Title       string    `json:"title"`
+	Description string    `json:"description,omitempty"`
+	Location    string    `json:"location,omitempty"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end,omitempty"`
+	Deadline    time.Time `json:"deadline,omitempty"`
+	Limit       int       `json:"limit,omitempty"`
+	Category    string    `json:"category,omitempty"`
+	Owner       string    `json:"owner,omitempty"` // username of the organizer
+	Saved       time.Time `json:"saved"`
+
+	// PublishAt is when a scheduled post goes live. Drafts without one wait for the organizer.
+	PublishAt time.Time `json:"publish_at,omitempty"`
+	// PostChannelID is the channel a scheduled post goes to, chosen when it was scheduled
+	PostChannelID string `json:"post_channel_id,omitempty"`
+	// Locale is the language the organizer is messaged in when a scheduled post goes live
+	Locale string `json:"locale,omitempty"`
+}
+
+// Scheduled reports whether a draft is posted automatically
+func (d Draft) Scheduled() bool {
+	return !d.PublishAt.IsZero()
+}
+
+// data is everything kept by the store
+type data struct {
+	Attendance        map[string]Attendance  `json:"attendance"` // keyed by message ID
+	LateCancellations []Cancellation         `json:"late_cancellations,omitempty"`
+	MutedAlerts       []string               `json:"muted_alerts,omitempty"` // message IDs of events without alerts
+	Announcements     []Announcement         `json:"announcements,omitempty"`
+	Preferences       map[string]Preferences `json:"preferences,omitempty"` // keyed by user ID
+	Drafts            map[string]Draft       `json:"drafts,omitempty"`      // keyed by draft ID
+}
+
+// Store keeps records which do not fit in event messages in a JSON file
+type Store struct {
+	mu   sync.RWMutex
+	path string
+	data data
+}
+
+// Open loads a store from a file. A missing file is an empty store.
+func Open(path string) (*Store, error) {
+	s := &Store{
+		path: path,
+		data: data{Attendance: map[string]Attendance{}},
+	}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(b, &s.data); err != nil {
+		return nil, fmt.Errorf("cannot read store %s: %w", path, err)
+	}
+	if s.data.Attendance == nil {
+		s.data.Attendance = map[string]Attendance{}
+	}
+	return s, nil
+}
+
+// RecordAttendance saves the roster of an event, replacing any earlier record for the same message. Check-ins from an
+// earlier record are kept unless the new record took attendance itself, and so is a pending archive.
+func (s *Store) RecordAttendance(a Attendance) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if prev, ok := s.data.Attendance[a.MessageID]; ok {
+		if prev.TookAttendance && !a.TookAttendance {
+			a.TookAttendance, a.CheckedIn = true, prev.CheckedIn
+		}
+		a.ArchivePending = a.ArchivePending || prev.ArchivePending
+	}
+	s.data.Attendance[a.MessageID] = a
+	return s.save()
+}
+
+// FinishArchive clears the pending archive of an event message once its thread and archive channel copy are done
+func (s *Store) FinishArchive(messageID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, ok := s.data.Attendance[messageID]
+	if !ok || !a.ArchivePending {
+		return nil
+	}
+	a.ArchivePending = false
+	s.data.Attendance[messageID] = a
+	return s.save()
+}
+
+// Attendance gets the recorded roster of an event message
+func (s *Store) Attendance(messageID string) (Attendance, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	a, ok := s.data.Attendance[messageID]
+	return a, ok
+}
+
+// ListAttendance gets every recorded roster ordered by start time
+func (s *Store) ListAttendance() []Attendance {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	result := make([]Attendance, 0, len(s.data.Attendance))
+	for _, a := range s.data.Attendance {
+		result = append(result, a)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Start.Before(result[j].Start)
+	})
+	return result
+}
+
+// RecordLateCancellation saves a member dropping out of an event shortly before it started
+func (s *Store) RecordLateCancellation(c Cancellation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.LateCancellations = append(s.data.LateCancellations, c)
+	return s.save()
+}
+
+// ClearLateCancellation removes the late cancellations of a member from an event, such as when they rejoin it
+func (s *Store) ClearLateCancellation(name, messageID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	kept := s.data.LateCancellations[:0]
+	for _, c := range s.data.LateCancellations {
+		if c.Name != name || c.MessageID != messageID {
+			kept = append(kept, c)
+		}
+	}
+	if len(kept) == len(s.data.LateCancellations) {
+		return nil
+	}
+	s.data.LateCancellations = kept
+	return s.save()
+}
+
+// LateCancellations gets the late cancellations of a member since a time
+func (s *Store) LateCancellations(name string, since time.Time) []Cancellation {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var result []Cancellation
+	for _, c := range s.data.LateCancellations {
+		if c.Name == name && !c.At.Before(since) {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// MuteAlerts turns organizer alerts for an event message off or back on
+func (s *Store) MuteAlerts(messageID string, muted bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if contains(s.data.MutedAlerts, messageID) == muted {
+		return nil
+	}
+	if muted {
+		s.data.MutedAlerts = append(s.data.MutedAlerts, messageID)
+	} else {
+		kept := s.data.MutedAlerts[:0]
+		for _, id := range s.data.MutedAlerts {
+			if id != messageID {
+				kept = append(kept, id)
+			}
+		}
+		s.data.MutedAlerts = kept
+	}
+	return s.save()
+}
+
+// AlertsMuted reports whether the organizer muted alerts for an event message
+func (s *Store) AlertsMuted(messageID string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return contains(s.data.MutedAlerts, messageID)
+}
+
+// RecordAnnouncement saves an announcement for auditing
+func (s *Store) RecordAnnouncement(a Announcement) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Announcements = append(s.data.Announcements, a)
+	return s.save()
+}
+
+// Announcements gets the announcements sent about an event message, oldest first
+func (s *Store) Announcements(messageID string) []Announcement {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var result []Announcement
+	for _, a := range s.data.Announcements {
+		if a.MessageID == messageID {
+			result = append(result, a)
+		}
+	}
+	return result
+}
+
+// MoveEvent keeps the records of an event message when its post is moved to another channel as a new message
+func (s *Store) MoveEvent(messageID, channelID, newMessageID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	moved := false
+	if a, ok := s.data.Attendance[messageID]; ok {
+		delete(s.data.Attendance, messageID)
+		a.ChannelID, a.MessageID = channelID, newMessageID
+		s.data.Attendance[newMessageID] = a
+		moved = true
+	}
+	for i, c := range s.data.LateCancellations {
+		if c.MessageID == messageID {
+			s.data.LateCancellations[i].MessageID = newMessageID
+			moved = true
+		}
+	}
+	for i, id := range s.data.MutedAlerts {
+		if id == messageID {
+			s.data.MutedAlerts[i] = newMessageID
+			moved = true
+		}
+	}
+	for i, a := range s.data.Announcements {
+		if a.MessageID == messageID {
+			s.data.Announcements[i].ChannelID, s.data.Announcements[i].MessageID = channelID, newMessageID
+			moved = true
+		}
+	}
+	if !moved {
+		return nil
+	}
+	return s.save()
+}
+
+// Prune removes late cancellations and announcements made before a time so the file does not grow without bound
+func (s *Store) Prune(before time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cancellations := s.data.LateCancellations[:0]
+	for _, c := range s.data.LateCancellations {
+		if !c.At.Before(before) {
+			cancellations = append(cancellations, c)
+		}
+	}
+	announcements := s.data.Announcements[:0]
+	for _, a := range s.data.Announcements {
+		if !a.At.Before(before) {
+			announcements = append(announcements, a)
+		}
+	}
+	if len(cancellations) == len(s.data.LateCancellations) && len(announcements) == len(s.data.Announcements) {
+		return nil
+	}
+	s.data.LateCancellations, s.data.Announcements = cancellations, announcements
+	return s.save()
+}
+
+// SetPreferences saves the preferences of a user
+func (s *Store) SetPreferences(userID string, p Preferences) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if prev, ok := s.data.Preferences[userID]; ok && prev == p {
+		return nil
+	}
+	if s.data.Preferences == nil {
+		s.data.Preferences = map[string]Preferences{}
+	}
+	s.data.Preferences[userID] = p
+	return s.save()
+}
+
+// Preferences gets the preferences of a user. Users who never set any get the defaults.
+func (s *Store) Preferences(userID string) Preferences {
+	if s == nil {
+		return Preferences{}
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.data.Preferences[userID]
+}
+
+// SaveDraft saves a draft, replacing any earlier version with the same ID
+func (s *Store) SaveDraft(d Draft) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.data.Drafts == nil {
+		s.data.Drafts = map[string]Draft{}
+	}
+	s.data.Drafts[d.ID] = d
+	return s.save()
+}
+
+// DeleteDraft removes a draft, such as once it was published
+func (s *Store) DeleteDraft(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.data.Drafts[id]; !ok {
+		return nil
+	}
+	delete(s.data.Drafts, id)
+	return s.save()
+}
+
+// ClaimDraft removes a draft so it can be published, reporting false when it was already removed, such as by another
+// caller publishing it at the same time. Only the caller which removed it may publish it.
+func (s *Store) ClaimDraft(id string) (Draft, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.data.Drafts[id]
+	if !ok {
+		return Draft{}, false, nil
+	}
+	delete(s.data.Drafts, id)
+	if err := s.save(); err != nil {
+		s.data.Drafts[id] = d
+		return Draft{}, false, err
+	}
+	return d, true, nil
+}
+
+// Draft gets a draft by ID
+func (s *Store) Draft(id string) (Draft, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	d, ok := s.data.Drafts[id]
+	return d, ok
+}
+
+// DueDrafts gets the scheduled posts which should be published by a time, earliest first
+func (s *Store) DueDrafts(now time.Time) []Draft {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var result []Draft
+	for _, d := range s.data.Drafts {
+		if d.Scheduled() && !d.PublishAt.After(now) {
+			result = append(result, d)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].PublishAt.Before(result[j].PublishAt)
+	})
+	return result
+}
+
+// Drafts gets the drafts a user saved in a guild, oldest first
+func (s *Store) Drafts(guildID, ownerID string) []Draft {
+	if s == nil {
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var result []Draft
+	for _, d := range s.data.Drafts {
+		if d.GuildID == guildID && d.OwnerID == ownerID {
+			result = append(result, d)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Saved.Before(result[j].Saved)
+	})
+	return result
+}
+
+// save writes the store to a temporary file first so a crash cannot leave a partial file behind
+func (s *Store) save() error {
+	b, err := json.MarshalIndent(s.data, "", "  ")
+	if err != nil {
+		return err
+	}
+	temp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+	if _, err = temp.Write(b); err != nil {
+		temp.Close()
+		return err
+	}
+	if err = temp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(temp.Name(), s.path)
+}
@@ -0,0 +1,349 @@
+package store
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestOpen(t *testing.T) {
+	dir := t.TempDir()
+
+	cases := []struct {
+		name     string
+		contents string
+		isErr    bool
+	}{
+		{
+			name: "missing file",
+		},
+		{
+			name:     "empty store",
+			contents: `{}`,
+		},
+		{
+			name:     "invalid",
+			contents: `{`,
+			isErr:    true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(dir, tc.name+".json")
+			if tc.contents != "" {
+				assert.NoError(t, os.WriteFile(path, []byte(tc.contents), 0600))
+			}
+			s, err := Open(path)
+			if tc.isErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Empty(t, s.ListAttendance())
+		})
+	}
+}
+
+func TestStore_RecordAttendance(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.json")
+	s, err := Open(path)
+	assert.NoError(t, err)
+
+	start := time.Date(2023, 6, 10, 18, 0, 0, 0, time.UTC)
+	later := Attendance{
+		MessageID: "2",
+		Title:     "later",
+		Start:     start.Add(time.Hour),
+		Responses: map[string][]string{"Accepted": {"foo"}},
+	}
+	earlier := Attendance{
+		MessageID: "1",
+		Title:     "earlier",
+		Start:     start,
+		Responses: map[string][]string{"Accepted": {"bar"}},
+	}
+	assert.NoError(t, s.RecordAttendance(later))
+	assert.NoError(t, s.RecordAttendance(earlier))
+
+	earlier.Responses["Accepted"] = []string{"bar", "baz"}
+	assert.NoError(t, s.RecordAttendance(earlier))
+
+	reopened, err := Open(path)
+	assert.NoError(t, err)
+	got := reopened.ListAttendance()
+	assert.Len(t, got, 2)
+	assert.Equal(t, "earlier", got[0].Title)
+	assert.Equal(t, []string{"bar", "baz"}, got[0].Responses["Accepted"])
+
+	a, ok := reopened.Attendance("2")
+	assert.True(t, ok)
+	assert.Equal(t, "later", a.Title)
+}
+
+func TestStore_RecordAttendanceKeepsCheckIns(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "store.json"))
+	assert.NoError(t, err)
+
+	assert.NoError(t, s.RecordAttendance(Attendance{
+		MessageID:      "1",
+		Responses:      map[string][]string{"Accepted": {"foo", "bar"}},
+		TookAttendance: true,
+		CheckedIn:      []string{"foo"},
+	}))
+	// Archiving records the final roster without check-ins
+	assert.NoError(t, s.RecordAttendance(Attendance{
+		MessageID: "1",
+		Responses: map[string][]string{"Accepted": {"foo", "bar", "baz"}},
+	}))
+
+	a, ok := s.Attendance("1")
+	assert.True(t, ok)
+	assert.True(t, a.TookAttendance)
+	assert.Equal(t, []string{"foo"}, a.CheckedIn)
+	assert.Equal(t, []string{"foo", "bar", "baz"}, a.Responses["Accepted"])
+}
+
+func TestStore_FinishArchive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.json")
+	s, err := Open(path)
+	assert.NoError(t, err)
+
+	assert.NoError(t, s.RecordAttendance(Attendance{MessageID: "1", ArchivePending: true}))
+	// Taking attendance before the archive finished does not drop it
+	assert.NoError(t, s.RecordAttendance(Attendance{MessageID: "1", TookAttendance: true, CheckedIn: []string{"foo"}}))
+	a, _ := s.Attendance("1")
+	assert.True(t, a.ArchivePending)
+
+	assert.NoError(t, s.FinishArchive("1"))
+	assert.NoError(t, s.FinishArchive("missing"))
+
+	reopened, err := Open(path)
+	assert.NoError(t, err)
+	a, ok := reopened.Attendance("1")
+	assert.True(t, ok)
+	assert.False(t, a.ArchivePending)
+	assert.Equal(t, []string{"foo"}, a.CheckedIn)
+}
+
+func TestStore_LateCancellations(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "store.json"))
+	assert.NoError(t, err)
+	now := time.Date(2023, 6, 10, 18, 0, 0, 0, time.UTC)
+
+	assert.NoError(t, s.RecordLateCancellation(Cancellation{Name: "foo", MessageID: "1", At: now.Add(-100 * 24 * time.Hour)}))
+	assert.NoError(t, s.RecordLateCancellation(Cancellation{Name: "foo", MessageID: "2", At: now.Add(-time.Hour)}))
+	assert.NoError(t, s.RecordLateCancellation(Cancellation{Name: "foo", MessageID: "3", At: now}))
+	assert.NoError(t, s.RecordLateCancellation(Cancellation{Name: "bar", MessageID: "3", At: now}))
+
+	assert.Len(t, s.LateCancellations("foo", now.Add(-90*24*time.Hour)), 2)
+
+	assert.NoError(t, s.ClearLateCancellation("foo", "3"))
+	got := s.LateCancellations("foo", now.Add(-90*24*time.Hour))
+	assert.Len(t, got, 1)
+	assert.Equal(t, "2", got[0].MessageID)
+	assert.Len(t, s.LateCancellations("bar", now), 1)
+}
+
+func TestStore_MuteAlerts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.json")
+	s, err := Open(path)
+	assert.NoError(t, err)
+
+	assert.NoError(t, s.MuteAlerts("1", true))
+	assert.NoError(t, s.MuteAlerts("1", true))
+	assert.NoError(t, s.MuteAlerts("2", true))
+	assert.True(t, s.AlertsMuted("1"))
+
+	reopened, err := Open(path)
+	assert.NoError(t, err)
+	assert.True(t, reopened.AlertsMuted("2"))
+
+	assert.NoError(t, s.MuteAlerts("1", false))
+	assert.False(t, s.AlertsMuted("1"))
+	assert.True(t, s.AlertsMuted("2"))
+}
+
+func TestStore_Preferences(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.json")
+	s, err := Open(path)
+	assert.NoError(t, err)
+
+	assert.False(t, s.Preferences("1").Accessible)
+	assert.NoError(t, s.SetPreferences("1", Preferences{Accessible: true}))
+	assert.True(t, s.Preferences("1").Accessible)
+	assert.False(t, s.Preferences("2").Accessible)
+
+	reopened, err := Open(path)
+	assert.NoError(t, err)
+	assert.True(t, reopened.Preferences("1").Accessible)
+
+	var missing *Store
+	assert.False(t, missing.Preferences("1").Accessible)
+}
+
+func TestStore_Drafts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.json")
+	s, err := Open(path)
+	assert.NoError(t, err)
+	now := time.Now()
+
+	assert.NoError(t, s.SaveDraft(Draft{ID: "1", GuildID: "g", OwnerID: "u", Title: "Social", Saved: now.Add(time.Minute)}))
+	assert.NoError(t, s.SaveDraft(Draft{ID: "2", GuildID: "g", OwnerID: "u", Title: "Workshop", Saved: now}))
+	assert.NoError(t, s.SaveDraft(Draft{ID: "3", GuildID: "g", OwnerID: "v", Title: "Other", Saved: now}))
+	assert.NoError(t, s.SaveDraft(Draft{ID: "1", GuildID: "g", OwnerID: "u", Title: "Social night", Saved: now.Add(time.Hour)}))
+
+	reopened, err := Open(path)
+	assert.NoError(t, err)
+	got := reopened.Drafts("g", "u")
+	assert.Len(t, got, 2)
+	assert.Equal(t, "Workshop", got[0].Title)
+	assert.Equal(t, "Social night", got[1].Title)
+	assert.Empty(t, reopened.Drafts("h", "u"))
+
+	assert.NoError(t, reopened.DeleteDraft("2"))
+	assert.NoError(t, reopened.DeleteDraft("missing"))
+	assert.Len(t, reopened.Drafts("g", "u"), 1)
+
+	var missing *Store
+	assert.Empty(t, missing.Drafts("g", "u"))
+}
+
+func TestStore_ClaimDraft(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.json")
+	s, err := Open(path)
+	assert.NoError(t, err)
+	assert.NoError(t, s.SaveDraft(Draft{ID: "1", GuildID: "g", OwnerID: "u", Title: "Social"}))
+
+	// The publisher job and an organizer publishing by hand race for the same draft
+	const claimers = 8
+	var wg sync.WaitGroup
+	var claims atomic.Int32
+	for n := 0; n < claimers; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d, ok, err := s.ClaimDraft("1")
+			assert.NoError(t, err)
+			if ok {
+				claims.Add(1)
+				assert.Equal(t, "Social", d.Title)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), claims.Load())
+
+	reopened, err := Open(path)
+	assert.NoError(t, err)
+	_, ok := reopened.Draft("1")
+	assert.False(t, ok)
+	_, ok, err = reopened.ClaimDraft("1")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestStore_DueDrafts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.json")
+	s, err := Open(path)
+	assert.NoError(t, err)
+	now := time.Now()
+
+	assert.NoError(t, s.SaveDraft(Draft{ID: "1", Title: "Later", PublishAt: now.Add(time.Hour)}))
+	assert.NoError(t, s.SaveDraft(Draft{ID: "2", Title: "Due", PublishAt: now}))
+	assert.NoError(t, s.SaveDraft(Draft{ID: "3", Title: "Overdue", PublishAt: now.Add(-time.Hour)}))
+	assert.NoError(t, s.SaveDraft(Draft{ID: "4", Title: "Unscheduled"}))
+
+	reopened, err := Open(path)
+	assert.NoError(t, err)
+	got := reopened.DueDrafts(now)
+	assert.Len(t, got, 2)
+	assert.Equal(t, "Overdue", got[0].Title)
+	assert.Equal(t, "Due", got[1].Title)
+
+	d, ok := reopened.Draft("1")
+	assert.True(t, ok)
+	assert.True(t, d.Scheduled())
+	d, ok = reopened.Draft("4")
+	assert.True(t, ok)
+	assert.False(t, d.Scheduled())
+	_, ok = reopened.Draft("missing")
+	assert.False(t, ok)
+}
+
+func TestStore_Announcements(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.json")
+	s, err := Open(path)
+	assert.NoError(t, err)
+
+	assert.NoError(t, s.RecordAnnouncement(Announcement{MessageID: "1", Text: "We moved to room B"}))
+	assert.NoError(t, s.RecordAnnouncement(Announcement{MessageID: "2", Text: "Bring shoes"}))
+	assert.NoError(t, s.RecordAnnouncement(Announcement{MessageID: "1", Text: "Starting soon"}))
+
+	reopened, err := Open(path)
+	assert.NoError(t, err)
+	got := reopened.Announcements("1")
+	assert.Len(t, got, 2)
+	assert.Equal(t, "We moved to room B", got[0].Text)
+	assert.Empty(t, reopened.Announcements("3"))
+}
+
+func TestStore_MoveEvent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.json")
+	s, err := Open(path)
+	assert.NoError(t, err)
+	now := time.Date(2023, 6, 10, 18, 0, 0, 0, time.UTC)
+
+	assert.NoError(t, s.RecordAttendance(Attendance{ChannelID: "a", MessageID: "1", Title: "Board games"}))
+	assert.NoError(t, s.RecordLateCancellation(Cancellation{Name: "foo", MessageID: "1", At: now}))
+	assert.NoError(t, s.MuteAlerts("1", true))
+	assert.NoError(t, s.RecordAnnouncement(Announcement{ChannelID: "a", MessageID: "1", Text: "Bring shoes"}))
+	assert.NoError(t, s.RecordAnnouncement(Announcement{ChannelID: "a", MessageID: "2", Text: "Starting soon"}))
+
+	assert.NoError(t, s.MoveEvent("1", "b", "3"))
+
+	reopened, err := Open(path)
+	assert.NoError(t, err)
+	_, ok := reopened.Attendance("1")
+	assert.False(t, ok)
+	a, ok := reopened.Attendance("3")
+	assert.True(t, ok)
+	assert.Equal(t, "b", a.ChannelID)
+	assert.Equal(t, "Board games", a.Title)
+	assert.Equal(t, "3", reopened.LateCancellations("foo", now)[0].MessageID)
+	assert.False(t, reopened.AlertsMuted("1"))
+	assert.True(t, reopened.AlertsMuted("3"))
+	assert.Empty(t, reopened.Announcements("1"))
+	assert.Equal(t, "b", reopened.Announcements("3")[0].ChannelID)
+	assert.Len(t, reopened.Announcements("2"), 1)
+}
+
+func TestStore_Prune(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.json")
+	s, err := Open(path)
+	assert.NoError(t, err)
+	now := time.Date(2023, 6, 10, 18, 0, 0, 0, time.UTC)
+
+	assert.NoError(t, s.RecordLateCancellation(Cancellation{Name: "foo", MessageID: "1", At: now.Add(-400 * 24 * time.Hour)}))
+	assert.NoError(t, s.RecordLateCancellation(Cancellation{Name: "foo", MessageID: "2", At: now}))
+	assert.NoError(t, s.RecordAnnouncement(Announcement{MessageID: "1", Text: "Bring shoes", At: now.Add(-400 * 24 * time.Hour)}))
+	assert.NoError(t, s.RecordAnnouncement(Announcement{MessageID: "1", Text: "Starting soon", At: now}))
+	assert.NoError(t, s.RecordAttendance(Attendance{MessageID: "1", Title: "Board games"}))
+
+	assert.NoError(t, s.Prune(now.Add(-365*24*time.Hour)))
+
+	reopened, err := Open(path)
+	assert.NoError(t, err)
+	got := reopened.LateCancellations("foo", time.Time{})
+	assert.Len(t, got, 1)
+	assert.Equal(t, "2", got[0].MessageID)
+	announcements := reopened.Announcements("1")
+	assert.Len(t, announcements, 1)
+	assert.Equal(t, "Starting soon", announcements[0].Text)
+	_, ok := reopened.Attendance("1")
+	assert.True(t, ok)
+}
@@ -0,0 +1,535 @@
+// Package timeparse reads the dates, times and durations members type when scheduling an event, such as
+// "Sat 8pm-midnight", "friday at 9 until 11pm" or "in 1 hour".
+package timeparse
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNoTime is returned for a day without a time, such as "friday"
+	ErrNoTime = errors.New("no time of day")
+	// ErrEndBeforeStart is returned for ranges which end before they start
+	ErrEndBeforeStart = errors.New("end is not after start")
+)
+
+// maxInheritedRange is the longest range where a start time without am or pm takes it from the end, as in 7-10pm
+const maxInheritedRange = 12 * time.Hour
+
+// Range is an interpretation of what a member typed
+type Range struct {
+	Start time.Time
+	// End is zero when the input has no end
+	End time.Time
+	// Ambiguous is set when the input could be read more than one way, such as a time without am or pm or
+	// "next friday". The interpretation should be confirmed with the member.
+	Ambiguous bool
+}
+
+type dayKind int
+
+const (
+	dayNone dayKind = iota
+	dayToday
+	dayTomorrow
+	dayWeekday
+	dayDate
+)
+
+type meridiem string
+
+const (
+	unknown meridiem = ""
+	am      meridiem = "am"
+	pm      meridiem = "pm"
+)
+
+// clock is a time of day as typed
+type clock struct {
+	hour     int
+	minute   int
+	meridiem meridiem
+	// twentyFour is set for times such as 09:00 or 21:00 which cannot be am or pm
+	twentyFour bool
+	midnight   bool
+}
+
+// moment is a day and time as typed, before it is placed on the calendar
+type moment struct {
+	day     dayKind
+	weekday time.Weekday
+	next    bool // "next friday"
+	year    int
+	month   time.Month
+	date    int
+	// swappable is set for numeric dates like 6/5 which could also be read day first
+	swappable bool
+	clock     *clock
+	// period is the part of the day given with words such as "tonight" or "morning"
+	period meridiem
+	// in is a time from now such as "in 2 hours"
+	in time.Duration
+}
+
+var (
+	isoDateRegex     = regexp.MustCompile(`\b(\d{4})-(\d{1,2})-(\d{1,2})\b`)
+	dashRegex        = regexp.MustCompile(`\s*[-–—]\s*`)
+	spacedMeridiem   = regexp.MustCompile(`(\d)\s+(am|pm|a|p)\b`)
+	clockRegex       = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(am|pm|a|p)?$`)
+	numericDateRegex = regexp.MustCompile(`^(\d{1,4})/(\d{1,2})(?:/(\d{2,4}))?$`)
+	ordinalRegex     = regexp.MustCompile(`^(\d{1,2})(st|nd|rd|th)?$`)
+	durationRegex    = regexp.MustCompile(`(\d+(?:\.\d+)?\s*|\ban?\s+)([a-z]+)`)
+
+	rangeSeparators = []string{" - ", " to ", " until ", " till ", " til "}
+	fillerWords     = map[string]bool{"at": true, "on": true, "@": true, "from": true, "the": true, "of": true, "starting": true, "starts": true, "start": true}
+
+	weekdays = map[string]time.Weekday{
+		"sun": time.Sunday, "sunday": time.Sunday,
+		"mon": time.Monday, "monday": time.Monday,
+		"tue": time.Tuesday, "tues": time.Tuesday, "tuesday": time.Tuesday,
+		"wed": time.Wednesday, "weds": time.Wednesday, "wednesday": time.Wednesday,
+		"thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday, "thursday": time.Thursday,
+		"fri": time.Friday, "friday": time.Friday,
+		"sat": time.Saturday, "saturday": time.Saturday,
+	}
+	months = map[string]time.Month{
+		"jan": time.January, "january": time.January,
+		"feb": time.February, "february": time.February,
+		"mar": time.March, "march": time.March,
+		"apr": time.April, "april": time.April,
+		"may": time.May,
+		"jun": time.June, "june": time.June,
+		"jul": time.July, "july": time.July,
+		"aug": time.August, "august": time.August,
+		"sep": time.September, "sept": time.September, "september": time.September,
+		"oct": time.October, "october": time.October,
+		"nov": time.November, "november": time.November,
+		"dec": time.December, "december": time.December,
+	}
+	periods = map[string]meridiem{
+		"morning":   am,
+		"afternoon": pm,
+		"evening":   pm,
+		"night":     pm,
+	}
+	durationUnits = map[string]time.Duration{
+		"m": time.Minute, "min": time.Minute, "mins": time.Minute, "minute": time.Minute, "minutes": time.Minute,
+		"h": time.Hour, "hr": time.Hour, "hrs": time.Hour, "hour": time.Hour, "hours": time.Hour,
+		"d": 24 * time.Hour, "day": 24 * time.Hour, "days": 24 * time.Hour,
+		"w": 7 * 24 * time.Hour, "wk": 7 * 24 * time.Hour, "week": 7 * 24 * time.Hour, "weeks": 7 * 24 * time.Hour,
+	}
+)
+
+// Parse reads a start time with an optional end, such as "Sat 8pm-midnight" or "tomorrow 7 until 10pm". Times are
+// placed in the location of now, and a time without a day is the next time it comes around.
+func Parse(input string, now time.Time) (Range, error) {
+	text := normalize(input)
+	if text == "" {
+		return Range{}, fmt.Errorf("empty input")
+	}
+	if text == "now" {
+		return Range{Start: now}, nil
+	}
+	startText, endText := splitRange(text)
+	start, err := parseMoment(startText)
+	if err != nil {
+		return Range{}, err
+	}
+	startTime, ambiguous, err := start.resolve(now)
+	if err != nil {
+		return Range{}, err
+	}
+	if endText == "" {
+		return Range{Start: startTime, Ambiguous: ambiguous}, nil
+	}
+
+	// A start without am or pm takes it from the end, as in 7-10pm, or the opposite one, as in 11-1am
+	if end, err := parseMoment(endText); err == nil && start.needsMeridiem() && end.day == dayNone && end.clock != nil && end.clock.meridiem != unknown {
+		for _, m := range []meridiem{end.clock.meridiem, end.clock.meridiem.opposite()} {
+			candidate := start
+			c := *start.clock
+			c.meridiem = m
+			candidate.clock = &c
+			t, amb, err := candidate.resolve(now)
+			if err != nil {
+				continue
+			}
+			if e, err := ParseEnd(endText, t); err == nil && e.Sub(t) <= maxInheritedRange {
+				startTime, ambiguous = t, amb
+				break
+			}
+		}
+	}
+
+	end, err := ParseEnd(endText, startTime)
+	if err != nil {
+		return Range{}, err
+	}
+	return Range{Start: startTime, End: end, Ambiguous: ambiguous}, nil
+}
+
+// ParseEnd reads when an event starting at start ends. It takes a duration such as "2 hours" or "1h30m", or an end
+// time such as "until 11pm", "midnight" or "sun 2am". A time without a day is the first one after the start.
+func ParseEnd(input string, start time.Time) (time.Time, error) {
+	text := normalize(input)
+	// A dash before an end time continues a range, but before a duration it is a sign such as "-2 hours"
+	signed := strings.HasPrefix(text, "- ")
+	for _, prefix := range []string{"until ", "till ", "til ", "to ", "- ", "ends ", "ending ", "at "} {
+		text = strings.TrimPrefix(text, prefix)
+	}
+	if text == "" {
+		return time.Time{}, fmt.Errorf("empty input")
+	}
+	if d, ok := parseDuration(text); ok {
+		if signed || d <= 0 {
+			return time.Time{}, ErrEndBeforeStart
+		}
+		return start.Add(d), nil
+	}
+
+	m, err := parseMoment(text)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if m.in > 0 {
+		return start.Add(m.in), nil
+	}
+	if m.clock == nil {
+		return time.Time{}, ErrNoTime
+	}
+	base := startOfDay(start)
+	if m.day != dayNone {
+		var amb bool
+		base, amb, err = m.resolveDay(start)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if amb {
+			return time.Time{}, fmt.Errorf("ambiguous end date")
+		}
+	}
+	var end time.Time
+	for _, t := range m.times(base) {
+		if t.After(start) {
+			end = t
+			break
+		}
+	}
+	if end.IsZero() && m.day == dayNone {
+		end = m.times(base.AddDate(0, 0, 1))[0]
+	}
+	if !end.After(start) {
+		return time.Time{}, ErrEndBeforeStart
+	}
+	return end, nil
+}
+
+// normalize lowercases input and spaces it out so every part is its own word
+func normalize(input string) string {
+	text := strings.ToLower(strings.TrimSpace(input))
+	text = strings.NewReplacer("a.m.", "am", "p.m.", "pm", ",", " ", "!", "", "?", "").Replace(text)
+	text = strings.TrimSuffix(text, ".")
+	// ISO dates are rewritten so their dashes are not read as ranges
+	text = isoDateRegex.ReplaceAllString(text, "$1/$2/$3")
+	text = dashRegex.ReplaceAllString(text, " - ")
+	text = spacedMeridiem.ReplaceAllString(text, "$1$2")
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// splitRange separates the start from the end of a range at the first separator
+func splitRange(text string) (start, end string) {
+	index, length := -1, 0
+	for _, sep := range rangeSeparators {
+		if i := strings.Index(" "+text+" ", sep); i >= 0 && (index < 0 || i < index) {
+			index, length = i, len(sep)
+		}
+	}
+	if index < 0 {
+		return text, ""
+	}
+	padded := " " + text + " "
+	return strings.TrimSpace(padded[:index]), strings.TrimSpace(padded[index+length:])
+}
+
+// parseMoment reads a day and time such as "next fri 9pm", "june 5th at 7:30" or "in 2 hours"
+func parseMoment(text string) (moment, error) {
+	var m moment
+	if rest, ok := strings.CutPrefix(text, "in "); ok {
+		d, ok := parseDuration(rest)
+		if !ok || d <= 0 {
+			return m, fmt.Errorf("invalid duration: %s", rest)
+		}
+		m.in = d
+		return m, nil
+	}
+
+	words := strings.Fields(text)
+	for i := 0; i < len(words); i++ {
+		w := words[i]
+		switch {
+		case fillerWords[w]:
+		case w == "today":
+			m.day = dayToday
+		case w == "tonight":
+			m.day, m.period = dayToday, pm
+		case w == "tomorrow" || w == "tmrw" || w == "tmr":
+			m.day = dayTomorrow
+		case w == "next":
+			m.next = true
+		case w == "this":
+		case w == "noon":
+			m.clock = &clock{hour: 12, meridiem: pm}
+		case w == "midnight":
+			m.clock = &clock{midnight: true}
+		default:
+			if weekday, ok := weekdays[w]; ok {
+				m.day, m.weekday = dayWeekday, weekday
+				continue
+			}
+			if p, ok := periods[w]; ok {
+				m.period = p
+				continue
+			}
+			if month, ok := months[w]; ok {
+				m.day, m.month = dayDate, month
+				// The day of the month comes right after or right before the month
+				if i+1 < len(words) && ordinalRegex.MatchString(words[i+1]) {
+					m.date, _ = strconv.Atoi(ordinalRegex.FindStringSubmatch(words[i+1])[1])
+					i++
+				} else if m.date == 0 {
+					return m, fmt.Errorf("missing day of the month")
+				}
+				if i+1 < len(words) && len(words[i+1]) == 4 {
+					if year, err := strconv.Atoi(words[i+1]); err == nil {
+						m.year = year
+						i++
+					}
+				}
+				continue
+			}
+			if i+1 < len(words) && months[words[i+1]] != 0 && ordinalRegex.MatchString(w) {
+				m.date, _ = strconv.Atoi(ordinalRegex.FindStringSubmatch(w)[1])
+				continue
+			}
+			if match := numericDateRegex.FindStringSubmatch(w); match != nil {
+				if err := m.setNumericDate(match); err != nil {
+					return m, err
+				}
+				continue
+			}
+			if match := clockRegex.FindStringSubmatch(w); match != nil {
+				c, err := parseClock(match)
+				if err != nil {
+					return m, err
+				}
+				m.clock = c
+				continue
+			}
+			return m, fmt.Errorf("unknown word: %s", w)
+		}
+	}
+	if m.next && m.day != dayWeekday {
+		return m, fmt.Errorf("next must be followed by a weekday")
+	}
+	return m, nil
+}
+
+// setNumericDate reads 2024/06/05 year first or 6/5 and 6/5/2024 month first
+func (m *moment) setNumericDate(match []string) error {
+	first, _ := strconv.Atoi(match[1])
+	second, _ := strconv.Atoi(match[2])
+	m.day = dayDate
+	if len(match[1]) == 4 {
+		third, err := strconv.Atoi(match[3])
+		if err != nil {
+			return fmt.Errorf("invalid date")
+		}
+		m.year, m.month, m.date = first, time.Month(second), third
+	} else {
+		m.month, m.date = time.Month(first), second
+		m.swappable = first != second && first <= 12 && second <= 12
+		if match[3] != "" {
+			year, _ := strconv.Atoi(match[3])
+			if year < 100 {
+				year += 2000
+			}
+			m.year = year
+		}
+	}
+	if m.month < time.January || m.month > time.December || m.date < 1 || m.date > 31 {
+		return fmt.Errorf("invalid date")
+	}
+	return nil
+}
+
+func parseClock(match []string) (*clock, error) {
+	c := &clock{}
+	c.hour, _ = strconv.Atoi(match[1])
+	if match[2] != "" {
+		c.minute, _ = strconv.Atoi(match[2])
+	}
+	switch match[3] {
+	case "am", "a":
+		c.meridiem = am
+	case "pm", "p":
+		c.meridiem = pm
+	}
+	if c.minute > 59 || c.hour > 23 || (c.meridiem != unknown && (c.hour == 0 || c.hour > 12)) {
+		return nil, fmt.Errorf("invalid time: %s", match[0])
+	}
+	// 21:00 and 09:00 can only be read one way
+	c.twentyFour = c.meridiem == unknown && (c.hour == 0 || c.hour > 12 || (match[2] != "" && strings.HasPrefix(match[1], "0")))
+	return c, nil
+}
+
+func (m meridiem) opposite() meridiem {
+	if m == am {
+		return pm
+	}
+	return am
+}
+
+// needsMeridiem reports whether the time of a moment could be am or pm
+func (m moment) needsMeridiem() bool {
+	return m.clock != nil && !m.clock.midnight && !m.clock.twentyFour && m.clock.meridiem == unknown && m.clock.hour != 12
+}
+
+// times lists the possible times of a moment on a day, in the order they happen
+func (m moment) times(day time.Time) []time.Time {
+	c := m.clock
+	at := func(hour int) time.Time {
+		return time.Date(day.Year(), day.Month(), day.Day(), hour, c.minute, 0, 0, day.Location())
+	}
+	switch {
+	case c.midnight:
+		return []time.Time{at(24)}
+	case c.twentyFour:
+		return []time.Time{at(c.hour)}
+	case c.meridiem == am:
+		return []time.Time{at(c.hour % 12)}
+	case c.meridiem == pm:
+		return []time.Time{at(c.hour%12 + 12)}
+	case c.hour == 12:
+		return []time.Time{at(12)}
+	case m.period == am:
+		return []time.Time{at(c.hour)}
+	case m.period == pm:
+		return []time.Time{at(c.hour + 12)}
+	default:
+		return []time.Time{at(c.hour), at(c.hour + 12)}
+	}
+}
+
+// resolve places a moment on the calendar. Without a day, the next time it comes around is used.
+func (m moment) resolve(now time.Time) (time.Time, bool, error) {
+	if m.in > 0 {
+		return now.Add(m.in), false, nil
+	}
+	if m.clock == nil {
+		return time.Time{}, false, ErrNoTime
+	}
+	day, ambiguous, err := m.resolveDay(now)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	times := m.times(day)
+	// Midnight on a day is read as the end of it, but members may mean the night before
+	ambiguous = ambiguous || len(times) > 1 || (m.clock.midnight && m.day != dayNone)
+	for _, t := range times {
+		if t.After(now) {
+			return t, ambiguous, nil
+		}
+	}
+	switch m.day {
+	case dayNone:
+		return m.times(day.AddDate(0, 0, 1))[0], ambiguous, nil
+	case dayWeekday:
+		if !m.next {
+			return m.times(day.AddDate(0, 0, 7))[0], ambiguous, nil
+		}
+	}
+	return times[len(times)-1], ambiguous, nil
+}
+
+// resolveDay finds the day a moment is on, counting from now. Dates which do not exist, such as February 30, are
+// rejected rather than rolled over into the next month.
+func (m moment) resolveDay(now time.Time) (time.Time, bool, error) {
+	today := startOfDay(now)
+	switch m.day {
+	case dayTomorrow:
+		return today.AddDate(0, 0, 1), false, nil
+	case dayWeekday:
+		delta := (int(m.weekday) - int(now.Weekday()) + 7) % 7
+		if m.next {
+			// "next friday" is either the coming friday or the one after
+			if delta == 0 {
+				delta = 7
+			}
+			return today.AddDate(0, 0, delta), true, nil
+		}
+		return today.AddDate(0, 0, delta), false, nil
+	case dayDate:
+		if m.year != 0 {
+			day, ok := m.dateIn(m.year, now.Location())
+			if !ok {
+				return time.Time{}, false, fmt.Errorf("invalid date")
+			}
+			return day, m.swappable, nil
+		}
+		// Without a year, a date which already passed this year is next year
+		for _, year := range []int{now.Year(), now.Year() + 1} {
+			if day, ok := m.dateIn(year, now.Location()); ok && !day.Before(today) {
+				return day, m.swappable, nil
+			}
+		}
+		return time.Time{}, false, fmt.Errorf("invalid date")
+	default:
+		return today, false, nil
+	}
+}
+
+// dateIn places the date of a moment in a year, reporting false when that day does not exist
+func (m moment) dateIn(year int, loc *time.Location) (time.Time, bool) {
+	day := time.Date(year, m.month, m.date, 0, 0, 0, 0, loc)
+	return day, day.Month() == m.month && day.Day() == m.date
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// parseDuration reads a length of time such as "2 hours", "1h30m", "1 hour and 30 minutes" or "an hour"
+func parseDuration(text string) (time.Duration, bool) {
+	text = strings.ReplaceAll(text, "half an hour", "30 minutes")
+	matches := durationRegex.FindAllStringSubmatch(text, -1)
+	if len(matches) == 0 {
+		return 0, false
+	}
+	rest := durationRegex.ReplaceAllString(text, "")
+	for _, w := range strings.Fields(rest) {
+		if w != "and" {
+			return 0, false
+		}
+	}
+	var total time.Duration
+	for _, match := range matches {
+		unit, ok := durationUnits[match[2]]
+		if !ok {
+			return 0, false
+		}
+		amount := 1.0
+		if n := strings.TrimSpace(match[1]); n != "a" && n != "an" {
+			var err error
+			if amount, err = strconv.ParseFloat(n, 64); err != nil {
+				return 0, false
+			}
+		}
+		total += time.Duration(amount * float64(unit))
+	}
+	return total, true
+}
@@ -0,0 +1,241 @@
+package timeparse
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	// Saturday
+	now := time.Date(2023, 6, 10, 18, 0, 0, 0, time.UTC)
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2023, 6, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	cases := []struct {
+		input    string
+		expected Range
+		err      bool
+	}{
+		{
+			input:    "now",
+			expected: Range{Start: now},
+		},
+		{
+			input:    "in 1 hour",
+			expected: Range{Start: now.Add(time.Hour)},
+		},
+		{
+			input:    "in an hour and 30 minutes",
+			expected: Range{Start: now.Add(90 * time.Minute)},
+		},
+		{
+			input:    "tomorrow at 10:15am",
+			expected: Range{Start: at(11, 10, 15)},
+		},
+		{
+			input:    "Friday at 9pm",
+			expected: Range{Start: at(16, 21, 0)},
+		},
+		{
+			input:    "tonight 8",
+			expected: Range{Start: at(10, 20, 0)},
+		},
+		{
+			input:    "21:00",
+			expected: Range{Start: at(10, 21, 0)},
+		},
+		{
+			input:    "5pm",
+			expected: Range{Start: at(11, 17, 0)},
+		},
+		{
+			input:    "sat 5pm",
+			expected: Range{Start: at(17, 17, 0)},
+		},
+		{
+			input:    "2023-06-20 7:00 PM",
+			expected: Range{Start: at(20, 19, 0)},
+		},
+		{
+			input:    "June 20th at 7:30 p.m.",
+			expected: Range{Start: at(20, 19, 30)},
+		},
+		{
+			input:    "Sat 8pm–midnight",
+			expected: Range{Start: at(10, 20, 0), End: at(11, 0, 0)},
+		},
+		{
+			input:    "7-10pm",
+			expected: Range{Start: at(10, 19, 0), End: at(10, 22, 0)},
+		},
+		{
+			input:    "11-1am",
+			expected: Range{Start: at(10, 23, 0), End: at(11, 1, 0)},
+		},
+		{
+			input:    "friday at 9 until 11pm",
+			expected: Range{Start: at(16, 21, 0), End: at(16, 23, 0)},
+		},
+		{
+			input:    "tomorrow 2pm to 2 hours",
+			expected: Range{Start: at(11, 14, 0), End: at(11, 16, 0)},
+		},
+		{
+			input:    "8",
+			expected: Range{Start: at(10, 20, 0), Ambiguous: true},
+		},
+		{
+			input:    "next friday 9pm",
+			expected: Range{Start: at(16, 21, 0), Ambiguous: true},
+		},
+		{
+			input:    "6/5 7pm",
+			expected: Range{Start: time.Date(2024, 6, 5, 19, 0, 0, 0, time.UTC), Ambiguous: true},
+		},
+		{
+			input:    "friday midnight",
+			expected: Range{Start: at(17, 0, 0), Ambiguous: true},
+		},
+		{
+			input: "friday",
+			err:   true,
+		},
+		{
+			input: "next 9pm",
+			err:   true,
+		},
+		{
+			input: "13pm",
+			err:   true,
+		},
+		{
+			input: "whenever",
+			err:   true,
+		},
+		{
+			input: "feb 30 8pm",
+			err:   true,
+		},
+		{
+			input: "4/31 7pm",
+			err:   true,
+		},
+		{
+			input: "2023-02-29 7pm",
+			err:   true,
+		},
+		{
+			input:    "feb 29 7pm",
+			expected: Range{Start: time.Date(2024, 2, 29, 19, 0, 0, 0, time.UTC)},
+		},
+		{
+			input: "",
+			err:   true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.input, func(t *testing.T) {
+			actual, err := Parse(tc.input, now)
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestParseEnd(t *testing.T) {
+	start := time.Date(2023, 6, 10, 20, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		input    string
+		expected time.Time
+		err      bool
+	}{
+		{
+			input:    "2 hours",
+			expected: start.Add(2 * time.Hour),
+		},
+		{
+			input:    "45 minutes",
+			expected: start.Add(45 * time.Minute),
+		},
+		{
+			input:    "1 hour and 30 minutes",
+			expected: start.Add(90 * time.Minute),
+		},
+		{
+			input:    "1h30m",
+			expected: start.Add(90 * time.Minute),
+		},
+		{
+			input:    "1.5 hours",
+			expected: start.Add(90 * time.Minute),
+		},
+		{
+			input:    "an hour",
+			expected: start.Add(time.Hour),
+		},
+		{
+			input:    "until 11pm",
+			expected: start.Add(3 * time.Hour),
+		},
+		{
+			input:    "until 1am",
+			expected: start.Add(5 * time.Hour),
+		},
+		{
+			input:    "11",
+			expected: start.Add(3 * time.Hour),
+		},
+		{
+			input:    "midnight",
+			expected: start.Add(4 * time.Hour),
+		},
+		{
+			input:    "sun 2am",
+			expected: start.Add(6 * time.Hour),
+		},
+		{
+			input: "0 hours",
+			err:   true,
+		},
+		{
+			input: "-2 hours",
+			err:   true,
+		},
+		{
+			input: "+2 hours",
+			err:   true,
+		},
+		{
+			input:    "- 11pm",
+			expected: start.Add(3 * time.Hour),
+		},
+		{
+			input: "sat 7pm",
+			err:   true,
+		},
+		{
+			input: "none",
+			err:   true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.input, func(t *testing.T) {
+			actual, err := ParseEnd(tc.input, start)
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
@@ -70,6 +70,41 @@ func PrintGoogleCalendarEventLink(ID string) string {
 	return fmt.Sprintf("[View in Calendar](%s)", u.String())
 }
 
+// PrintScheduledEventLink prints a link to a Discord guild scheduled event
+func PrintScheduledEventLink(guildID, eventID string) string {
+	return fmt.Sprintf("[View in Discord](https://discord.com/events/%s/%s)", guildID, eventID)
+}
+
+// PrintFooter prints the event organizer
+func PrintFooter(owner string) string {
+	return fmt.Sprintf("Created by %v", owner)
+}
+
+// PrintSignups prints when signups for an event close and whether they were locked by an organizer
+func PrintSignups(deadline time.Time, locked bool) string {
+	var lines []string
+	if locked {
+		lines = append(lines, LockedText)
+	}
+	if !deadline.IsZero() {
+		lines = append(lines, fmt.Sprintf("Close <t:%d:F> (<t:%d:R>)", deadline.Unix(), deadline.Unix()))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// PrintGuests prints a username followed by the number of guests they are bringing, if any
+func PrintGuests(name string, guests int) string {
+	if guests == 0 {
+		return name
+	}
+	return fmt.Sprintf("%s (+%d)", name, guests)
+}
+
+// PrintGuestLimit prints the maximum number of guests each member can bring to an event
+func PrintGuestLimit(limit int) string {
+	return fmt.Sprintf("Up to %d per member", limit)
+}
+
 func PrintGoogleCalendarDescription(description string, discordLink string) string {
 	result := description + fmt.Sprintf("\n%s\n%s", LineFeed, discordLink)
 	return result
@@ -7,15 +7,26 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 )
 
 var (
-	linkRegex        = regexp.MustCompile(`^\[[^][]+]\((https?://[^()]+)\)$`)
-	headCountRegex   = regexp.MustCompile(`\(([[:digit:]]+)\/?([[:digit:]]+)?\)$`)
-	footerRegex      = regexp.MustCompile(`^Created by (.+)$`)
-	inputSelectRegex = regexp.MustCompile(`^\d+(?: \d+)*$`)
+	linkRegex         = regexp.MustCompile(`^\[[^][]+]\((https?://[^()]+)\)$`)
+	headCountRegex    = regexp.MustCompile(`\(([[:digit:]]+)\/?([[:digit:]]+)?\)$`)
+	footerRegex       = regexp.MustCompile(`^Created by (.+?)(?: · rev (\d+))?$`)
+	inputSelectRegex  = regexp.MustCompile(`^\d+(?: \d+)*$`)
+	discordLinkRegex  = regexp.MustCompile(`https://discord(?:app)?\.com/channels/\d+/\d+/\d+`)
+	eventLinkRegex    = regexp.MustCompile(`^\[[^][]+]\(https://discord\.com/events/(\d+)/(\d+)\)$`)
+	deadlineRegex     = regexp.MustCompile(`<t:(-?\d+):F>`)
+	offsetBeforeRegex = regexp.MustCompile(`^(\d+)\s*(minute|min|hour|hr|day|week)s?\s+before$`)
+	guestsRegex       = regexp.MustCompile(`^(.+) \(\+(\d+)\)$`)
+	guestLimitRegex   = regexp.MustCompile(`^Up to (\d+) per member$`)
+	channelRegex      = regexp.MustCompile(`^<#(\d+)>$`)
 
 	LineFeed = "ლ(´ڡ`ლ)"
+	// LockedText marks events whose signups were locked by an organizer
+	LockedText = "🔒 Locked"
 )
 
 // GetLinkFromDeleteDescription extracts a message link from a delete handler
@@ -43,6 +54,31 @@ func GetIDsFromDiscordLink(link string) (guildID string, channelID string, messa
 	return pathSlice[l-3], pathSlice[l-2], pathSlice[l-1], err
 }
 
+// FindDiscordLink finds the first Discord message link in text, if any
+func FindDiscordLink(text string) string {
+	return discordLinkRegex.FindString(text)
+}
+
+// RemoveDiscordLink removes Discord message links from text
+func RemoveDiscordLink(text string) string {
+	return strings.TrimSpace(discordLinkRegex.ReplaceAllString(text, ""))
+}
+
+// AppendDiscordLink appends a Discord message link to text while staying within a character limit
+func AppendDiscordLink(text, link string, limit int) string {
+	if text == "" {
+		return link
+	}
+	max := limit - utf8.RuneCountInString(link) - 2
+	if max <= 0 {
+		return link
+	}
+	if runes := []rune(text); len(runes) > max {
+		text = string(runes[:max])
+	}
+	return text + "\n\n" + link
+}
+
 // GetDiscordLinkFromCalendarDescription gets the Discord link from a Google calendar event description
 func GetDiscordLinkFromCalendarDescription(description string) (string, error) {
 	result := strings.Split(description, LineFeed)
@@ -136,14 +172,61 @@ func NameListToValues(list []string) string {
 	return strings.Join(result, "\n")
 }
 
+// GetUserFromFooter gets the event organizer from the footer. Messages edited by earlier versions of the bot may also
+// show a revision after the organizer.
 func GetUserFromFooter(footText string) string {
 	match := footerRegex.FindStringSubmatch(footText)
-	if len(match) != 2 {
+	if len(match) != 3 {
 		return ""
 	}
 	return match[1]
 }
 
+// ParseSignups gets the deadline and lock state from the signups field of an event
+func ParseSignups(value string) (deadline time.Time, locked bool, err error) {
+	locked = strings.Contains(value, LockedText)
+	result := deadlineRegex.FindStringSubmatch(value)
+	if len(result) != 2 {
+		return time.Time{}, locked, nil
+	}
+	unix, err := strconv.ParseInt(result[1], 10, 64)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return time.Unix(unix, 0), locked, nil
+}
+
+// ParseGuests splits a listed user such as "alice (+2)" into the username and their number of guests
+func ParseGuests(value string) (name string, guests int) {
+	result := guestsRegex.FindStringSubmatch(value)
+	if len(result) != 3 {
+		return value, 0
+	}
+	guests, err := strconv.Atoi(result[2])
+	if err != nil {
+		return value, 0
+	}
+	return result[1], guests
+}
+
+// ParseGuestLimit gets the maximum number of guests per member from the guests field of an event
+func ParseGuestLimit(value string) (int, error) {
+	result := guestLimitRegex.FindStringSubmatch(value)
+	if len(result) != 2 {
+		return 0, fmt.Errorf("cannot parse guest limit: %s", value)
+	}
+	return strconv.Atoi(result[1])
+}
+
+// ParseChannelMention gets the channel ID from a channel mention
+func ParseChannelMention(value string) (string, error) {
+	result := channelRegex.FindStringSubmatch(value)
+	if len(result) != 2 {
+		return "", fmt.Errorf("cannot parse channel: %s", value)
+	}
+	return result[1], nil
+}
+
 // IsInputOption validates input for one or more choices
 func IsInputOption(input string) bool {
 	result := inputSelectRegex.FindStringSubmatch(input)
@@ -179,6 +262,20 @@ func ParseEventID(link string) (string, error) {
 	return id, nil
 }
 
+// IsScheduledEventLink checks if a markdown link points to a Discord guild scheduled event
+func IsScheduledEventLink(link string) bool {
+	return eventLinkRegex.MatchString(link)
+}
+
+// ParseScheduledEventLink parses the guild ID and scheduled event ID from a Discord event link
+func ParseScheduledEventLink(link string) (guildID, eventID string, err error) {
+	result := eventLinkRegex.FindStringSubmatch(link)
+	if len(result) != 3 {
+		return "", "", fmt.Errorf("invalid scheduled event link")
+	}
+	return result[1], result[2], nil
+}
+
 // DecodeToGoogleEventID converts a base64 encoded ID to an event ID and calendar ID
 func DecodeToGoogleEventID(encodedID string) (eid, calendarID string, err error) {
 	result, err := base64.RawStdEncoding.DecodeString(encodedID)
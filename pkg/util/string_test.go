@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 func TestGetLinkFromDeleteDescription(t *testing.T) {
@@ -297,6 +298,11 @@ func TestGetUserFromFooter(t *testing.T) {
 			input:    "Created by Weirdo ハロー・ワールド",
 			expected: "Weirdo ハロー・ワールド",
 		},
+		{
+			name:     "with revision",
+			input:    "Created by a funky dude · rev 12",
+			expected: "a funky dude",
+		},
 	}
 
 	for _, tc := range cases {
@@ -306,6 +312,94 @@ func TestGetUserFromFooter(t *testing.T) {
 	}
 }
 
+func TestParseSignups(t *testing.T) {
+	deadline := time.Date(2023, 6, 10, 18, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name             string
+		input            string
+		expectedDeadline time.Time
+		expectedLocked   bool
+	}{
+		{
+			name:             "deadline",
+			input:            PrintSignups(deadline, false),
+			expectedDeadline: deadline,
+		},
+		{
+			name:           "locked",
+			input:          PrintSignups(time.Time{}, true),
+			expectedLocked: true,
+		},
+		{
+			name:             "locked with deadline",
+			input:            PrintSignups(deadline, true),
+			expectedDeadline: deadline,
+			expectedLocked:   true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, locked, err := ParseSignups(tc.input)
+			assert.NoError(t, err)
+			assert.True(t, tc.expectedDeadline.Equal(actual))
+			assert.Equal(t, tc.expectedLocked, locked)
+		})
+	}
+}
+
+func TestParseGuests(t *testing.T) {
+	cases := []struct {
+		name           string
+		input          string
+		expectedName   string
+		expectedGuests int
+	}{
+		{
+			name:         "no guests",
+			input:        "alice",
+			expectedName: "alice",
+		},
+		{
+			name:           "guests",
+			input:          PrintGuests("alice", 2),
+			expectedName:   "alice",
+			expectedGuests: 2,
+		},
+		{
+			name:         "parentheses in name",
+			input:        "alice (bob)",
+			expectedName: "alice (bob)",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			name, guests := ParseGuests(tc.input)
+			assert.Equal(t, tc.expectedName, name)
+			assert.Equal(t, tc.expectedGuests, guests)
+		})
+	}
+}
+
+func TestParseGuestLimit(t *testing.T) {
+	limit, err := ParseGuestLimit(PrintGuestLimit(3))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, limit)
+
+	_, err = ParseGuestLimit("invalid")
+	assert.Error(t, err)
+}
+
+func TestParseChannelMention(t *testing.T) {
+	channelID, err := ParseChannelMention("<#1234>")
+	assert.NoError(t, err)
+	assert.Equal(t, "1234", channelID)
+
+	_, err = ParseChannelMention("#general")
+	assert.Error(t, err)
+}
+
 func TestIsInputOption(t *testing.T) {
 	cases := []struct {
 		name     string
@@ -376,3 +470,113 @@ func TestDecodeToGoogleEventID(t *testing.T) {
 		})
 	}
 }
+
+func TestParseScheduledEventLink(t *testing.T) {
+	cases := []struct {
+		name            string
+		input           string
+		expectedGuildID string
+		expectedEventID string
+		isErr           bool
+	}{
+		{
+			name:            "valid",
+			input:           "[View in Discord](https://discord.com/events/123/456)",
+			expectedGuildID: "123",
+			expectedEventID: "456",
+		},
+		{
+			name:  "calendar link",
+			input: "[View in Calendar](https://www.google.com/calendar/event?eid=abc)",
+			isErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, !tc.isErr, IsScheduledEventLink(tc.input))
+			guildID, eventID, err := ParseScheduledEventLink(tc.input)
+			if tc.isErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedGuildID, guildID)
+			assert.Equal(t, tc.expectedEventID, eventID)
+		})
+	}
+}
+
+func TestFindDiscordLink(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "link only",
+			input:    "https://discord.com/channels/1/2/3",
+			expected: "https://discord.com/channels/1/2/3",
+		},
+		{
+			name:     "link after description",
+			input:    "Bring shoes\n\nhttps://discord.com/channels/1/2/3",
+			expected: "https://discord.com/channels/1/2/3",
+		},
+		{
+			name:     "no link",
+			input:    "Bring shoes",
+			expected: "",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, FindDiscordLink(tc.input))
+		})
+	}
+}
+
+func TestRemoveDiscordLink(t *testing.T) {
+	got := RemoveDiscordLink("Bring shoes\n\nhttps://discord.com/channels/1/2/3")
+	assert.Equal(t, "Bring shoes", got)
+}
+
+func TestAppendDiscordLink(t *testing.T) {
+	cases := []struct {
+		name     string
+		text     string
+		limit    int
+		expected string
+	}{
+		{
+			name:     "empty text",
+			text:     "",
+			limit:    1000,
+			expected: "https://discord.com/channels/1/2/3",
+		},
+		{
+			name:     "within limit",
+			text:     "Bring shoes",
+			limit:    1000,
+			expected: "Bring shoes\n\nhttps://discord.com/channels/1/2/3",
+		},
+		{
+			name:     "truncated",
+			text:     "Bring shoes",
+			limit:    40,
+			expected: "Brin\n\nhttps://discord.com/channels/1/2/3",
+		},
+		{
+			name:     "truncated by character",
+			text:     "ééééé",
+			limit:    40,
+			expected: "éééé\n\nhttps://discord.com/channels/1/2/3",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := AppendDiscordLink(tc.text, "https://discord.com/channels/1/2/3", tc.limit)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
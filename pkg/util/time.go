@@ -1,7 +1,42 @@
 package util
 
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
 const (
 	GoogleCalendarTimeFormat string = "20060102T150405Z"
 	HumanTimeFormat          string = "Mon Jan 2, 2006 3:04 pm MST"
 	StaticLocation           string = "America/Los_Angeles"
 )
+
+var offsetUnits = map[string]time.Duration{
+	"minute": time.Minute,
+	"min":    time.Minute,
+	"hour":   time.Hour,
+	"hr":     time.Hour,
+	"day":    24 * time.Hour,
+	"week":   7 * 24 * time.Hour,
+}
+
+// ParseOffsetBefore parses input such as "2 hours before" into the time that long before ref
+func ParseOffsetBefore(input string, ref time.Time) (time.Time, bool) {
+	result := offsetBeforeRegex.FindStringSubmatch(strings.ToLower(strings.TrimSpace(input)))
+	if len(result) != 3 {
+		return time.Time{}, false
+	}
+	n, err := strconv.Atoi(result[1])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return ref.Add(-time.Duration(n) * offsetUnits[result[2]]), true
+}
+
+// StartOfWeek returns midnight on the Monday of the week a time falls in
+func StartOfWeek(t time.Time) time.Time {
+	days := (int(t.Weekday()) + 6) % 7
+	y, m, d := t.AddDate(0, 0, -days).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
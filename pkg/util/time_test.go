@@ -0,0 +1,75 @@
+package util
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestParseOffsetBefore(t *testing.T) {
+	ref := time.Date(2023, 6, 10, 18, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		input    string
+		expected time.Time
+		ok       bool
+	}{
+		{
+			input:    "2 hours before",
+			expected: ref.Add(-2 * time.Hour),
+			ok:       true,
+		},
+		{
+			input:    "30 min before",
+			expected: ref.Add(-30 * time.Minute),
+			ok:       true,
+		},
+		{
+			input:    "1 Day Before",
+			expected: ref.Add(-24 * time.Hour),
+			ok:       true,
+		},
+		{
+			input:    "1 week before",
+			expected: ref.Add(-7 * 24 * time.Hour),
+			ok:       true,
+		},
+		{
+			input: "tomorrow at 5pm",
+		},
+		{
+			input: "2 hours",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.input, func(t *testing.T) {
+			actual, ok := ParseOffsetBefore(tc.input, ref)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestStartOfWeek(t *testing.T) {
+	monday := time.Date(2023, 6, 5, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name  string
+		input time.Time
+	}{
+		{
+			name:  "monday",
+			input: monday.Add(9 * time.Hour),
+		},
+		{
+			name:  "sunday",
+			input: time.Date(2023, 6, 11, 23, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, monday, StartOfWeek(tc.input))
+		})
+	}
+}